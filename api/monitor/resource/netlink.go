@@ -0,0 +1,57 @@
+package resource
+
+import "encoding/json"
+
+// The types of the netlink messages of a NetlinkEvent.
+const (
+	NetlinkNewLink  = "RTM_NEWLINK"
+	NetlinkDelLink  = "RTM_DELLINK"
+	NetlinkNewRoute = "RTM_NEWROUTE"
+	NetlinkDelRoute = "RTM_DELROUTE"
+)
+
+// NetlinkEvent is an event of the netlink resource. Link events have a Link,
+// and route events a Route.
+type NetlinkEvent struct {
+	// Type is the type of the netlink message, e.g. RTM_NEWLINK.
+	Type  string        `json:"type"`
+	Link  *NetlinkLink  `json:"link,omitempty"`
+	Route *NetlinkRoute `json:"route,omitempty"`
+}
+
+// NetlinkLink is the link of a RTM_NEWLINK or RTM_DELLINK event.
+type NetlinkLink struct {
+	Index int    `json:"index"`
+	Name  string `json:"name"`
+	// Kind is the kind of the link, e.g. device for physical interfaces like
+	// ENIs, or veth.
+	Kind         string `json:"kind"`
+	HardwareAddr string `json:"hardwareAddr,omitempty"`
+	// OperState is the operational state of the link as in RFC 2863, e.g. up
+	// or down.
+	OperState string `json:"operState"`
+	Loopback  bool   `json:"loopback,omitempty"`
+	// Registered is set on the RTM_NEWLINK event of a link that was just
+	// created, like an ENI that was attached, rather than changed.
+	Registered bool `json:"registered,omitempty"`
+}
+
+// NetlinkRoute is the route of a RTM_NEWROUTE or RTM_DELROUTE event.
+type NetlinkRoute struct {
+	// Family is the address family of the route, inet or inet6.
+	Family string `json:"family"`
+	Table  int    `json:"table"`
+	// Dst is the destination prefix of the route, or default.
+	Dst     string `json:"dst"`
+	Gateway string `json:"gateway,omitempty"`
+	// Dev is the name of the link of the route, when it still exists.
+	Dev       string `json:"dev,omitempty"`
+	LinkIndex int    `json:"linkIndex,omitempty"`
+}
+
+// ParseNetlinkEvent parses an event of the netlink resource.
+func ParseNetlinkEvent(event string) (NetlinkEvent, error) {
+	var netlinkEvent NetlinkEvent
+	err := json.Unmarshal([]byte(event), &netlinkEvent)
+	return netlinkEvent, err
+}
@@ -13,4 +13,7 @@ const (
 	// The journal resource.
 	// It has one part that is the name of the systemd unit.
 	ResourceTypeJournal Type = "journal"
+	// The netlink resource, the link and route events of the host.
+	// It has no parts. Each event is a NetlinkEvent encoded as JSON.
+	ResourceTypeNetlink Type = "netlink"
 )
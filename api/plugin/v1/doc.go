@@ -0,0 +1,5 @@
+// Package pluginv1 contains the versioned gRPC API served to out-of-process
+// monitor plugins. The Go code in this package is generated from plugin.proto.
+package pluginv1
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative plugin.proto
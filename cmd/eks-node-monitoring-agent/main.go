@@ -8,7 +8,9 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"reflect"
 	"slices"
+	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -47,6 +49,8 @@ import (
 	_ "github.com/aws/eks-node-monitoring-agent/monitors/storage"
 
 	// Import monitors that require explicit registration (can't use init())
+	"github.com/aws/eks-node-monitoring-agent/monitors/custom"
+	"github.com/aws/eks-node-monitoring-agent/monitors/remote"
 	"github.com/aws/eks-node-monitoring-agent/monitors/runtime"
 	// Import observer packages to register observers
 	_ "github.com/aws/eks-node-monitoring-agent/pkg/observer"
@@ -228,10 +232,36 @@ func run() error {
 			logger.Info("monitor config file not found, all monitors will be enabled by default", "path", config.DefaultConfigPath)
 		}
 
+		// Register the custom plugin monitor manually, since its monitors are
+		// built from the configured checks.
+		if checks := monitorConfig.GetCustomChecks(); len(checks) > 0 {
+			concurrency, maxOutputBytes := monitorConfig.GetCustomCheckLimits()
+			if err := registry.ValidateAndRegister(custom.NewPlugin(checks, concurrency, maxOutputBytes)); err != nil {
+				logger.Error(err, "failed to register custom plugin monitor")
+				return err
+			}
+		}
+
+		// Register a plugin for each configured out-of-process plugin. These
+		// are served over the plugin socket once monitor registration is done.
+		var remoteServer *remote.Server
+		if remotePlugins := monitorConfig.GetRemotePlugins(); len(remotePlugins) > 0 && monitorConfig.IsMonitorEnabled(config.RemotePluginMonitorName) {
+			remoteServer = remote.NewServer(config.ToHostPath(monitorConfig.GetRemotePluginSocketPath()), remotePlugins)
+			for _, plugin := range remoteServer.Plugins() {
+				if err := registry.ValidateAndRegister(plugin); err != nil {
+					logger.Error(err, "failed to register remote plugin", "plugin", plugin.Name())
+					return err
+				}
+			}
+		}
+
 		// Filter plugins by configuration and log effective state
 		allPlugins := registry.GlobalRegistry().List()
 		var enabledMonitors []monitor.Monitor
 		var disabledNames []string
+		// monitorPlugins maps each enabled monitor to the configuration key of
+		// the plugin that provides it.
+		monitorPlugins := map[string]string{}
 
 		for _, plugin := range allPlugins {
 			enabled := monitorConfig.IsMonitorEnabled(plugin.Name())
@@ -241,6 +271,15 @@ func run() error {
 				continue
 			}
 			enabledMonitors = append(enabledMonitors, plugin.Monitors()...)
+			for _, mon := range plugin.Monitors() {
+				// remote plugins are configured together under the
+				// remote-plugin-monitor.
+				if strings.HasPrefix(plugin.Name(), remote.PluginNamePrefix) {
+					monitorPlugins[mon.Name()] = config.RemotePluginMonitorName
+				} else {
+					monitorPlugins[mon.Name()] = plugin.Name()
+				}
+			}
 		}
 
 		if len(disabledNames) > 0 {
@@ -275,6 +314,42 @@ func run() error {
 			}
 		}
 
+		if threshold := monitorConfig.GetEBSLatencyThreshold(); threshold != config.DefaultEBSLatencyThreshold {
+			for _, mon := range enabledMonitors {
+				type ebsLatencyConfigurable interface {
+					SetEBSLatencyThreshold(time.Duration)
+				}
+				if c, ok := mon.(ebsLatencyConfigurable); ok {
+					c.SetEBSLatencyThreshold(threshold)
+					logger.Info("configured EBS latency threshold", "monitor", mon.Name(), "threshold", threshold)
+				}
+			}
+		}
+
+		if names := monitorConfig.GetDNSProbeNames(); !slices.Equal(names, config.DefaultDNSProbeNames) {
+			for _, mon := range enabledMonitors {
+				type dnsProbeConfigurable interface {
+					SetDNSProbeNames([]string)
+				}
+				if c, ok := mon.(dnsProbeConfigurable); ok {
+					c.SetDNSProbeNames(names)
+					logger.Info("configured DNS probe names", "monitor", mon.Name(), "names", names)
+				}
+			}
+		}
+
+		if probes := monitorConfig.GetConnectivityProbes(); !reflect.DeepEqual(probes, config.DefaultConnectivityProbes) {
+			for _, mon := range enabledMonitors {
+				type connectivityProbeConfigurable interface {
+					SetConnectivityProbes([]config.ConnectivityProbe)
+				}
+				if c, ok := mon.(connectivityProbeConfigurable); ok {
+					c.SetConnectivityProbes(probes)
+					logger.Info("configured connectivity probes", "monitor", mon.Name(), "probes", len(probes))
+				}
+			}
+		}
+
 		if len(enabledMonitors) == 0 {
 			logger.Info("all monitors are disabled by configuration, NMA will not perform any monitoring")
 		} else {
@@ -371,7 +446,20 @@ func run() error {
 				}
 				conditionType = conditions.AcceleratedHardwareReady
 			default:
-				conditionType = conditions.KernelReady // Default fallback
+				type conditionTyped interface {
+					ConditionType() corev1.NodeConditionType
+				}
+				if c, ok := mon.(conditionTyped); ok {
+					conditionType = c.ConditionType()
+				} else {
+					conditionType = conditions.KernelReady // Default fallback
+				}
+			}
+			if pluginName := monitorPlugins[mon.Name()]; monitorConfig.HasShadowMode(pluginName) {
+				monitorMgr.SetShadowMode(mon.Name(), func(reason string) bool {
+					return monitorConfig.IsShadowed(pluginName, reason)
+				})
+				logger.Info("monitor has conditions in shadow mode", "monitor", mon.Name())
 			}
 			if err := monitorMgr.Register(monCtx, mon, conditionType); err != nil {
 				logger.Error(err, "failed to register monitor", "name", mon.Name())
@@ -380,6 +468,14 @@ func run() error {
 			logger.Info("registered monitor with manager", "name", mon.Name(), "conditionType", conditionType)
 		}
 
+		if remoteServer != nil {
+			go func() {
+				if err := remoteServer.Serve(ctx); err != nil {
+					logger.Error(err, "remote plugin server failed")
+				}
+			}()
+		}
+
 		close(registered)
 
 		return monitorMgr.Start(ctx)
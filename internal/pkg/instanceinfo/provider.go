@@ -11,6 +11,7 @@ import (
 	"io"
 	"sync"
 
+	"github.com/aws/amazon-vpc-cni-k8s/pkg/vpc"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/feature/ec2/imds"
 )
@@ -29,6 +30,11 @@ var ErrUnknownInstanceType = errors.New("unknown instance type")
 type InstanceInfo struct {
 	InstanceType   string `json:"instanceType"`
 	NvidiaGPUCount uint   `json:"nvidiaGpuCount"`
+	// MaximumNetworkInterfaces is the number of network interfaces of the
+	// default network card. It is read from the limits of the VPC CNI rather
+	// than the embedded lookup table.
+	MaximumNetworkInterfaces  int `json:"-"`
+	IPv4AddressesPerInterface int `json:"-"`
 }
 
 // InstanceTypeInfoProvider returns hardware information about the current EC2 instance type.
@@ -72,7 +78,7 @@ func (p *ec2InstanceTypeInfoProvider) GetInstanceInfo(ctx context.Context) (*Ins
 		return nil, err
 	}
 
-	info, ok := p.embeddedLookup[instanceType]
+	info, ok := lookupInstanceInfo(p.embeddedLookup, instanceType)
 	if !ok {
 		return nil, fmt.Errorf("%w: %s", ErrUnknownInstanceType, instanceType)
 	}
@@ -81,6 +87,19 @@ func (p *ec2InstanceTypeInfoProvider) GetInstanceInfo(ctx context.Context) (*Ins
 	return p.info, nil
 }
 
+// lookupInstanceInfo merges the embedded info of the instance type with the
+// ENI limits that the VPC CNI publishes for it.
+func lookupInstanceInfo(embeddedLookup map[string]InstanceInfo, instanceType string) (InstanceInfo, bool) {
+	info, ok := embeddedLookup[instanceType]
+	if limits, found := vpc.GetInstance(instanceType); found {
+		info.InstanceType = instanceType
+		info.MaximumNetworkInterfaces = limits.ENILimit
+		info.IPv4AddressesPerInterface = limits.IPv4Limit
+		ok = true
+	}
+	return info, ok
+}
+
 func getInstanceTypeFromIMDS(ctx context.Context) (string, error) {
 	cfg, err := config.LoadDefaultConfig(ctx)
 	if err != nil {
@@ -31,6 +31,35 @@ func TestLoadEmbeddedInstanceInfo(t *testing.T) {
 	}
 }
 
+func TestLookupInstanceInfoNetworkInterfaces(t *testing.T) {
+	lookup := loadEmbeddedInstanceInfo()
+
+	tests := []struct {
+		instanceType          string
+		expectedGPUs          uint
+		expectedInterfaces    int
+		expectedIPv4Addresses int
+	}{
+		{"m5.xlarge", 0, 4, 15},
+		{"t3.micro", 0, 2, 2},
+		{"p5.48xlarge", 8, 2, 50},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.instanceType, func(t *testing.T) {
+			info, ok := lookupInstanceInfo(lookup, tt.instanceType)
+			assert.True(t, ok, "instance type %s should be known", tt.instanceType)
+			assert.Equal(t, tt.instanceType, info.InstanceType)
+			assert.Equal(t, tt.expectedGPUs, info.NvidiaGPUCount)
+			assert.Equal(t, tt.expectedInterfaces, info.MaximumNetworkInterfaces)
+			assert.Equal(t, tt.expectedIPv4Addresses, info.IPv4AddressesPerInterface)
+		})
+	}
+
+	_, ok := lookupInstanceInfo(lookup, "m5.nonexistent")
+	assert.False(t, ok, "unknown instance type should not be known")
+}
+
 func TestLoadEmbeddedInstanceInfoMissingType(t *testing.T) {
 	lookup := loadEmbeddedInstanceInfo()
 	_, ok := lookup["m5.xlarge"]
@@ -0,0 +1,131 @@
+package custom
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/aws/eks-node-monitoring-agent/api/monitor"
+	"github.com/aws/eks-node-monitoring-agent/pkg/config"
+	"github.com/aws/eks-node-monitoring-agent/pkg/osext"
+	"github.com/aws/eks-node-monitoring-agent/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var _ monitor.Monitor = (*customMonitor)(nil)
+
+// Exit codes defined by the node-problem-detector custom plugin protocol.
+const (
+	exitCodeOK      = 0
+	exitCodeNonOK   = 1
+	exitCodeUnknown = 2
+)
+
+// NewCustomMonitor creates a monitor that runs exec-based checks and reports
+// their problems under conditionType. Runs are limited by the shared
+// semaphore sem, and at most maxOutputBytes of each run's output are kept.
+func NewCustomMonitor(conditionType corev1.NodeConditionType, sem chan struct{}, maxOutputBytes int) *customMonitor {
+	return &customMonitor{
+		conditionType:  conditionType,
+		sem:            sem,
+		maxOutputBytes: maxOutputBytes,
+		newCommand: func(ctx context.Context, name string, args ...string) *exec.Cmd {
+			return osext.NewExec(config.HostRoot()).CommandContext(ctx, name, args...)
+		},
+	}
+}
+
+type customMonitor struct {
+	conditionType  corev1.NodeConditionType
+	checks         []config.CustomCheck
+	sem            chan struct{}
+	maxOutputBytes int
+	newCommand     func(ctx context.Context, name string, args ...string) *exec.Cmd
+
+	manager monitor.Manager
+}
+
+func (m *customMonitor) Name() string {
+	return "custom-plugin-" + strings.ToLower(string(m.conditionType))
+}
+
+// ConditionType returns the node condition that this monitor's checks report
+// under.
+func (m *customMonitor) ConditionType() corev1.NodeConditionType {
+	return m.conditionType
+}
+
+func (m *customMonitor) Conditions() []monitor.Condition {
+	return []monitor.Condition{}
+}
+
+func (m *customMonitor) Register(ctx context.Context, mgr monitor.Manager) error {
+	m.manager = mgr
+	for _, check := range m.checks {
+		logger := log.FromContext(ctx).WithValues("check", check.Name)
+		checkCtx := log.IntoContext(ctx, logger)
+		handler := util.NewChannelHandler(func(time.Time) error {
+			return m.runCheck(checkCtx, check)
+		}, util.TimeTickWithJitterContext(ctx, check.GetInterval()))
+		go handler.Start(checkCtx)
+	}
+	return nil
+}
+
+// runCheck executes a single check and notifies the manager when the check
+// reports a problem. A run is skipped rather than queued when the concurrency
+// limit is reached, so that slow checks cannot build up a backlog.
+func (m *customMonitor) runCheck(ctx context.Context, check config.CustomCheck) error {
+	logger := log.FromContext(ctx)
+	select {
+	case m.sem <- struct{}{}:
+		defer func() { <-m.sem }()
+	default:
+		logger.Info("skipping custom check run, concurrency limit reached")
+		return nil
+	}
+
+	output, err := osext.CombinedOutputLimit(ctx, check.GetTimeout(), m.maxOutputBytes, func(ctx context.Context) *exec.Cmd {
+		return m.newCommand(ctx, check.Path, check.Args...)
+	})
+	exitCode := exitCodeOK
+	if err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			// timeouts and failures to start the check leave the state unknown.
+			logger.Error(err, "custom check did not complete")
+			return nil
+		}
+		exitCode = exitErr.ExitCode()
+	}
+
+	switch exitCode {
+	case exitCodeOK:
+		return nil
+	case exitCodeNonOK:
+		return m.manager.Notify(ctx, monitor.Condition{
+			Reason:   check.Reason,
+			Message:  checkMessage(check, output),
+			Severity: monitor.Severity(check.GetSeverity()),
+		})
+	default:
+		logger.Info("custom check reported unknown state", "exitCode", exitCode, "output", string(bytes.TrimSpace(output)))
+		return nil
+	}
+}
+
+// checkMessage returns the first line of the check output, which is the
+// status message in the node-problem-detector protocol, or a generic message
+// when the check printed nothing.
+func checkMessage(check config.CustomCheck, output []byte) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(string(output)), "\n")
+	if line = strings.TrimSpace(line); line != "" {
+		return line
+	}
+	return fmt.Sprintf("Custom check %q reported a problem", check.Name)
+}
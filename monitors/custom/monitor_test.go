@@ -0,0 +1,156 @@
+package custom
+
+import (
+	"context"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aws/eks-node-monitoring-agent/api/monitor"
+	"github.com/aws/eks-node-monitoring-agent/api/monitor/resource"
+	"github.com/aws/eks-node-monitoring-agent/pkg/conditions"
+	"github.com/aws/eks-node-monitoring-agent/pkg/config"
+)
+
+type mockManager struct {
+	res chan monitor.Condition
+}
+
+func (m *mockManager) Subscribe(rType resource.Type, rParts []resource.Part) (<-chan string, error) {
+	return make(chan string), nil
+}
+
+func (m *mockManager) Notify(ctx context.Context, condition monitor.Condition) error {
+	m.res <- condition
+	return nil
+}
+
+// newTestMonitor returns a monitor whose checks run their Path as a shell
+// script instead of an executable on the host.
+func newTestMonitor(concurrency int, maxOutputBytes int) (*customMonitor, *mockManager) {
+	mgr := &mockManager{res: make(chan monitor.Condition, 10)}
+	m := NewCustomMonitor(conditions.KernelReady, make(chan struct{}, concurrency), maxOutputBytes)
+	m.newCommand = func(ctx context.Context, name string, args ...string) *exec.Cmd {
+		return exec.CommandContext(ctx, "sh", append([]string{"-c", name, "check"}, args...)...)
+	}
+	m.manager = mgr
+	return m, mgr
+}
+
+func TestRunCheck(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		script    string
+		severity  string
+		timeout   time.Duration
+		condition *monitor.Condition
+	}{
+		{
+			name:   "OK",
+			script: "echo healthy; exit 0",
+		},
+		{
+			name:     "NonOK",
+			script:   "echo 'clock skew of 3s'; echo details; exit 1",
+			severity: "Fatal",
+			condition: &monitor.Condition{
+				Reason:   "ClockSkewDetected",
+				Message:  "clock skew of 3s",
+				Severity: monitor.SeverityFatal,
+			},
+		},
+		{
+			name:   "NonOKWithoutOutput",
+			script: "exit 1",
+			condition: &monitor.Condition{
+				Reason:   "ClockSkewDetected",
+				Message:  `Custom check "NonOKWithoutOutput" reported a problem`,
+				Severity: monitor.SeverityWarning,
+			},
+		},
+		{
+			name:   "Unknown",
+			script: "echo 'chronyc not found'; exit 2",
+		},
+		{
+			name:   "UnexpectedExitCode",
+			script: "exit 7",
+		},
+		{
+			name:    "Timeout",
+			script:  "sleep 5; exit 1",
+			timeout: 100 * time.Millisecond,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			m, mgr := newTestMonitor(1, 512)
+			check := config.CustomCheck{
+				Name:     tc.name,
+				Path:     tc.script,
+				Reason:   "ClockSkewDetected",
+				Severity: tc.severity,
+			}
+			if tc.timeout > 0 {
+				check.Timeout = &metav1.Duration{Duration: tc.timeout}
+			}
+			require.NoError(t, m.runCheck(context.Background(), check))
+			if tc.condition == nil {
+				assert.Empty(t, mgr.res)
+				return
+			}
+			require.Len(t, mgr.res, 1)
+			assert.Equal(t, *tc.condition, <-mgr.res)
+		})
+	}
+}
+
+func TestRunCheckTruncatesOutput(t *testing.T) {
+	m, mgr := newTestMonitor(1, 8)
+	check := config.CustomCheck{Name: "truncate", Path: "echo 0123456789abcdef; exit 1", Reason: "ClockSkewDetected"}
+	require.NoError(t, m.runCheck(context.Background(), check))
+	require.Len(t, mgr.res, 1)
+	assert.Equal(t, "01234567", (<-mgr.res).Message)
+}
+
+func TestRunCheckSkipsWhenConcurrencyLimitReached(t *testing.T) {
+	m, mgr := newTestMonitor(1, 512)
+	// occupy the only slot, as a concurrently running check would.
+	m.sem <- struct{}{}
+	check := config.CustomCheck{Name: "skipped", Path: "exit 1", Reason: "ClockSkewDetected"}
+	require.NoError(t, m.runCheck(context.Background(), check))
+	assert.Empty(t, mgr.res)
+	<-m.sem
+	require.NoError(t, m.runCheck(context.Background(), check))
+	assert.Len(t, mgr.res, 1)
+}
+
+func TestNewPluginGroupsChecksByConditionType(t *testing.T) {
+	plugin := NewPlugin([]config.CustomCheck{
+		{Name: "a", Path: "/bin/a", Reason: "A"},
+		{Name: "b", Path: "/bin/b", Reason: "B", ConditionType: "NetworkingReady"},
+		{Name: "c", Path: "/bin/c", Reason: "C", ConditionType: "KernelReady"},
+	}, 2, 512)
+
+	assert.Equal(t, config.CustomPluginMonitorName, plugin.Name())
+	monitors := plugin.Monitors()
+	require.Len(t, monitors, 2)
+
+	kernel := monitors[0].(*customMonitor)
+	assert.Equal(t, "custom-plugin-kernelready", kernel.Name())
+	assert.Equal(t, conditions.KernelReady, kernel.ConditionType())
+	assert.Len(t, kernel.checks, 2)
+
+	networking := monitors[1].(*customMonitor)
+	assert.Equal(t, "custom-plugin-networkingready", networking.Name())
+	assert.Equal(t, corev1.NodeConditionType("NetworkingReady"), networking.ConditionType())
+	assert.Len(t, networking.checks, 1)
+
+	// all monitors share one concurrency limit.
+	assert.Equal(t, kernel.sem, networking.sem)
+	assert.Equal(t, 2, cap(kernel.sem))
+}
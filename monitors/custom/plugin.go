@@ -0,0 +1,33 @@
+package custom
+
+import (
+	"github.com/aws/eks-node-monitoring-agent/api/monitor"
+	"github.com/aws/eks-node-monitoring-agent/pkg/config"
+	"github.com/aws/eks-node-monitoring-agent/pkg/monitor/framework"
+	"github.com/aws/eks-node-monitoring-agent/pkg/monitor/registry"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// NewPlugin creates the custom plugin monitor for the configured checks. The
+// plugin is registered manually rather than in init() because its monitors
+// are derived from the monitor configuration.
+//
+// Checks are grouped into one monitor per condition type so that each monitor
+// can be registered with the manager under the condition it reports. All
+// monitors share a single concurrency limit.
+func NewPlugin(checks []config.CustomCheck, concurrency int, maxOutputBytes int) registry.MonitorPlugin {
+	sem := make(chan struct{}, concurrency)
+	var monitors []monitor.Monitor
+	byType := map[corev1.NodeConditionType]*customMonitor{}
+	for _, check := range checks {
+		conditionType := corev1.NodeConditionType(check.GetConditionType())
+		mon, ok := byType[conditionType]
+		if !ok {
+			mon = NewCustomMonitor(conditionType, sem, maxOutputBytes)
+			byType[conditionType] = mon
+			monitors = append(monitors, mon)
+		}
+		mon.checks = append(mon.checks, check)
+	}
+	return framework.NewPlugin(config.CustomPluginMonitorName, monitors)
+}
@@ -0,0 +1,198 @@
+package kernel
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/aws/eks-node-monitoring-agent/api/monitor"
+	"github.com/aws/eks-node-monitoring-agent/pkg/config"
+	"github.com/aws/eks-node-monitoring-agent/pkg/osext"
+	"github.com/aws/eks-node-monitoring-agent/pkg/reasons"
+)
+
+const (
+	// conntrackWarningRatio is the utilization of the conntrack table above
+	// which a sustained utilization is reported.
+	conntrackWarningRatio = 0.8
+	// conntrackCriticalRatio is the utilization of the conntrack table above
+	// which the table is reported right away, since it is close to dropping
+	// packets.
+	conntrackCriticalRatio = 0.95
+	// conntrackSustainedChecks is the number of consecutive checks the
+	// utilization must stay above conntrackWarningRatio to be reported.
+	conntrackSustainedChecks = 2
+	// conntrackMaxScannedEntries bounds the entries of /proc/net/nf_conntrack
+	// read for the per-state breakdown, since reading the table costs
+	// O(entries) on large nodes.
+	conntrackMaxScannedEntries = 65536
+	// conntrackStatesInMessage bounds the number of states included in the
+	// condition message.
+	conntrackStatesInMessage = 3
+)
+
+var (
+	conntrackUtilization = prometheus.NewGauge(
+		prometheus.GaugeOpts{Name: "conntrack_utilization_ratio"},
+	)
+	conntrackEntries = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{Name: "conntrack_entries"},
+		[]string{"protocol", "state"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		conntrackUtilization,
+		conntrackEntries,
+	)
+}
+
+// conntrackState is the number of conntrack entries for a protocol and state.
+type conntrackState struct {
+	protocol string
+	state    string
+	count    int
+}
+
+func (s conntrackState) String() string {
+	if s.state == "" {
+		return fmt.Sprintf("%s=%d", s.protocol, s.count)
+	}
+	return fmt.Sprintf("%s %s=%d", s.protocol, s.state, s.count)
+}
+
+// ~~~~ conntrack ~~~~
+
+// reporting a sustained utilization requires remembering the previous checks,
+// so this has a separate struct to track state.
+type conntrack struct {
+	*KernelMonitor
+	// highChecks is the number of consecutive checks the utilization was above
+	// conntrackWarningRatio.
+	highChecks int
+}
+
+func makeConntrack(k *KernelMonitor) *conntrack {
+	return &conntrack{KernelMonitor: k}
+}
+
+func (c *conntrack) handle() error {
+	count, err := osext.ParseSysctl("net.netfilter.nf_conntrack_count", func(b []byte) (int, error) { return strconv.Atoi(string(b)) })
+	if err != nil {
+		// the sysctls only exist once the nf_conntrack module is loaded.
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	limit, err := osext.ParseSysctl("net.netfilter.nf_conntrack_max", func(b []byte) (int, error) { return strconv.Atoi(string(b)) })
+	if err != nil {
+		return err
+	}
+	// the per-state breakdown is only available when the kernel is built with
+	// CONFIG_NF_CONNTRACK_PROCFS.
+	states, err := readConntrackStates()
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		c.logger.Error(err, "failed to read conntrack entries")
+	}
+	return c.check(*count, *limit, states)
+}
+
+// check reports the utilization of the conntrack table right away above
+// conntrackCriticalRatio, and otherwise once it has stayed above
+// conntrackWarningRatio for conntrackSustainedChecks checks in a row.
+func (c *conntrack) check(count, limit int, states []conntrackState) error {
+	if limit <= 0 {
+		return nil
+	}
+	percentageUsed := float64(count) / float64(limit)
+	conntrackUtilization.Set(percentageUsed)
+	conntrackEntries.Reset()
+	for _, state := range states {
+		conntrackEntries.WithLabelValues(state.protocol, state.state).Set(float64(state.count))
+	}
+
+	if percentageUsed < conntrackWarningRatio {
+		c.highChecks = 0
+		return nil
+	}
+	c.highChecks++
+	critical := percentageUsed >= conntrackCriticalRatio
+	if !critical && c.highChecks < conntrackSustainedChecks {
+		return nil
+	}
+	message := fmt.Sprintf("Approaching the max number of conntrack entries. %d of %d total, %0.1f%%", count, limit, percentageUsed*100)
+	if len(states) > 0 {
+		var top []string
+		for _, state := range states[:min(len(states), conntrackStatesInMessage)] {
+			top = append(top, state.String())
+		}
+		message += fmt.Sprintf(" (%s)", strings.Join(top, ", "))
+	}
+	builder := reasons.ApproachingConntrackMax.Builder().Message(message)
+	if critical {
+		builder = builder.Severity(monitor.SeverityFatal)
+	}
+	return c.manager.Notify(context.Background(), builder.Build())
+}
+
+// readConntrackStates counts the entries of the conntrack table by protocol and
+// state, sorted by descending count.
+func readConntrackStates() ([]conntrackState, error) {
+	f, err := os.Open(config.ToHostPath("/proc/net/nf_conntrack"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return parseConntrackStates(f, conntrackMaxScannedEntries)
+}
+
+// parseConntrackStates parses up to maxEntries entries of the format:
+//
+//	ipv4     2 tcp      6 431999 ESTABLISHED src=10.0.0.1 dst=10.0.0.2 ...
+//	ipv4     2 udp      17 29 src=10.0.0.1 dst=10.0.0.2 ...
+//
+// Only protocols that track connection state, such as tcp, have a state field.
+func parseConntrackStates(r io.Reader, maxEntries int) ([]conntrackState, error) {
+	type key struct{ protocol, state string }
+	counts := make(map[key]int)
+	scanner := bufio.NewScanner(r)
+	for entries := 0; entries < maxEntries && scanner.Scan(); entries++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 6 {
+			continue
+		}
+		entry := key{protocol: fields[2]}
+		if !strings.Contains(fields[5], "=") {
+			entry.state = fields[5]
+		}
+		counts[entry]++
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	states := make([]conntrackState, 0, len(counts))
+	for entry, count := range counts {
+		states = append(states, conntrackState{protocol: entry.protocol, state: entry.state, count: count})
+	}
+	slices.SortFunc(states, func(a, b conntrackState) int {
+		return cmp.Or(
+			cmp.Compare(b.count, a.count),
+			cmp.Compare(a.protocol, b.protocol),
+			cmp.Compare(a.state, b.state),
+		)
+	})
+	return states, nil
+}
@@ -0,0 +1,157 @@
+package edac
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/time/rate"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/aws/eks-node-monitoring-agent/api/monitor"
+	"github.com/aws/eks-node-monitoring-agent/pkg/config"
+	"github.com/aws/eks-node-monitoring-agent/pkg/osext"
+	"github.com/aws/eks-node-monitoring-agent/pkg/reasons"
+)
+
+const (
+	// https://www.kernel.org/doc/Documentation/ABI/testing/sysfs-devices-edac
+	edacSysfsPath = "/sys/devices/system/edac/mc"
+
+	// correctable errors are expected to trickle in on healthy memory, so they
+	// are only reported once they arrive faster than this sustained rate, after
+	// a burst allowance.
+	correctableErrorInterval = 6 * time.Minute
+	correctableErrorBurst    = 100
+)
+
+func NewEDACSystem() *edacSystem {
+	return &edacSystem{
+		trackers: map[string]*locationTracker{},
+	}
+}
+
+type edacSystem struct {
+	trackers map[string]*locationTracker
+}
+
+// memoryLocation is a memory controller or a DIMM on it, along with the files
+// holding its error counters.
+type memoryLocation struct {
+	name        string
+	ceCountPath string
+	ueCountPath string
+}
+
+// MemoryErrors reads the EDAC error counters of each memory controller, or of
+// each DIMM where the driver reports them, and returns conditions for new
+// uncorrectable errors and for correctable errors arriving at a rising rate.
+func (e *edacSystem) MemoryErrors(ctx context.Context) ([]monitor.Condition, error) {
+	controllers, err := filepath.Glob(config.ToHostPath(filepath.Join(edacSysfsPath, "mc[0-9]*")))
+	if err != nil {
+		return nil, err
+	}
+
+	var conditions []monitor.Condition
+	for _, controllerDir := range controllers {
+		locations, err := memoryLocations(controllerDir)
+		if err != nil {
+			return nil, err
+		}
+		for _, location := range locations {
+			logger := log.FromContext(ctx).WithValues("location", location.name)
+			ceCount, err := osext.ReadInt(location.ceCountPath)
+			if err != nil {
+				logger.V(4).Info("failed to read correctable error count", "error", err, "path", location.ceCountPath)
+				continue
+			}
+			ueCount, err := osext.ReadInt(location.ueCountPath)
+			if err != nil {
+				logger.V(4).Info("failed to read uncorrectable error count", "error", err, "path", location.ueCountPath)
+				continue
+			}
+			tracker, ok := e.trackers[location.name]
+			if !ok {
+				tracker = &locationTracker{
+					name:        location.name,
+					rateLimiter: rate.NewLimiter(rate.Every(correctableErrorInterval), correctableErrorBurst),
+				}
+				e.trackers[location.name] = tracker
+			}
+			conditions = append(conditions, tracker.Process(ceCount, ueCount)...)
+		}
+	}
+	return conditions, nil
+}
+
+// memoryLocations returns the DIMMs of a memory controller, or the controller
+// itself when its driver does not report errors per DIMM.
+func memoryLocations(controllerDir string) ([]memoryLocation, error) {
+	controller := filepath.Base(controllerDir)
+	var dimmDirs []string
+	// drivers name the per-DIMM directories after either DIMMs or ranks.
+	for _, pattern := range []string{"dimm[0-9]*", "rank[0-9]*"} {
+		matches, err := filepath.Glob(filepath.Join(controllerDir, pattern))
+		if err != nil {
+			return nil, err
+		}
+		dimmDirs = append(dimmDirs, matches...)
+	}
+	if len(dimmDirs) == 0 {
+		return []memoryLocation{{
+			name:        controller,
+			ceCountPath: filepath.Join(controllerDir, "ce_count"),
+			ueCountPath: filepath.Join(controllerDir, "ue_count"),
+		}}, nil
+	}
+
+	var locations []memoryLocation
+	for _, dimmDir := range dimmDirs {
+		name := fmt.Sprintf("%s/%s", controller, filepath.Base(dimmDir))
+		// the label identifies the physical slot, such as
+		// "CPU_SrcID#0_Ha#0_Chan#1_DIMM#0", but is not set by every driver.
+		if label, err := os.ReadFile(filepath.Join(dimmDir, "dimm_label")); err == nil && len(strings.TrimSpace(string(label))) > 0 {
+			name = fmt.Sprintf("%s (%s)", name, strings.TrimSpace(string(label)))
+		}
+		locations = append(locations, memoryLocation{
+			name:        name,
+			ceCountPath: filepath.Join(dimmDir, "dimm_ce_count"),
+			ueCountPath: filepath.Join(dimmDir, "dimm_ue_count"),
+		})
+	}
+	return locations, nil
+}
+
+type locationTracker struct {
+	ceCount     int
+	ueCount     int
+	rateLimiter *rate.Limiter
+	name        string
+}
+
+func (lt *locationTracker) Process(ceCount, ueCount int) []monitor.Condition {
+	// update the current counter values after the check is complete
+	defer func() { lt.ceCount, lt.ueCount = ceCount, ueCount }()
+
+	var conditions []monitor.Condition
+	if ueCount > lt.ueCount {
+		conditions = append(conditions,
+			reasons.MemoryUncorrectableError.
+				Builder().
+				Message(fmt.Sprintf("Uncorrectable memory errors increased from %d to %d on %s", lt.ueCount, ueCount, lt.name)).
+				Build(),
+		)
+	}
+	if delta := ceCount - lt.ceCount; delta > 0 && !lt.rateLimiter.AllowN(time.Now(), delta) {
+		conditions = append(conditions,
+			reasons.MemoryCorrectableErrorRate.
+				Builder().
+				Message(fmt.Sprintf("Correctable memory errors increased from %d to %d on %s", lt.ceCount, ceCount, lt.name)).
+				Build(),
+		)
+	}
+	return conditions
+}
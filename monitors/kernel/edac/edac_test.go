@@ -0,0 +1,115 @@
+package edac
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aws/eks-node-monitoring-agent/api/monitor"
+	"github.com/aws/eks-node-monitoring-agent/pkg/config"
+)
+
+func TestEDACSystem(t *testing.T) {
+	t.Run("NoControllers", func(t *testing.T) {
+		SetupRoot(t)
+
+		edacSystem := NewEDACSystem()
+		conditions, err := edacSystem.MemoryErrors(context.TODO())
+		assert.NoError(t, err)
+		assert.Len(t, conditions, 0)
+	})
+
+	t.Run("ControllerHealthy", func(t *testing.T) {
+		SetupRoot(t)
+		SetupCounter(t, "mc0", "ce_count", 3)
+		SetupCounter(t, "mc0", "ue_count", 0)
+
+		edacSystem := NewEDACSystem()
+		conditions, err := edacSystem.MemoryErrors(context.TODO())
+		assert.NoError(t, err)
+		assert.Len(t, conditions, 0)
+	})
+
+	t.Run("ControllerUncorrectable", func(t *testing.T) {
+		SetupRoot(t)
+		SetupCounter(t, "mc0", "ce_count", 0)
+		SetupCounter(t, "mc0", "ue_count", 0)
+
+		edacSystem := NewEDACSystem()
+		conditions, err := edacSystem.MemoryErrors(context.TODO())
+		assert.NoError(t, err)
+		assert.Len(t, conditions, 0)
+
+		SetupCounter(t, "mc0", "ue_count", 2)
+		conditions, err = edacSystem.MemoryErrors(context.TODO())
+		assert.NoError(t, err)
+		assert.Equal(t, []monitor.Condition{{
+			Reason:   "MemoryUncorrectableError",
+			Message:  "Uncorrectable memory errors increased from 0 to 2 on mc0",
+			Severity: monitor.SeverityFatal,
+		}}, conditions)
+
+		// the same count is not reported again.
+		conditions, err = edacSystem.MemoryErrors(context.TODO())
+		assert.NoError(t, err)
+		assert.Len(t, conditions, 0)
+	})
+
+	t.Run("DIMMCorrectableRate", func(t *testing.T) {
+		SetupRoot(t)
+		SetupCounter(t, "mc0", "ce_count", 0)
+		SetupCounter(t, "mc0", "ue_count", 0)
+		SetupDIMM(t, "mc0", "dimm1", "CPU_SrcID#0_Ha#0_Chan#1_DIMM#0")
+		SetupCounter(t, "mc0/dimm1", "dimm_ce_count", 10)
+		SetupCounter(t, "mc0/dimm1", "dimm_ue_count", 0)
+
+		edacSystem := NewEDACSystem()
+		conditions, err := edacSystem.MemoryErrors(context.TODO())
+		assert.NoError(t, err)
+		assert.Len(t, conditions, 0)
+
+		SetupCounter(t, "mc0/dimm1", "dimm_ce_count", 10+correctableErrorBurst)
+		conditions, err = edacSystem.MemoryErrors(context.TODO())
+		assert.NoError(t, err)
+		assert.Equal(t, []monitor.Condition{{
+			Reason:   "MemoryCorrectableErrorRate",
+			Message:  "Correctable memory errors increased from 10 to 110 on mc0/dimm1 (CPU_SrcID#0_Ha#0_Chan#1_DIMM#0)",
+			Severity: monitor.SeverityWarning,
+		}}, conditions)
+	})
+
+	t.Run("DIMMWithoutLabel", func(t *testing.T) {
+		SetupRoot(t)
+		SetupDIMM(t, "mc1", "rank0", "")
+		SetupCounter(t, "mc1/rank0", "dimm_ce_count", 0)
+		SetupCounter(t, "mc1/rank0", "dimm_ue_count", 1)
+
+		edacSystem := NewEDACSystem()
+		conditions, err := edacSystem.MemoryErrors(context.TODO())
+		assert.NoError(t, err)
+		assert.Len(t, conditions, 1)
+		assert.Equal(t, "Uncorrectable memory errors increased from 0 to 1 on mc1/rank0", conditions[0].Message)
+	})
+}
+
+func SetupRoot(t *testing.T) string {
+	root := t.TempDir()
+	t.Setenv(config.HOST_ROOT_ENV, root)
+	return root
+}
+
+func SetupDIMM(t *testing.T, controller, dimm, label string) {
+	dir := filepath.Join(config.HostRoot(), edacSysfsPath, controller, dimm)
+	assert.NoError(t, os.MkdirAll(dir, 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "dimm_label"), []byte(label+"\n"), 0644))
+}
+
+func SetupCounter(t *testing.T, dir, counterName string, counterValue int) {
+	counterDir := filepath.Join(config.HostRoot(), edacSysfsPath, dir)
+	assert.NoError(t, os.MkdirAll(counterDir, 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(counterDir, counterName), []byte(strconv.Itoa(counterValue)+"\n"), 0644))
+}
@@ -14,10 +14,12 @@ import (
 
 	"github.com/aws/eks-node-monitoring-agent/api/monitor"
 	"github.com/aws/eks-node-monitoring-agent/api/monitor/resource"
+	"github.com/aws/eks-node-monitoring-agent/monitors/kernel/edac"
 	"github.com/aws/eks-node-monitoring-agent/pkg/config"
 	"github.com/aws/eks-node-monitoring-agent/pkg/osext"
 	"github.com/aws/eks-node-monitoring-agent/pkg/reasons"
 	"github.com/aws/eks-node-monitoring-agent/pkg/util"
+	"github.com/aws/eks-node-monitoring-agent/pkg/util/pods"
 	"github.com/go-logr/logr"
 	"golang.org/x/sys/unix"
 	log "sigs.k8s.io/controller-runtime/pkg/log"
@@ -26,8 +28,9 @@ import (
 var _ monitor.Monitor = (*KernelMonitor)(nil)
 
 type KernelMonitor struct {
-	manager monitor.Manager
-	logger  logr.Logger
+	manager   monitor.Manager
+	logger    logr.Logger
+	podLister pods.Lister
 }
 
 func (m *KernelMonitor) Name() string {
@@ -57,6 +60,17 @@ func (m *KernelMonitor) Register(ctx context.Context, mgr monitor.Manager) error
 		return err
 	}
 
+	oomKills := makeOOMKills(m)
+	edacSystem := edac.NewEDACSystem()
+	taints := makeTaints(m)
+	conntrack := makeConntrack(m)
+
+	// reboots are only detected once, when the agent starts.
+	go func() {
+		if err := m.handleReboot(); err != nil {
+			m.logger.Error(err, "failed to check for an unexpected reboot")
+		}
+	}()
 	for _, handler := range []interface{ Start(context.Context) error }{
 		util.NewChannelHandler(m.handleDmesg, dmesg),
 		util.NewChannelHandler(m.handleKubelet, kubelet_log),
@@ -67,6 +81,11 @@ func (m *KernelMonitor) Register(ctx context.Context, mgr monitor.Manager) error
 		util.NewChannelHandler(func(time.Time) error { return m.handleEnvironment() }, util.TimeTickWithJitterContext(ctx, 5*time.Minute)),
 		util.NewChannelHandler(func(time.Time) error { return m.handleZram() }, util.TimeTickWithJitterContext(ctx, 5*time.Minute)),
 		util.NewChannelHandler(func(time.Time) error { return m.handleClockSync() }, util.TimeTickWithJitterContext(ctx, 5*time.Minute)),
+		util.NewChannelHandler(func(time.Time) error { return oomKills.handle() }, util.TimeTickWithJitterContext(ctx, oomKillInterval)),
+		util.NewChannelHandler(func(time.Time) error { return m.handleMemoryPressure() }, util.TimeTickWithJitterContext(ctx, 5*time.Minute)),
+		util.NewChannelHandler(func(time.Time) error { return m.handleMemoryErrors(ctx, edacSystem) }, util.TimeTickWithJitterContext(ctx, 5*time.Minute)),
+		util.NewChannelHandler(func(time.Time) error { return taints.handle() }, util.TimeTickWithJitterContext(ctx, 5*time.Minute)),
+		util.NewChannelHandler(func(time.Time) error { return conntrack.handle() }, util.TimeTickWithJitterContext(ctx, 5*time.Minute)),
 	} {
 		go handler.Start(ctx)
 	}
@@ -91,6 +110,18 @@ var (
 var (
 	appBlocked        = regexp.MustCompile(`task (.*?):\d+ blocked for more than`)
 	conntrackExceeded = regexp.MustCompile(`(ip|nf)_conntrack: table full, dropping packet`)
+	// the OOM killer reports a kill as "Memory cgroup out of memory" when a
+	// cgroup exceeded its limit, and as "Out of memory" when the whole system
+	// ran out. older kernels print "Kill process" instead of "Killed process".
+	oomKill = regexp.MustCompile(`(Memory cgroup out|Out) of memory: Kill(?:ed)? process (\d+) \((.*?)\)`)
+)
+var (
+	machineCheckException = regexp.MustCompile(`mce: \[Hardware Error\]: CPU (\d+): Machine Check Exception`)
+	uncorrectedMemoryMCE  = regexp.MustCompile(`mce: Uncorrected hardware memory error`)
+	machineCheckEvents    = regexp.MustCompile(`mce: \[Hardware Error\]: Machine check events logged`)
+	// correctable "CE" errors are also logged, but they are tracked as a rate
+	// through the EDAC counters instead.
+	edacUncorrectable = regexp.MustCompile(`EDAC (MC\d+): (?:\d+ )?UE(?: .* on (\S+))?`)
 )
 
 func (k *KernelMonitor) handleDmesg(line string) error {
@@ -132,6 +163,59 @@ func (k *KernelMonitor) handleDmesg(line string) error {
 				Message(fmt.Sprintf("Connection tracking exceeded the maximum for the kernel")).
 				Build(),
 		)
+	} else if matches := oomKill.FindStringSubmatch(line); matches != nil {
+		pid, processName := matches[2], matches[3]
+		if matches[1] != "Out" {
+			// a container exceeding its own memory limit is expected behavior,
+			// so it is only reported as an event, apart from system-wide OOM
+			// kills. storms of them are tracked in aggregate through the
+			// kubepods memory.events counters.
+			return k.manager.Notify(context.Background(),
+				reasons.CgroupOOMKill.
+					Builder().
+					Message(fmt.Sprintf("The OOM killer terminated process %q (PID %s) because its memory cgroup reached its limit", processName, pid)).
+					Build(),
+			)
+		}
+		return k.manager.Notify(context.Background(),
+			reasons.SystemOOMKill.
+				Builder().
+				Message(fmt.Sprintf("The system ran out of memory and the OOM killer terminated process %q (PID %s)", processName, pid)).
+				Build(),
+		)
+	} else if matches := machineCheckException.FindStringSubmatch(line); matches != nil {
+		cpu := matches[1]
+		return k.manager.Notify(context.Background(),
+			reasons.MachineCheckException.
+				Builder().
+				Message(fmt.Sprintf("CPU %s raised a machine check exception", cpu)).
+				Build(),
+		)
+	} else if uncorrectedMemoryMCE.MatchString(line) {
+		return k.manager.Notify(context.Background(),
+			reasons.MachineCheckException.
+				Builder().
+				Message("The kernel reported an uncorrected hardware memory error").
+				Build(),
+		)
+	} else if machineCheckEvents.MatchString(line) {
+		return k.manager.Notify(context.Background(),
+			reasons.MachineCheckEvents.
+				Builder().
+				Message("The kernel logged corrected machine check events").
+				Build(),
+		)
+	} else if matches := edacUncorrectable.FindStringSubmatch(line); matches != nil {
+		location := matches[1]
+		if dimm := matches[2]; dimm != "" {
+			location = fmt.Sprintf("%s (%s)", location, dimm)
+		}
+		return k.manager.Notify(context.Background(),
+			reasons.MemoryUncorrectableError.
+				Builder().
+				Message(fmt.Sprintf("The kernel reported an uncorrectable memory error on %s", location)).
+				Build(),
+		)
 	}
 
 	return nil
@@ -227,29 +311,84 @@ func (k *KernelMonitor) checkPids(pidCur, pidMax int) error {
 
 // ~~~~ zombies ~~~~
 
+const (
+	// zombieThreshold is the number of zombie processes on the node above
+	// which they are reported.
+	zombieThreshold = 20
+	// zombieParentsInMessage bounds the number of parents included in the
+	// condition message.
+	zombieParentsInMessage = 3
+)
+
 func (k *KernelMonitor) handleZombies() error {
-	// Parse ps output and check lines for '<defunct>'
-	out, err := osext.NewExec(config.HostRoot()).Command("ps", "aux").Output()
+	stats, err := osext.ReadProcStats()
 	if err != nil {
 		return err
 	}
+	parents := osext.ZombieParents(stats)
 	zombieCount := 0
-	for _, line := range strings.Split(string(out), "\n") {
-		if strings.Contains(line, "<defunct>") {
-			zombieCount += 1
+	for _, parent := range parents {
+		zombieCount += parent.Zombies
+	}
+	if zombieCount < zombieThreshold {
+		return nil
+	}
+	parents = parents[:min(len(parents), zombieParentsInMessage)]
+	// the pods of the parents are looked up at once, and only when there are
+	// any.
+	podUIDs := make(map[int]string)
+	for _, parent := range parents {
+		cgroup, err := osext.ReadProcCgroup(parent.PID)
+		if err != nil {
+			continue
+		}
+		if podUID, _, ok := osext.PodFromCgroup(cgroup); ok {
+			podUIDs[parent.PID] = podUID
+		}
+	}
+	var podsByUID map[string]pods.Metadata
+	if len(podUIDs) > 0 {
+		if podsByUID, err = k.podLister.ByUID(context.Background()); err != nil {
+			k.logger.Error(err, "failed to resolve pod names")
 		}
 	}
-	return k.checkZombies(zombieCount)
+	var descriptions []string
+	for _, parent := range parents {
+		descriptions = append(descriptions, describeZombieParent(parent, podUIDs[parent.PID], podsByUID))
+	}
+	return k.checkZombies(zombieCount, descriptions)
 }
 
-func (k *KernelMonitor) checkZombies(zombieCount int) error {
-	if zombieCount < 20 {
+// describeZombieParent describes a process that is not reaping its children,
+// including the pod it runs in when its cgroup belongs to one.
+func describeZombieParent(parent osext.ZombieParent, podUID string, podsByUID map[string]pods.Metadata) string {
+	process := fmt.Sprintf("process %q (PID %d)", parent.Comm, parent.PID)
+	if parent.Comm == "" {
+		process = fmt.Sprintf("process with PID %d", parent.PID)
+	}
+	description := fmt.Sprintf("%s is not reaping %d children", process, parent.Zombies)
+	if podUID == "" {
+		return description
+	}
+	pod := fmt.Sprintf("pod with UID %q", podUID)
+	if metadata, ok := podsByUID[podUID]; ok {
+		pod = fmt.Sprintf("pod %q", metadata)
+	}
+	return fmt.Sprintf("%s's %s", pod, description)
+}
+
+func (k *KernelMonitor) checkZombies(zombieCount int, parents []string) error {
+	if zombieCount < zombieThreshold {
 		return nil
 	}
+	message := fmt.Sprintf("Detected %d zombie processes still running", zombieCount)
+	if len(parents) > 0 {
+		message += ": " + strings.Join(parents, "; ")
+	}
 	return k.manager.Notify(context.Background(),
 		reasons.ExcessiveZombieProcesses.
 			Builder().
-			Message(fmt.Sprintf("Detected %d zombie processes still running", zombieCount)).
+			Message(message).
 			// this should run on a 5 minute cadence, so seeing this 5 times is
 			// already 25 minutes of prolonged exposure.
 			MinOccurrences(5).
@@ -411,6 +550,244 @@ func (k *KernelMonitor) checkZram(origSize, compSize, disksize int64, deviceName
 
 }
 
+// ~~~~ memory ~~~~
+
+const (
+	// oomKillInterval is how often the kubepods OOM kill counter is sampled.
+	oomKillInterval = 5 * time.Minute
+	// oomKillStormThreshold is the number of container OOM kills within one
+	// oomKillInterval that is considered a storm.
+	oomKillStormThreshold = 10
+	// memoryPressureFullThreshold is the percentage of time over the last five
+	// minutes that all non-idle tasks may be stalled on memory.
+	memoryPressureFullThreshold = 10.0
+)
+
+// kubepodsMemoryEventsPaths are the memory.events files of the cgroup that
+// holds all pods, for the systemd and cgroupfs kubelet cgroup drivers. Only
+// cgroup v2 is supported, since its counters include all descendant cgroups.
+var kubepodsMemoryEventsPaths = []string{
+	"/sys/fs/cgroup/kubepods.slice/memory.events",
+	"/sys/fs/cgroup/kubepods/memory.events",
+}
+
+// counting OOM kills requires remembering the previous counter value, so this
+// handler is implemented on top of a wrapper class.
+type oomKills struct {
+	*KernelMonitor
+	last *int64
+}
+
+func makeOOMKills(k *KernelMonitor) *oomKills {
+	return &oomKills{KernelMonitor: k}
+}
+
+func (o *oomKills) handle() error {
+	for _, path := range kubepodsMemoryEventsPaths {
+		data, err := os.ReadFile(config.ToHostPath(path))
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return err
+		}
+		count, err := parseOOMKillCount(data)
+		if err != nil {
+			return fmt.Errorf("parsing %s: %w", path, err)
+		}
+		return o.check(count)
+	}
+	o.logger.V(1).Info("kubepods memory.events not found on this node")
+	return nil
+}
+
+// check compares the OOM kill counter with the value seen on the previous
+// interval.
+func (o *oomKills) check(count int64) error {
+	last := o.last
+	o.last = &count
+	// the first sample has nothing to compare with, and a lower count means
+	// the cgroup was recreated.
+	if last == nil || count < *last {
+		return nil
+	}
+	kills := count - *last
+	if kills < oomKillStormThreshold {
+		return nil
+	}
+	return o.manager.Notify(context.Background(),
+		reasons.ContainerOOMKillStorm.
+			Builder().
+			Message(fmt.Sprintf("%d containers were OOM killed in the last %s", kills, oomKillInterval)).
+			Build(),
+	)
+}
+
+// parseOOMKillCount returns the oom_kill counter from a memory.events file.
+func parseOOMKillCount(data []byte) (int64, error) {
+	for _, line := range strings.Split(string(data), "\n") {
+		key, value, ok := strings.Cut(line, " ")
+		if ok && key == "oom_kill" {
+			return strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		}
+	}
+	return 0, fmt.Errorf("oom_kill counter not found")
+}
+
+func (k *KernelMonitor) handleMemoryPressure() error {
+	data, err := os.ReadFile(config.ToHostPath("/proc/pressure/memory"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) || errors.Is(err, syscall.EOPNOTSUPP) {
+			// PSI is disabled in this kernel.
+			k.logger.V(1).Info("memory pressure stall information not available on this node")
+			return nil
+		}
+		return err
+	}
+	fullAvg300, err := parsePSIFullAvg300(data)
+	if err != nil {
+		return err
+	}
+	return k.checkMemoryPressure(fullAvg300)
+}
+
+func (k *KernelMonitor) checkMemoryPressure(fullAvg300 float64) error {
+	if fullAvg300 < memoryPressureFullThreshold {
+		return nil
+	}
+	return k.manager.Notify(context.Background(),
+		reasons.MemoryPressureSustained.
+			Builder().
+			Message(fmt.Sprintf("All non-idle tasks were stalled on memory %.1f%% of the time over the last 5 minutes", fullAvg300)).
+			// this runs on a 5 minute cadence, so a second occurrence means the
+			// pressure has lasted beyond a single averaging window.
+			MinOccurrences(1).
+			Build(),
+	)
+}
+
+// parsePSIFullAvg300 returns the avg300 value of the "full" line of a PSI file.
+// see: https://docs.kernel.org/accounting/psi.html
+func parsePSIFullAvg300(data []byte) (float64, error) {
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || fields[0] != "full" {
+			continue
+		}
+		for _, field := range fields[1:] {
+			if value, ok := strings.CutPrefix(field, "avg300="); ok {
+				return strconv.ParseFloat(value, 64)
+			}
+		}
+	}
+	return 0, fmt.Errorf("full avg300 not found in pressure stall information")
+}
+
+// ~~~~ hardware memory errors ~~~~
+
+type memoryErrorCounter interface {
+	MemoryErrors(context.Context) ([]monitor.Condition, error)
+}
+
+func (k *KernelMonitor) handleMemoryErrors(ctx context.Context, counter memoryErrorCounter) error {
+	conditions, err := counter.MemoryErrors(ctx)
+	if err != nil {
+		return err
+	}
+	for _, condition := range conditions {
+		if err := k.manager.Notify(ctx, condition); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ~~~~ taint ~~~~
+
+// taintFlags names the bits of the kernel taint mask, indexed by bit.
+// see: https://docs.kernel.org/admin-guide/tainted-kernels.html
+var taintFlags = []string{
+	"proprietary module",
+	"forced module load",
+	"out of spec system",
+	"forced module unload",
+	"machine check",
+	"bad page",
+	"user request",
+	"kernel died",
+	"ACPI table override",
+	"warning",
+	"staging driver",
+	"firmware workaround",
+	"out-of-tree module",
+	"unsigned module",
+	"soft lockup",
+	"live patch",
+	"auxiliary",
+	"struct randomization",
+	"in-kernel test",
+	"debugfs mutation",
+}
+
+// decodeTaint returns the names of the flags set in a kernel taint mask.
+func decodeTaint(mask uint64) []string {
+	var flags []string
+	for bit := 0; bit < 64; bit++ {
+		if mask&(1<<bit) == 0 {
+			continue
+		}
+		if bit < len(taintFlags) {
+			flags = append(flags, taintFlags[bit])
+		} else {
+			flags = append(flags, fmt.Sprintf("bit %d", bit))
+		}
+	}
+	return flags
+}
+
+// detecting new taint flags requires remembering the previous mask, so this
+// handler is implemented on top of a wrapper class.
+type taints struct {
+	*KernelMonitor
+	last *uint64
+}
+
+func makeTaints(k *KernelMonitor) *taints {
+	return &taints{KernelMonitor: k}
+}
+
+func (t *taints) handle() error {
+	mask, err := osext.ParseSysctl("kernel.tainted", func(b []byte) (uint64, error) { return strconv.ParseUint(string(b), 10, 64) })
+	if err != nil {
+		return err
+	}
+	return t.check(*mask)
+}
+
+// check reports the taint flags that were set since the previous check. Flags
+// already set when the agent starts are only logged, since they may have been
+// set long before and are expected on some nodes.
+func (t *taints) check(mask uint64) error {
+	last := t.last
+	t.last = &mask
+	if last == nil {
+		if mask != 0 {
+			t.logger.Info("kernel is tainted", "tainted", mask, "flags", decodeTaint(mask))
+		}
+		return nil
+	}
+	newFlags := mask &^ *last
+	if newFlags == 0 {
+		return nil
+	}
+	return t.manager.Notify(context.Background(),
+		reasons.KernelTainted.
+			Builder().
+			Message(fmt.Sprintf("The kernel was tainted with new flags: %s (tainted=%d)", strings.Join(decodeTaint(newFlags), ", "), mask)).
+			Build(),
+	)
+}
+
 // ~~~~ clock sync ~~~~
 
 func (k *KernelMonitor) handleClockSync() error {
@@ -3,16 +3,23 @@ package kernel
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 
 	"golang.org/x/sys/unix"
+	cri "k8s.io/cri-api/pkg/apis"
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
 
 	"github.com/aws/eks-node-monitoring-agent/api/monitor"
 	"github.com/aws/eks-node-monitoring-agent/api/monitor/resource"
+	"github.com/aws/eks-node-monitoring-agent/pkg/config"
+	"github.com/aws/eks-node-monitoring-agent/pkg/util/pods"
 )
 
 // mockObserver provides a simple channel-based observer for testing
@@ -70,6 +77,20 @@ func (m *mockManager) Notify(ctx context.Context, condition monitor.Condition) e
 	return nil
 }
 
+// TestMain points the host root at an empty directory, so that the checks
+// started by Register neither read nor persist state on the machine running
+// the tests.
+func TestMain(m *testing.M) {
+	root, err := os.MkdirTemp("", "kernel-monitor")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv(config.HOST_ROOT_ENV, root)
+	code := m.Run()
+	os.RemoveAll(root)
+	os.Exit(code)
+}
+
 func TestKernelMonitor(t *testing.T) {
 	for _, testCase := range []struct {
 		log          string
@@ -85,6 +106,12 @@ func TestKernelMonitor(t *testing.T) {
 		{"[   32.298491][  T896] kexec[896]: segfault at 0 ip 0000000000000000 sp 00007ffeaf0ff420 error 14 in dash[561ac3c57000+4000]", "AppCrash", resource.ResourceTypeDmesg, "", monitor.SeverityWarning},
 		{"task foo:123 blocked for more than 20s", "AppBlocked", resource.ResourceTypeDmesg, "", monitor.SeverityWarning},
 		{"nf_conntrack: nf_conntrack: table full, dropping packet", "ConntrackExceededKernel", resource.ResourceTypeDmesg, "", monitor.SeverityWarning},
+		{"[ 1234.567890] Out of memory: Killed process 4321 (java) total-vm:8388608kB, anon-rss:4194304kB, file-rss:0kB, shmem-rss:0kB, UID:0 pgtables:8192kB oom_score_adj:0", "SystemOOMKill", resource.ResourceTypeDmesg, "", monitor.SeverityWarning},
+		{"[ 1234.567890] Out of memory: Kill process 4321 (java) score 900 or sacrifice child", "SystemOOMKill", resource.ResourceTypeDmesg, "", monitor.SeverityWarning},
+		{"[ 5678.123456] mce: [Hardware Error]: CPU 3: Machine Check Exception: 5 Bank 4: be00000000800400", "MachineCheckException", resource.ResourceTypeDmesg, "", monitor.SeverityFatal},
+		{"[ 5678.123456] mce: Uncorrected hardware memory error in user-access at 3e8a0a400", "MachineCheckException", resource.ResourceTypeDmesg, "", monitor.SeverityFatal},
+		{"[ 5678.123456] mce: [Hardware Error]: Machine check events logged", "MachineCheckEvents", resource.ResourceTypeDmesg, "", monitor.SeverityWarning},
+		{"[ 5678.123456] EDAC MC1: 1 UE memory read error on CPU_SrcID#1_MC#0_Chan#0_DIMM#0 (channel:0 slot:0 page:0x1234 offset:0x0 grain:32 syndrome:0x0)", "MemoryUncorrectableError", resource.ResourceTypeDmesg, "", monitor.SeverityFatal},
 	} {
 		t.Run(testCase.log, func(t *testing.T) {
 			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
@@ -152,6 +179,31 @@ func TestKernelMonitor(t *testing.T) {
 		}
 	})
 
+	t.Run("CgroupOOMKill", func(t *testing.T) {
+		mon := &KernelMonitor{}
+		mockManager := &mockManager{res: make(chan monitor.Condition, 5)}
+		mon.manager = mockManager
+		if err := mon.handleDmesg("[ 1234.567890] Memory cgroup out of memory: Killed process 4321 (java) total-vm:8388608kB, anon-rss:4194304kB"); err != nil {
+			t.Fatal(err)
+		}
+		if assert.Len(t, mockManager.res, 1) {
+			monitorResult := <-mockManager.res
+			assert.Equal(t, "CgroupOOMKill", monitorResult.Reason)
+			assert.Equal(t, monitor.SeverityInfo, monitorResult.Severity)
+			assert.Equal(t, `The OOM killer terminated process "java" (PID 4321) because its memory cgroup reached its limit`, monitorResult.Message)
+		}
+	})
+
+	t.Run("EDACCorrectableIgnored", func(t *testing.T) {
+		mon := &KernelMonitor{}
+		mockManager := &mockManager{res: make(chan monitor.Condition, 5)}
+		mon.manager = mockManager
+		if err := mon.handleDmesg("[ 5678.123456] EDAC MC0: 1 CE memory read error on CPU_SrcID#0_Ha#0_Chan#1_DIMM#0 (channel:1 slot:0 page:0x1234 offset:0x0 grain:32 syndrome:0x0)"); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, 0, len(mockManager.res))
+	})
+
 	t.Run("SubscribeError", func(t *testing.T) {
 		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 		defer cancel()
@@ -230,7 +282,7 @@ func TestKernelPeriodic(t *testing.T) {
 		mon := &KernelMonitor{}
 		mockManager := &mockManager{obs: newMockObserver(), res: make(chan monitor.Condition, 5)}
 		mon.Register(ctx, mockManager)
-		if err := mon.checkZombies(0); err != nil {
+		if err := mon.checkZombies(0, nil); err != nil {
 			t.Fatal(err)
 		}
 		assert.Equal(t, 0, len(mockManager.res))
@@ -242,7 +294,7 @@ func TestKernelPeriodic(t *testing.T) {
 		mon := &KernelMonitor{}
 		mockManager := &mockManager{obs: newMockObserver(), res: make(chan monitor.Condition, 5)}
 		mon.Register(ctx, mockManager)
-		if err := mon.checkZombies(20); err != nil {
+		if err := mon.checkZombies(20, nil); err != nil {
 			t.Fatal(err)
 		}
 		select {
@@ -254,6 +306,32 @@ func TestKernelPeriodic(t *testing.T) {
 		}
 	})
 
+	t.Run("ExcessiveZombieProcessesAttributed", func(t *testing.T) {
+		root := t.TempDir()
+		t.Setenv(config.HOST_ROOT_ENV, root)
+		const podUID = "8d2b6f4e-3c1a-4e5f-9a7b-1c2d3e4f5a6b"
+		writeFile(t, filepath.Join(root, "proc/1/stat"), "1 (systemd) S 0 1 1 0 -1 4194560 0 0\n")
+		writeFile(t, filepath.Join(root, "proc/1/cgroup"), "0::/init.scope\n")
+		writeFile(t, filepath.Join(root, "proc/4321/stat"), "4321 (my (app)) S 1 4321 4321 0 -1 4194560 0 0\n")
+		writeFile(t, filepath.Join(root, "proc/4321/cgroup"), "0::/kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod8d2b6f4e_3c1a_4e5f_9a7b_1c2d3e4f5a6b.slice/cri-containerd-"+strings.Repeat("a", 64)+".scope\n")
+		for pid := 5000; pid < 5018; pid++ {
+			writeFile(t, filepath.Join(root, fmt.Sprintf("proc/%d/stat", pid)), fmt.Sprintf("%d (worker) Z 4321 4321 4321 0 -1 4227084 0 0\n", pid))
+		}
+		for pid := 6000; pid < 6002; pid++ {
+			writeFile(t, filepath.Join(root, fmt.Sprintf("proc/%d/stat", pid)), fmt.Sprintf("%d (sh) Z 1 1 1 0 -1 4227084 0 0\n", pid))
+		}
+
+		mon := &KernelMonitor{podLister: pods.Lister{RuntimeService: &fakeRuntimeService{sandboxes: []*runtimeapi.PodSandbox{
+			{Metadata: &runtimeapi.PodSandboxMetadata{Uid: podUID, Namespace: "default", Name: "web-0"}},
+		}}}}
+		mockManager := &mockManager{res: make(chan monitor.Condition, 5)}
+		mon.manager = mockManager
+		assert.NoError(t, mon.handleZombies())
+		monitorResult := <-mockManager.res
+		assert.Equal(t, "ExcessiveZombieProcesses", monitorResult.Reason)
+		assert.Equal(t, `Detected 20 zombie processes still running: pod "default/web-0"'s process "my (app)" (PID 4321) is not reaping 18 children; process "systemd" (PID 1) is not reaping 2 children`, monitorResult.Message)
+	})
+
 	t.Run("LargeEnvironment", func(t *testing.T) {
 		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 		defer cancel()
@@ -369,4 +447,340 @@ func TestKernelPeriodic(t *testing.T) {
 			assert.Contains(t, monitorResult.Message, "capacity")
 		}
 	})
+
+	t.Run("ContainerOOMKillStorm", func(t *testing.T) {
+		mon := &KernelMonitor{}
+		mockManager := &mockManager{res: make(chan monitor.Condition, 5)}
+		mon.manager = mockManager
+		oomKills := makeOOMKills(mon)
+
+		// the first sample only establishes a baseline.
+		assert.NoError(t, oomKills.check(100))
+		assert.NoError(t, oomKills.check(100+oomKillStormThreshold-1))
+		assert.Equal(t, 0, len(mockManager.res))
+		// a reset counter is treated as a new baseline.
+		assert.NoError(t, oomKills.check(0))
+		assert.Equal(t, 0, len(mockManager.res))
+
+		assert.NoError(t, oomKills.check(oomKillStormThreshold))
+		monitorResult := <-mockManager.res
+		assert.Equal(t, monitor.SeverityWarning, monitorResult.Severity)
+		assert.Equal(t, "ContainerOOMKillStorm", monitorResult.Reason)
+		assert.Contains(t, monitorResult.Message, "10 containers")
+	})
+
+	t.Run("ContainerOOMKillStormFromCgroup", func(t *testing.T) {
+		root := t.TempDir()
+		t.Setenv(config.HOST_ROOT_ENV, root)
+		path := filepath.Join(root, "sys/fs/cgroup/kubepods.slice/memory.events")
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		writeEvents := func(oomKill int) {
+			assert.NoError(t, os.WriteFile(path, []byte(fmt.Sprintf("low 0\nhigh 0\nmax 12\noom 3\noom_kill %d\noom_group_kill 0\n", oomKill)), 0644))
+		}
+
+		mon := &KernelMonitor{}
+		mockManager := &mockManager{res: make(chan monitor.Condition, 5)}
+		mon.manager = mockManager
+		oomKills := makeOOMKills(mon)
+
+		writeEvents(3)
+		assert.NoError(t, oomKills.handle())
+		writeEvents(20)
+		assert.NoError(t, oomKills.handle())
+		monitorResult := <-mockManager.res
+		assert.Equal(t, "ContainerOOMKillStorm", monitorResult.Reason)
+		assert.Contains(t, monitorResult.Message, "17 containers")
+	})
+
+	t.Run("MemoryPressureSustained", func(t *testing.T) {
+		mon := &KernelMonitor{}
+		mockManager := &mockManager{res: make(chan monitor.Condition, 5)}
+		mon.manager = mockManager
+
+		fullAvg300, err := parsePSIFullAvg300([]byte("some avg10=40.12 avg60=35.50 avg300=30.01 total=123456789\nfull avg10=20.00 avg60=15.25 avg300=12.50 total=98765432\n"))
+		assert.NoError(t, err)
+		assert.Equal(t, 12.5, fullAvg300)
+
+		assert.NoError(t, mon.checkMemoryPressure(2.0))
+		assert.Equal(t, 0, len(mockManager.res))
+
+		assert.NoError(t, mon.checkMemoryPressure(fullAvg300))
+		monitorResult := <-mockManager.res
+		assert.Equal(t, monitor.SeverityWarning, monitorResult.Severity)
+		assert.Equal(t, "MemoryPressureSustained", monitorResult.Reason)
+		assert.Equal(t, int64(1), monitorResult.MinOccurrences)
+	})
+
+	t.Run("ParsePSIMissingFull", func(t *testing.T) {
+		_, err := parsePSIFullAvg300([]byte("some avg10=0.00 avg60=0.00 avg300=0.00 total=0\n"))
+		assert.Error(t, err)
+	})
+
+	t.Run("ApproachingConntrackMaxNoop", func(t *testing.T) {
+		mon := &KernelMonitor{}
+		mockManager := &mockManager{res: make(chan monitor.Condition, 5)}
+		mon.manager = mockManager
+		conntrack := makeConntrack(mon)
+		for range 3 {
+			assert.NoError(t, conntrack.check(1000, 262144, nil))
+			assert.NoError(t, conntrack.check(10, 0, nil))
+		}
+		assert.Equal(t, 0, len(mockManager.res))
+	})
+
+	t.Run("ApproachingConntrackMax", func(t *testing.T) {
+		mon := &KernelMonitor{}
+		mockManager := &mockManager{res: make(chan monitor.Condition, 5)}
+		mon.manager = mockManager
+		conntrack := makeConntrack(mon)
+		states := []conntrackState{
+			{protocol: "tcp", state: "TIME_WAIT", count: 150},
+			{protocol: "tcp", state: "ESTABLISHED", count: 50},
+			{protocol: "udp", count: 10},
+			{protocol: "tcp", state: "SYN_SENT", count: 1},
+		}
+
+		// a utilization that is not sustained across two checks is not
+		// reported, however long ago the previous one was.
+		assert.NoError(t, conntrack.check(220, 256, states))
+		assert.NoError(t, conntrack.check(100, 256, states))
+		assert.NoError(t, conntrack.check(220, 256, states))
+		assert.Equal(t, 0, len(mockManager.res))
+
+		assert.NoError(t, conntrack.check(220, 256, states))
+		monitorResult := <-mockManager.res
+		assert.Equal(t, monitor.SeverityWarning, monitorResult.Severity)
+		assert.Equal(t, "ApproachingConntrackMax", monitorResult.Reason)
+		assert.Equal(t, "Approaching the max number of conntrack entries. 220 of 256 total, 85.9% (tcp TIME_WAIT=150, tcp ESTABLISHED=50, udp=10)", monitorResult.Message)
+		assert.Equal(t, float64(150), testutil.ToFloat64(conntrackEntries.WithLabelValues("tcp", "TIME_WAIT")))
+		assert.Equal(t, float64(10), testutil.ToFloat64(conntrackEntries.WithLabelValues("udp", "")))
+	})
+
+	t.Run("ApproachingConntrackMaxCritical", func(t *testing.T) {
+		mon := &KernelMonitor{}
+		mockManager := &mockManager{res: make(chan monitor.Condition, 5)}
+		mon.manager = mockManager
+		conntrack := makeConntrack(mon)
+
+		// above the critical ratio the table is reported on the first check.
+		assert.NoError(t, conntrack.check(250, 256, nil))
+		if assert.Len(t, mockManager.res, 1) {
+			monitorResult := <-mockManager.res
+			assert.Equal(t, monitor.SeverityFatal, monitorResult.Severity)
+			assert.Equal(t, "ApproachingConntrackMax", monitorResult.Reason)
+			assert.Equal(t, "Approaching the max number of conntrack entries. 250 of 256 total, 97.7%", monitorResult.Message)
+		}
+	})
+
+	t.Run("ApproachingConntrackMaxFromProc", func(t *testing.T) {
+		root := t.TempDir()
+		t.Setenv(config.HOST_ROOT_ENV, root)
+		writeFile(t, filepath.Join(root, "proc/sys/net/netfilter/nf_conntrack_count"), "900\n")
+		writeFile(t, filepath.Join(root, "proc/sys/net/netfilter/nf_conntrack_max"), "1000\n")
+		writeFile(t, filepath.Join(root, "proc/net/nf_conntrack"), strings.Join([]string{
+			"ipv4     2 tcp      6 431999 ESTABLISHED src=10.0.0.1 dst=10.0.0.2 sport=40000 dport=443 src=10.0.0.2 dst=10.0.0.1 sport=443 dport=40000 [ASSURED] mark=0 zone=0 use=2",
+			"ipv4     2 tcp      6 110 TIME_WAIT src=10.0.0.1 dst=10.0.0.3 sport=40001 dport=80 src=10.0.0.3 dst=10.0.0.1 sport=80 dport=40001 [ASSURED] mark=0 zone=0 use=2",
+			"ipv4     2 tcp      6 100 TIME_WAIT src=10.0.0.1 dst=10.0.0.3 sport=40002 dport=80 src=10.0.0.3 dst=10.0.0.1 sport=80 dport=40002 [ASSURED] mark=0 zone=0 use=2",
+			"ipv4     2 udp      17 29 src=10.0.0.1 dst=10.0.0.10 sport=53000 dport=53 [UNREPLIED] src=10.0.0.10 dst=10.0.0.1 sport=53 dport=53000 mark=0 zone=0 use=2",
+		}, "\n"))
+
+		mon := &KernelMonitor{}
+		mockManager := &mockManager{res: make(chan monitor.Condition, 5)}
+		mon.manager = mockManager
+		conntrack := makeConntrack(mon)
+		assert.NoError(t, conntrack.handle())
+		assert.NoError(t, conntrack.handle())
+		monitorResult := <-mockManager.res
+		assert.Equal(t, "ApproachingConntrackMax", monitorResult.Reason)
+		assert.Contains(t, monitorResult.Message, "900 of 1000 total")
+		assert.Contains(t, monitorResult.Message, "(tcp TIME_WAIT=2, tcp ESTABLISHED=1, udp=1)")
+	})
+
+	t.Run("ApproachingConntrackMaxWithoutProcfs", func(t *testing.T) {
+		root := t.TempDir()
+		t.Setenv(config.HOST_ROOT_ENV, root)
+		writeFile(t, filepath.Join(root, "proc/sys/net/netfilter/nf_conntrack_count"), "990\n")
+		writeFile(t, filepath.Join(root, "proc/sys/net/netfilter/nf_conntrack_max"), "1000\n")
+
+		mon := &KernelMonitor{}
+		mockManager := &mockManager{res: make(chan monitor.Condition, 5)}
+		mon.manager = mockManager
+		assert.NoError(t, makeConntrack(mon).handle())
+		monitorResult := <-mockManager.res
+		assert.Equal(t, "Approaching the max number of conntrack entries. 990 of 1000 total, 99.0%", monitorResult.Message)
+	})
+
+	t.Run("ParseConntrackStatesBounded", func(t *testing.T) {
+		entries := strings.Repeat("ipv4     2 tcp      6 110 TIME_WAIT src=10.0.0.1 dst=10.0.0.3\n", 3) +
+			"ipv4     2 udp      17 29 src=10.0.0.1 dst=10.0.0.10\n"
+		states, err := parseConntrackStates(strings.NewReader(entries), 3)
+		assert.NoError(t, err)
+		assert.Equal(t, []conntrackState{{protocol: "tcp", state: "TIME_WAIT", count: 3}}, states)
+	})
+
+	t.Run("ConntrackNotLoaded", func(t *testing.T) {
+		t.Setenv(config.HOST_ROOT_ENV, t.TempDir())
+		mon := &KernelMonitor{}
+		mockManager := &mockManager{res: make(chan monitor.Condition, 5)}
+		mon.manager = mockManager
+		assert.NoError(t, makeConntrack(mon).handle())
+		assert.Equal(t, 0, len(mockManager.res))
+	})
+}
+
+func TestKernelTaint(t *testing.T) {
+	t.Run("DecodeTaint", func(t *testing.T) {
+		assert.Empty(t, decodeTaint(0))
+		assert.Equal(t, []string{"proprietary module", "machine check"}, decodeTaint(1<<0|1<<4))
+		assert.Equal(t, []string{"bit 40"}, decodeTaint(1<<40))
+	})
+
+	t.Run("KernelTainted", func(t *testing.T) {
+		mon := &KernelMonitor{}
+		mockManager := &mockManager{res: make(chan monitor.Condition, 5)}
+		mon.manager = mockManager
+		taints := makeTaints(mon)
+
+		// the taint mask at startup is only a baseline.
+		assert.NoError(t, taints.check(1<<12))
+		assert.Equal(t, 0, len(mockManager.res))
+		assert.NoError(t, taints.check(1<<12))
+		assert.Equal(t, 0, len(mockManager.res))
+
+		assert.NoError(t, taints.check(1<<12|1<<9))
+		monitorResult := <-mockManager.res
+		assert.Equal(t, monitor.SeverityWarning, monitorResult.Severity)
+		assert.Equal(t, "KernelTainted", monitorResult.Reason)
+		assert.Contains(t, monitorResult.Message, "warning")
+		assert.NotContains(t, monitorResult.Message, "out-of-tree module")
+
+		// flags are only reported when they are first set.
+		assert.NoError(t, taints.check(1<<12|1<<9))
+		assert.Equal(t, 0, len(mockManager.res))
+	})
+}
+
+func TestKernelReboot(t *testing.T) {
+	setupRoot := func(t *testing.T, bootID string) string {
+		root := t.TempDir()
+		t.Setenv(config.HOST_ROOT_ENV, root)
+		writeFile(t, filepath.Join(root, bootIDPath), bootID+"\n")
+		writeFile(t, filepath.Join(root, "proc/stat"), fmt.Sprintf("cpu  1 2 3 4\nbtime %d\nprocesses 42\n", time.Now().Add(-time.Hour).Unix()))
+		return root
+	}
+	newMonitor := func() (*KernelMonitor, *mockManager) {
+		mockManager := &mockManager{res: make(chan monitor.Condition, 5)}
+		return &KernelMonitor{manager: mockManager}, mockManager
+	}
+	const panicLog = `Panic#1 Part1
+<4>[  812.123456] CPU: 3 PID: 1234 Comm: kworker/3:1 Tainted: G        W
+<0>[  812.123999] Kernel panic - not syncing: Fatal exception in interrupt
+`
+
+	t.Run("FirstRun", func(t *testing.T) {
+		root := setupRoot(t, "new-boot")
+		mon, mockManager := newMonitor()
+		assert.NoError(t, mon.handleReboot())
+		assert.Equal(t, 0, len(mockManager.res))
+		bootID, err := os.ReadFile(filepath.Join(root, bootIDStatePath))
+		assert.NoError(t, err)
+		assert.Equal(t, "new-boot\n", string(bootID))
+	})
+
+	t.Run("AgentRestart", func(t *testing.T) {
+		root := setupRoot(t, "same-boot")
+		writeFile(t, filepath.Join(root, bootIDStatePath), "same-boot\n")
+		writeFile(t, filepath.Join(root, pstorePath, "dmesg-ramoops-0"), panicLog)
+		mon, mockManager := newMonitor()
+		assert.NoError(t, mon.handleReboot())
+		assert.Equal(t, 0, len(mockManager.res))
+	})
+
+	t.Run("RebootWithoutCrash", func(t *testing.T) {
+		root := setupRoot(t, "new-boot")
+		writeFile(t, filepath.Join(root, bootIDStatePath), "old-boot\n")
+		mon, mockManager := newMonitor()
+		assert.NoError(t, mon.handleReboot())
+		assert.Equal(t, 0, len(mockManager.res))
+	})
+
+	t.Run("UnexpectedReboot", func(t *testing.T) {
+		root := setupRoot(t, "new-boot")
+		statePath := filepath.Join(root, bootIDStatePath)
+		writeFile(t, statePath, "old-boot\n")
+		assert.NoError(t, os.Chtimes(statePath, time.Now().Add(-2*time.Hour), time.Now().Add(-2*time.Hour)))
+		writeFile(t, filepath.Join(root, pstorePath, "dmesg-ramoops-0"), panicLog)
+		mon, mockManager := newMonitor()
+		assert.NoError(t, mon.handleReboot())
+		monitorResult := <-mockManager.res
+		assert.Equal(t, monitor.SeverityWarning, monitorResult.Severity)
+		assert.Equal(t, "UnexpectedReboot", monitorResult.Reason)
+		assert.Contains(t, monitorResult.Message, "old-boot")
+		assert.Contains(t, monitorResult.Message, "Kernel panic - not syncing: Fatal exception in interrupt")
+
+		bootID, err := os.ReadFile(statePath)
+		assert.NoError(t, err)
+		assert.Equal(t, "new-boot\n", string(bootID))
+	})
+
+	t.Run("UnexpectedRebootArchivedBySystemd", func(t *testing.T) {
+		root := setupRoot(t, "new-boot")
+		writeFile(t, filepath.Join(root, bootIDStatePath), "old-boot\n")
+		// a crash archived during an earlier boot is not attributed to this one.
+		stale := filepath.Join(root, systemdPstorePath, "1700000000", "dmesg-efi-170000000001")
+		writeFile(t, stale, "<0>[ 1.0] Kernel panic - not syncing: stale\n")
+		assert.NoError(t, os.Chtimes(stale, time.Now().Add(-48*time.Hour), time.Now().Add(-48*time.Hour)))
+		writeFile(t, filepath.Join(root, systemdPstorePath, "1800000000", "dmesg-efi-180000000001"), "<4>[ 9.0] BUG: unable to handle page fault for address: 0000000000001000\n")
+		mon, mockManager := newMonitor()
+		assert.NoError(t, mon.handleReboot())
+		monitorResult := <-mockManager.res
+		assert.Equal(t, "UnexpectedReboot", monitorResult.Reason)
+		assert.Contains(t, monitorResult.Message, "BUG: unable to handle page fault")
+		assert.NotContains(t, monitorResult.Message, "stale")
+	})
+
+	t.Run("StaleCrashLeftInPstore", func(t *testing.T) {
+		root := setupRoot(t, "new-boot")
+		statePath := filepath.Join(root, bootIDStatePath)
+		writeFile(t, statePath, "old-boot\n")
+		assert.NoError(t, os.Chtimes(statePath, time.Now().Add(-2*time.Hour), time.Now().Add(-2*time.Hour)))
+		// without systemd-pstore, the record of a crash from before the
+		// previous boot stays in pstore.
+		stale := filepath.Join(root, pstorePath, "dmesg-efi-170000000001")
+		writeFile(t, stale, panicLog)
+		assert.NoError(t, os.Chtimes(stale, time.Now().Add(-48*time.Hour), time.Now().Add(-48*time.Hour)))
+		mon, mockManager := newMonitor()
+		assert.NoError(t, mon.handleReboot())
+		assert.Equal(t, 0, len(mockManager.res))
+
+		// a crash during the previous boot is still reported.
+		writeFile(t, statePath, "old-boot\n")
+		assert.NoError(t, os.Chtimes(statePath, time.Now().Add(-2*time.Hour), time.Now().Add(-2*time.Hour)))
+		crash := filepath.Join(root, pstorePath, "dmesg-efi-180000000001")
+		writeFile(t, crash, "<4>[ 9.0] BUG: unable to handle page fault for address: 0000000000001000\n")
+		assert.NoError(t, os.Chtimes(crash, time.Now().Add(-90*time.Minute), time.Now().Add(-90*time.Minute)))
+		assert.NoError(t, mon.handleReboot())
+		monitorResult := <-mockManager.res
+		assert.Equal(t, "UnexpectedReboot", monitorResult.Reason)
+		assert.Contains(t, monitorResult.Message, "BUG: unable to handle page fault")
+	})
+}
+
+// fakeRuntimeService is a stub cri.RuntimeService that returns canned pod
+// sandboxes. Other interface methods inherit the embedded nil and panic if
+// invoked, surfacing unexpected dependencies.
+type fakeRuntimeService struct {
+	cri.RuntimeService
+
+	sandboxes []*runtimeapi.PodSandbox
+}
+
+func (f *fakeRuntimeService) ListPodSandbox(context.Context, *runtimeapi.PodSandboxFilter) ([]*runtimeapi.PodSandbox, error) {
+	return f.sandboxes, nil
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0644))
 }
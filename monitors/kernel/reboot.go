@@ -0,0 +1,193 @@
+package kernel
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/eks-node-monitoring-agent/pkg/config"
+	"github.com/aws/eks-node-monitoring-agent/pkg/reasons"
+)
+
+const (
+	bootIDPath = "/proc/sys/kernel/random/boot_id"
+	// pstore holds the kernel log of a crashed boot, written by backends such
+	// as ramoops or EFI as "dmesg-<backend>-<id>" files.
+	// see: https://docs.kernel.org/admin-guide/ramoops.html
+	pstorePath = "/sys/fs/pstore"
+	// systemd-pstore moves pstore records into this directory during boot.
+	systemdPstorePath = "/var/lib/systemd/pstore"
+	// maxPanicSummaryLength bounds the summary included in the condition.
+	maxPanicSummaryLength = 256
+)
+
+// bootIDStatePath is where the boot ID seen by the agent is persisted across
+// reboots.
+var bootIDStatePath = filepath.Join(config.StateDir, "boot_id")
+
+// panicSummaryRegexps match the lines of a crash log that summarize the crash,
+// in order of preference.
+var panicSummaryRegexps = []*regexp.Regexp{
+	regexp.MustCompile(`Kernel panic - not syncing: .*`),
+	regexp.MustCompile(`BUG: .*`),
+	regexp.MustCompile(`Oops: .*`),
+}
+
+// handleReboot compares the current boot ID with the one persisted by the
+// previous run of the agent, and when the node has rebooted since, reports an
+// unexpected reboot if pstore holds a crash log from the previous boot.
+func (k *KernelMonitor) handleReboot() error {
+	bootID, err := os.ReadFile(config.ToHostPath(bootIDPath))
+	if err != nil {
+		return err
+	}
+	bootID = bytes.TrimSpace(bootID)
+
+	statePath := config.ToHostPath(bootIDStatePath)
+	lastBootID, err := os.ReadFile(statePath)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	lastBootID = bytes.TrimSpace(lastBootID)
+	// the boot ID is persisted whenever the agent starts, so it was last
+	// persisted during the previous boot, after any earlier crash.
+	var lastBootIDTime time.Time
+	if len(lastBootID) > 0 {
+		info, err := os.Stat(statePath)
+		if err != nil {
+			return err
+		}
+		lastBootIDTime = info.ModTime()
+	}
+
+	if err := os.MkdirAll(filepath.Dir(statePath), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(statePath, append(bootID, '\n'), 0o644); err != nil {
+		return err
+	}
+
+	// nothing was persisted on the first run of the agent on this node, and the
+	// boot ID is unchanged when only the agent restarted.
+	if len(lastBootID) == 0 || bytes.Equal(lastBootID, bootID) {
+		return nil
+	}
+
+	bootTime, err := readBootTime()
+	if err != nil {
+		return err
+	}
+	summary, err := readPanicSummary(lastBootIDTime, bootTime)
+	if err != nil {
+		return err
+	}
+	if summary == "" {
+		k.logger.Info("node rebooted without a crash log", "previousBootID", string(lastBootID))
+		return nil
+	}
+	return k.manager.Notify(context.Background(),
+		reasons.UnexpectedReboot.
+			Builder().
+			Message(fmt.Sprintf("The previous boot %s ended in a kernel crash: %s", lastBootID, summary)).
+			Build(),
+	)
+}
+
+// readBootTime returns the time the node booted, from the btime field of
+// /proc/stat.
+func readBootTime() (time.Time, error) {
+	f, err := os.Open(config.ToHostPath("/proc/stat"))
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if value, ok := strings.CutPrefix(scanner.Text(), "btime "); ok {
+			seconds, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+			if err != nil {
+				return time.Time{}, err
+			}
+			return time.Unix(seconds, 0), nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return time.Time{}, err
+	}
+	return time.Time{}, fmt.Errorf("btime not found in /proc/stat")
+}
+
+// readPanicSummary returns the summary of the crash log left in pstore by the
+// previous boot, or an empty string if there is none. Records left in pstore
+// are only considered if they were written after since, as pstore keeps the
+// records of earlier crashes unless systemd-pstore removes them. Records
+// archived by systemd-pstore are only considered if they were archived during
+// this boot, since the archive keeps the records of every earlier crash.
+func readPanicSummary(since, bootTime time.Time) (string, error) {
+	matches, err := filepath.Glob(config.ToHostPath(filepath.Join(pstorePath, "dmesg-*")))
+	if err != nil {
+		return "", err
+	}
+	var paths []string
+	for _, path := range matches {
+		// pstore dates each record with the time of the crash.
+		info, err := os.Stat(path)
+		if err != nil {
+			return "", err
+		}
+		if info.ModTime().After(since) {
+			paths = append(paths, path)
+		}
+	}
+	if err := filepath.WalkDir(config.ToHostPath(systemdPstorePath), func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return fs.SkipAll
+			}
+			return err
+		}
+		if entry.IsDir() || !strings.HasPrefix(entry.Name(), "dmesg") {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil
+		}
+		if info.ModTime().After(bootTime) {
+			paths = append(paths, path)
+		}
+		return nil
+	}); err != nil {
+		return "", err
+	}
+
+	var logs [][]byte
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", err
+		}
+		logs = append(logs, data)
+	}
+	for _, summaryRegexp := range panicSummaryRegexps {
+		for _, data := range logs {
+			if summary := summaryRegexp.Find(data); summary != nil {
+				summary = bytes.TrimSpace(summary)
+				if len(summary) > maxPanicSummaryLength {
+					summary = summary[:maxPanicSummaryLength]
+				}
+				return string(summary), nil
+			}
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,384 @@
+package dns
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math/rand/v2"
+	"net"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/yaml"
+
+	"github.com/aws/eks-node-monitoring-agent/api/monitor"
+	"github.com/aws/eks-node-monitoring-agent/pkg/config"
+	"github.com/aws/eks-node-monitoring-agent/pkg/pathlib"
+	"github.com/aws/eks-node-monitoring-agent/pkg/reasons"
+)
+
+const (
+	// queryTimeout bounds a single query. The glibc resolver gives up on a
+	// nameserver after 5 seconds by default, so a nameserver that takes longer
+	// than this is already failing lookups of applications.
+	queryTimeout = 2 * time.Second
+	// latencyThreshold is the median latency of the queries to a nameserver
+	// above which it is reported.
+	latencyThreshold = 500 * time.Millisecond
+	// failingRatio is the fraction of the queries to a nameserver which must
+	// fail for it to be reported.
+	failingRatio = 0.5
+	// degradedChecks is the number of consecutive checks that a nameserver must
+	// be failing or slow for before it is reported, so that a single lost
+	// packet is not.
+	degradedChecks = 2
+
+	defaultResolvConf    = "/etc/resolv.conf"
+	defaultClusterDomain = "cluster.local"
+)
+
+// networks are the transports each name is queried over. Responses that do
+// not fit in a UDP packet are retried over TCP by resolvers, and firewalls
+// often treat the two differently.
+var networks = []string{"udp", "tcp"}
+
+// kubeletConfig holds the fields of the kubelet config used to find the
+// nameservers of the node and its pods.
+type kubeletConfig struct {
+	ClusterDNS    []string `json:"clusterDNS"`
+	ClusterDomain string   `json:"clusterDomain"`
+	ResolvConf    string   `json:"resolvConf"`
+}
+
+// nameserver is a nameserver that the node or its pods resolve names with.
+type nameserver struct {
+	address string
+	// source describes where the nameserver is configured.
+	source string
+	names  []string
+}
+
+// queryStats are the results of the queries to a nameserver in a check.
+type queryStats struct {
+	total     int
+	timeouts  int
+	servfails int
+	errors    int
+	latencies []time.Duration
+}
+
+func (s *queryStats) failures() int {
+	return s.timeouts + s.servfails + s.errors
+}
+
+func NewDNSSystem(names []string) *dnsSystem {
+	return &dnsSystem{
+		names:          names,
+		port:           "53",
+		queryTimeout:   queryTimeout,
+		consecutiveBad: map[string]int{},
+	}
+}
+
+type dnsSystem struct {
+	names        []string
+	port         string
+	queryTimeout time.Duration
+
+	// consecutiveBad is the number of consecutive checks that each nameserver
+	// was failing or slow in, keyed by address.
+	consecutiveBad map[string]int
+}
+
+// Resolution queries each of the names against the nameservers of the node
+// and the cluster DNS service of its pods, and reports the nameservers which
+// fail or are slow to answer.
+func (s *dnsSystem) Resolution(ctx context.Context) ([]monitor.Condition, error) {
+	nameservers, err := s.nameservers()
+	if err != nil {
+		return nil, err
+	}
+
+	var conditions []monitor.Condition
+	seen := map[string]bool{}
+	for _, server := range nameservers {
+		seen[server.address] = true
+		stats := s.queryNameserver(ctx, server)
+		log.FromContext(ctx).V(4).Info("queried nameserver", "nameserver", server.address, "total", stats.total, "failures", stats.failures())
+		if condition := s.checkNameserver(server, stats); condition != nil {
+			conditions = append(conditions, *condition)
+		}
+	}
+	// forget nameservers which are no longer configured.
+	for address := range s.consecutiveBad {
+		if !seen[address] {
+			delete(s.consecutiveBad, address)
+		}
+	}
+	return conditions, nil
+}
+
+func (s *dnsSystem) checkNameserver(server nameserver, stats queryStats) *monitor.Condition {
+	if stats.total == 0 {
+		return nil
+	}
+
+	failing := float64(stats.failures()) >= failingRatio*float64(stats.total)
+	var median time.Duration
+	if len(stats.latencies) > 0 {
+		slices.Sort(stats.latencies)
+		median = stats.latencies[len(stats.latencies)/2]
+	}
+	if !failing && median <= latencyThreshold {
+		delete(s.consecutiveBad, server.address)
+		return nil
+	}
+	s.consecutiveBad[server.address]++
+	if s.consecutiveBad[server.address] < degradedChecks {
+		return nil
+	}
+
+	if failing {
+		var failures []string
+		for _, failure := range []struct {
+			count int
+			kind  string
+		}{
+			{stats.timeouts, "timed out"},
+			{stats.servfails, "SERVFAIL"},
+			{stats.errors, "errors"},
+		} {
+			if failure.count > 0 {
+				failures = append(failures, fmt.Sprintf("%d %s", failure.count, failure.kind))
+			}
+		}
+		condition := reasons.DNSResolutionFailing.
+			Builder().
+			Message(fmt.Sprintf("Nameserver %s (%s) failed %d of %d queries: %s", server.address, server.source, stats.failures(), stats.total, strings.Join(failures, ", "))).
+			Build()
+		return &condition
+	}
+	condition := reasons.DNSLatencyHigh.
+		Builder().
+		Message(fmt.Sprintf("Nameserver %s (%s) median query latency %s exceeded %s (max %s, %d queries)", server.address, server.source, median, latencyThreshold, stats.latencies[len(stats.latencies)-1], stats.total)).
+		Build()
+	return &condition
+}
+
+func (s *dnsSystem) queryNameserver(ctx context.Context, server nameserver) queryStats {
+	var stats queryStats
+	for _, name := range server.names {
+		for _, network := range networks {
+			stats.total++
+			start := time.Now()
+			rcode, err := s.query(ctx, network, net.JoinHostPort(server.address, s.port), name)
+			latency := time.Since(start)
+			var netErr net.Error
+			switch {
+			case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+				stats.timeouts++
+			case err != nil:
+				stats.errors++
+			case rcode == dnsmessage.RCodeServerFailure:
+				stats.servfails++
+			default:
+				// any other answer, including NXDOMAIN for a name that does
+				// not exist, means the nameserver is resolving.
+				stats.latencies = append(stats.latencies, latency)
+			}
+		}
+	}
+	return stats
+}
+
+// query sends a query for the A records of the name to the nameserver, and
+// returns the response code of the answer.
+func (s *dnsSystem) query(ctx context.Context, network, address, name string) (dnsmessage.RCode, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.queryTimeout)
+	defer cancel()
+
+	fqdn, err := dnsmessage.NewName(dnsName(name))
+	if err != nil {
+		return 0, err
+	}
+	id := uint16(rand.Uint32())
+	message := dnsmessage.Message{
+		Header:    dnsmessage.Header{ID: id, RecursionDesired: true},
+		Questions: []dnsmessage.Question{{Name: fqdn, Type: dnsmessage.TypeA, Class: dnsmessage.ClassINET}},
+	}
+	packed, err := message.Pack()
+	if err != nil {
+		return 0, err
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, network, address)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return 0, err
+		}
+	}
+
+	var response []byte
+	if network == "tcp" {
+		// messages over TCP are prefixed with their length.
+		if _, err := conn.Write(append(binary.BigEndian.AppendUint16(nil, uint16(len(packed))), packed...)); err != nil {
+			return 0, err
+		}
+		var length [2]byte
+		if _, err := io.ReadFull(conn, length[:]); err != nil {
+			return 0, err
+		}
+		response = make([]byte, binary.BigEndian.Uint16(length[:]))
+		if _, err := io.ReadFull(conn, response); err != nil {
+			return 0, err
+		}
+	} else {
+		if _, err := conn.Write(packed); err != nil {
+			return 0, err
+		}
+		response = make([]byte, 1232)
+		n, err := conn.Read(response)
+		if err != nil {
+			return 0, err
+		}
+		response = response[:n]
+	}
+
+	var parser dnsmessage.Parser
+	header, err := parser.Start(response)
+	if err != nil {
+		return 0, err
+	}
+	if header.ID != id || !header.Response {
+		return 0, fmt.Errorf("unexpected response to query %d", id)
+	}
+	return header.RCode, nil
+}
+
+// dnsName returns the fully qualified form of the name.
+func dnsName(name string) string {
+	if strings.HasSuffix(name, ".") {
+		return name
+	}
+	return name + "."
+}
+
+// nameservers returns the nameservers of the resolv.conf that the kubelet
+// configures for pods with the Default DNS policy, which is the resolv.conf of
+// the host unless overridden, and the cluster DNS service that pods with the
+// ClusterFirst policy use. The cluster DNS service is also queried for the
+// kubernetes service, which it resolves itself rather than forwarding.
+func (s *dnsSystem) nameservers() ([]nameserver, error) {
+	kubeletConfig, err := readKubeletConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	resolvConf := defaultResolvConf
+	if kubeletConfig.ResolvConf != "" {
+		resolvConf = kubeletConfig.ResolvConf
+	}
+	addresses, err := readResolvConf(config.ToHostPath(resolvConf))
+	if err != nil {
+		return nil, err
+	}
+	var nameservers []nameserver
+	for _, address := range addresses {
+		nameservers = append(nameservers, nameserver{address: address, source: resolvConf, names: s.names})
+	}
+
+	clusterDomain := defaultClusterDomain
+	if kubeletConfig.ClusterDomain != "" {
+		clusterDomain = kubeletConfig.ClusterDomain
+	}
+	for _, address := range kubeletConfig.ClusterDNS {
+		nameservers = append(nameservers, nameserver{
+			address: address,
+			source:  "cluster DNS",
+			names:   append(slices.Clone(s.names), "kubernetes.default.svc."+clusterDomain),
+		})
+	}
+	return nameservers, nil
+}
+
+// readResolvConf returns the nameservers of the resolv.conf. A missing file has
+// no nameservers.
+func readResolvConf(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var addresses []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || fields[0] != "nameserver" {
+			continue
+		}
+		if net.ParseIP(fields[1]) != nil {
+			addresses = append(addresses, fields[1])
+		}
+	}
+	return addresses, scanner.Err()
+}
+
+// readKubeletConfig reads the kubelet config and the drop-in configs that
+// override it, in lexical order.
+func readKubeletConfig() (*kubeletConfig, error) {
+	merged := &kubeletConfig{}
+	paths, err := pathlib.ResolveKubeletConfigs(config.HostRoot())
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+		var kubeletConfig kubeletConfig
+		if err := yaml.Unmarshal(data, &kubeletConfig); err != nil {
+			return nil, fmt.Errorf("parsing kubelet config %s: %w", path, err)
+		}
+		if kubeletConfig.ClusterDNS != nil {
+			merged.ClusterDNS = kubeletConfig.ClusterDNS
+		}
+		if kubeletConfig.ClusterDomain != "" {
+			merged.ClusterDomain = kubeletConfig.ClusterDomain
+		}
+		if kubeletConfig.ResolvConf != "" {
+			merged.ResolvConf = kubeletConfig.ResolvConf
+		}
+	}
+	return merged, nil
+}
+
+// ClusterDNS returns the addresses of the cluster DNS service from the kubelet
+// config.
+func ClusterDNS() ([]string, error) {
+	kubeletConfig, err := readKubeletConfig()
+	if err != nil {
+		return nil, err
+	}
+	return kubeletConfig.ClusterDNS, nil
+}
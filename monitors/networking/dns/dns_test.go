@@ -0,0 +1,228 @@
+package dns
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/dns/dnsmessage"
+
+	"github.com/aws/eks-node-monitoring-agent/api/monitor"
+	"github.com/aws/eks-node-monitoring-agent/pkg/config"
+)
+
+// fakeNameserver is an in-process nameserver listening on the same UDP and
+// TCP port of the loopback address, which answers every query with rcode
+// after delay, or never answers when drop is set.
+type fakeNameserver struct {
+	port  string
+	rcode atomic.Int32
+	delay atomic.Int64
+	drop  atomic.Bool
+}
+
+func startFakeNameserver(t *testing.T) *fakeNameserver {
+	var tcp net.Listener
+	var udp net.PacketConn
+	// the port is chosen for TCP, and may already be in use for UDP.
+	for {
+		var err error
+		tcp, err = net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		udp, err = net.ListenPacket("udp", tcp.Addr().String())
+		if err == nil {
+			break
+		}
+		tcp.Close()
+	}
+	t.Cleanup(func() {
+		tcp.Close()
+		udp.Close()
+	})
+
+	_, port, _ := net.SplitHostPort(tcp.Addr().String())
+	server := &fakeNameserver{port: port}
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := udp.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if response := server.answer(buf[:n]); response != nil {
+				udp.WriteTo(response, addr)
+			}
+		}
+	}()
+	go func() {
+		for {
+			conn, err := tcp.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				var length [2]byte
+				if _, err := io.ReadFull(conn, length[:]); err != nil {
+					return
+				}
+				query := make([]byte, binary.BigEndian.Uint16(length[:]))
+				if _, err := io.ReadFull(conn, query); err != nil {
+					return
+				}
+				if response := server.answer(query); response != nil {
+					conn.Write(append(binary.BigEndian.AppendUint16(nil, uint16(len(response))), response...))
+				} else {
+					// hold the connection open until the client gives up.
+					io.Copy(io.Discard, conn)
+				}
+			}()
+		}
+	}()
+	return server
+}
+
+func (f *fakeNameserver) answer(query []byte) []byte {
+	if f.drop.Load() {
+		return nil
+	}
+	time.Sleep(time.Duration(f.delay.Load()))
+	var parser dnsmessage.Parser
+	header, err := parser.Start(query)
+	if err != nil {
+		return nil
+	}
+	question, err := parser.Question()
+	if err != nil {
+		return nil
+	}
+	response := dnsmessage.Message{
+		Header:    dnsmessage.Header{ID: header.ID, Response: true, RCode: dnsmessage.RCode(f.rcode.Load())},
+		Questions: []dnsmessage.Question{question},
+	}
+	packed, _ := response.Pack()
+	return packed
+}
+
+func writeFile(t *testing.T, path, content string) {
+	path = config.ToHostPath(path)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func TestNameservers(t *testing.T) {
+	t.Setenv(config.HOST_ROOT_ENV, t.TempDir())
+	writeFile(t, "/etc/kubernetes/kubelet/config.json", `{"clusterDNS": ["10.100.0.10"], "resolvConf": "/run/systemd/resolve/resolv.conf"}`)
+	writeFile(t, "/etc/kubernetes/kubelet/config.json.d/00-nodeadm.conf", `{"clusterDomain": "example.internal"}`)
+	writeFile(t, "/run/systemd/resolve/resolv.conf", `# This is /run/systemd/resolve/resolv.conf managed by man:systemd-resolved(8).
+nameserver 10.0.0.2
+nameserver fd00:ec2::253
+nameserver not-an-address
+search us-west-2.compute.internal
+`)
+
+	nameservers, err := NewDNSSystem([]string{"amazonaws.com"}).nameservers()
+	assert.NoError(t, err)
+	assert.Equal(t, []nameserver{
+		{address: "10.0.0.2", source: "/run/systemd/resolve/resolv.conf", names: []string{"amazonaws.com"}},
+		{address: "fd00:ec2::253", source: "/run/systemd/resolve/resolv.conf", names: []string{"amazonaws.com"}},
+		{address: "10.100.0.10", source: "cluster DNS", names: []string{"amazonaws.com", "kubernetes.default.svc.example.internal"}},
+	}, nameservers)
+}
+
+func TestResolution(t *testing.T) {
+	t.Setenv(config.HOST_ROOT_ENV, t.TempDir())
+	writeFile(t, "/etc/resolv.conf", "nameserver 127.0.0.1\n")
+
+	newSystem := func(server *fakeNameserver) *dnsSystem {
+		system := NewDNSSystem([]string{"amazonaws.com"})
+		system.port = server.port
+		system.queryTimeout = 100 * time.Millisecond
+		return system
+	}
+	check := func(t *testing.T, system *dnsSystem, checks int) []monitor.Condition {
+		var conditions []monitor.Condition
+		for range checks {
+			var err error
+			conditions, err = system.Resolution(context.TODO())
+			require.NoError(t, err)
+		}
+		return conditions
+	}
+
+	t.Run("Healthy", func(t *testing.T) {
+		server := startFakeNameserver(t)
+		// a name which does not exist is still an answer.
+		server.rcode.Store(int32(dnsmessage.RCodeNameError))
+		assert.Empty(t, check(t, newSystem(server), 3))
+	})
+
+	t.Run("Timeouts", func(t *testing.T) {
+		server := startFakeNameserver(t)
+		server.drop.Store(true)
+		system := newSystem(server)
+		assert.Empty(t, check(t, system, 1))
+		assert.Equal(t, []monitor.Condition{{
+			Reason:   "DNSResolutionFailing",
+			Message:  "Nameserver 127.0.0.1 (/etc/resolv.conf) failed 2 of 2 queries: 2 timed out",
+			Severity: monitor.SeverityWarning,
+		}}, check(t, system, 1))
+
+		// the nameserver recovering resets the consecutive checks.
+		server.drop.Store(false)
+		assert.Empty(t, check(t, system, 1))
+		server.drop.Store(true)
+		assert.Empty(t, check(t, system, 1))
+	})
+
+	t.Run("SERVFAIL", func(t *testing.T) {
+		server := startFakeNameserver(t)
+		server.rcode.Store(int32(dnsmessage.RCodeServerFailure))
+		assert.Equal(t, []monitor.Condition{{
+			Reason:   "DNSResolutionFailing",
+			Message:  "Nameserver 127.0.0.1 (/etc/resolv.conf) failed 2 of 2 queries: 2 SERVFAIL",
+			Severity: monitor.SeverityWarning,
+		}}, check(t, newSystem(server), 2))
+	})
+
+	t.Run("Slow", func(t *testing.T) {
+		server := startFakeNameserver(t)
+		server.delay.Store(int64(600 * time.Millisecond))
+		system := newSystem(server)
+		system.queryTimeout = time.Second
+		conditions := check(t, system, 2)
+		require.Len(t, conditions, 1)
+		assert.Equal(t, "DNSLatencyHigh", conditions[0].Reason)
+		assert.Contains(t, conditions[0].Message, "Nameserver 127.0.0.1 (/etc/resolv.conf) median query latency 6")
+	})
+
+	t.Run("ClusterDNS", func(t *testing.T) {
+		server := startFakeNameserver(t)
+		server.rcode.Store(int32(dnsmessage.RCodeServerFailure))
+		writeFile(t, "/etc/resolv.conf", "")
+		writeFile(t, "/etc/kubernetes/kubelet/config.json", `{"clusterDNS": ["127.0.0.1"]}`)
+		assert.Equal(t, []monitor.Condition{{
+			Reason:   "DNSResolutionFailing",
+			Message:  "Nameserver 127.0.0.1 (cluster DNS) failed 4 of 4 queries: 4 SERVFAIL",
+			Severity: monitor.SeverityWarning,
+		}}, check(t, newSystem(server), 2))
+	})
+}
+
+func TestDNSName(t *testing.T) {
+	for name, expected := range map[string]string{
+		"amazonaws.com":  "amazonaws.com.",
+		"amazonaws.com.": "amazonaws.com.",
+	} {
+		assert.Equal(t, expected, dnsName(name), strconv.Quote(name))
+	}
+}
@@ -0,0 +1,179 @@
+package networking
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aws/eks-node-monitoring-agent/pkg/osext"
+	"github.com/aws/eks-node-monitoring-agent/pkg/reasons"
+)
+
+const (
+	// interfaceDropRateThreshold is the rate of packets per second dropped by
+	// an interface above which it is reported.
+	interfaceDropRateThreshold = 10.0
+	// softnetDropRateThreshold is the rate of packets per second dropped from
+	// the backlog of a CPU above which it is reported.
+	softnetDropRateThreshold = 1.0
+)
+
+// interfaceDropCounters are the counters of /sys/class/net/*/statistics of
+// packets that an interface drops without reporting them otherwise.
+var interfaceDropCounters = []string{"rx_dropped", "rx_missed_errors", "tx_errors"}
+
+// packetDropSample is the drop counters of the interfaces and CPUs at a
+// point in time, keyed by interface and then by counter, and by CPU.
+type packetDropSample struct {
+	time       time.Time
+	interfaces map[string]map[string]uint64
+	softnet    map[int]osext.SoftnetStat
+}
+
+// dropRate is the rates of the drop counters of an interface or CPU, in
+// packets per second, and the total rate that they are ranked by.
+type dropRate[K cmp.Ordered] struct {
+	key   K
+	total float64
+	rates []float64
+}
+
+// ~~~~ packet drops ~~~~
+
+func (m *NetworkingMonitor) handlePacketDrops() error {
+	netInterfaces, err := net.Interfaces()
+	if err != nil {
+		return err
+	}
+	sample := packetDropSample{
+		time:       time.Now(),
+		interfaces: make(map[string]map[string]uint64),
+		softnet:    make(map[int]osext.SoftnetStat),
+	}
+	var merr error
+	for _, netInterface := range netInterfaces {
+		if m.isInterfaceExcluded(netInterface.Name) {
+			continue
+		}
+		stats, err := osext.ReadInterfaceStatistics(netInterface.Name, interfaceDropCounters...)
+		if err != nil {
+			// interfaces like the veths of pods come and go between listing
+			// and reading them.
+			if !errors.Is(err, fs.ErrNotExist) {
+				merr = errors.Join(merr, err)
+			}
+			continue
+		}
+		sample.interfaces[netInterface.Name] = stats
+	}
+	softnet, err := osext.ReadSoftnetStat()
+	if err != nil {
+		return errors.Join(merr, err)
+	}
+	for _, stat := range softnet {
+		sample.softnet[stat.CPU] = stat
+	}
+	return errors.Join(merr, m.checkPacketDrops(sample))
+}
+
+// checkPacketDrops reports the interface and the CPU that dropped packets at
+// the highest rate since the previous sample.
+func (m *NetworkingMonitor) checkPacketDrops(sample packetDropSample) (merr error) {
+	prev := m.packetDrops
+	m.packetDrops = &sample
+	if prev == nil {
+		// the first sample only sets the baseline, so that drops from before
+		// the agent started are not reported.
+		return nil
+	}
+	elapsed := sample.time.Sub(prev.time)
+	if elapsed <= 0 {
+		return nil
+	}
+
+	var interfaceRates []dropRate[string]
+	for name, stats := range sample.interfaces {
+		if rate, ok := interfaceDropRates(name, prev.interfaces[name], stats, elapsed); ok && rate.total >= interfaceDropRateThreshold {
+			interfaceRates = append(interfaceRates, rate)
+		}
+	}
+	if len(interfaceRates) > 0 {
+		worst := slices.MaxFunc(interfaceRates, compareDropRates)
+		var counters []string
+		for i, counter := range interfaceDropCounters {
+			counters = append(counters, fmt.Sprintf("%s %0.1f/s", counter, worst.rates[i]))
+		}
+		message := fmt.Sprintf("Interface %q dropped %0.1f packets/s over the last %s (%s)", worst.key, worst.total, elapsed.Round(time.Second), strings.Join(counters, ", "))
+		if len(interfaceRates) > 1 {
+			message += fmt.Sprintf(", the most of %d interfaces dropping packets", len(interfaceRates))
+		}
+		merr = errors.Join(merr, m.manager.Notify(context.TODO(),
+			reasons.InterfacePacketDrops.
+				Builder().
+				Message(message).
+				Build(),
+		))
+	}
+
+	var softnetRates []dropRate[int]
+	for cpu, stat := range sample.softnet {
+		prevStat, ok := prev.softnet[cpu]
+		if !ok {
+			continue
+		}
+		if stat.Dropped < prevStat.Dropped || stat.TimeSqueeze < prevStat.TimeSqueeze {
+			continue
+		}
+		// only the dropped packets count towards the threshold, since a
+		// squeeze defers the remaining packets to the next poll.
+		dropped := float64(stat.Dropped-prevStat.Dropped) / elapsed.Seconds()
+		squeezed := float64(stat.TimeSqueeze-prevStat.TimeSqueeze) / elapsed.Seconds()
+		if dropped >= softnetDropRateThreshold {
+			softnetRates = append(softnetRates, dropRate[int]{key: cpu, total: dropped, rates: []float64{dropped, squeezed}})
+		}
+	}
+	if len(softnetRates) > 0 {
+		worst := slices.MaxFunc(softnetRates, compareDropRates)
+		message := fmt.Sprintf("CPU %d dropped %0.1f packets/s over the last %s because its backlog, bounded by net.core.netdev_max_backlog, was full, and ran out of net.core.netdev_budget %0.1f times/s (time_squeeze)", worst.key, worst.rates[0], elapsed.Round(time.Second), worst.rates[1])
+		if len(softnetRates) > 1 {
+			message += fmt.Sprintf(", the most of %d CPUs dropping packets", len(softnetRates))
+		}
+		merr = errors.Join(merr, m.manager.Notify(context.TODO(),
+			reasons.SoftnetBacklogDrops.
+				Builder().
+				Message(message).
+				Build(),
+		))
+	}
+	return merr
+}
+
+// interfaceDropRates returns the rate of each drop counter of an interface
+// between two samples. It returns false when the interface is new, or its counters were
+// reset, e.g. when the device was recreated.
+func interfaceDropRates(name string, prev, current map[string]uint64, elapsed time.Duration) (dropRate[string], bool) {
+	rate := dropRate[string]{key: name}
+	if prev == nil {
+		return rate, false
+	}
+	for _, counter := range interfaceDropCounters {
+		if current[counter] < prev[counter] {
+			return rate, false
+		}
+		value := float64(current[counter]-prev[counter]) / elapsed.Seconds()
+		rate.rates = append(rate.rates, value)
+		rate.total += value
+	}
+	return rate, true
+}
+
+func compareDropRates[K cmp.Ordered](a, b dropRate[K]) int {
+	// break ties by the lowest key, so that the report is stable.
+	return cmp.Or(cmp.Compare(a.total, b.total), cmp.Compare(b.key, a.key))
+}
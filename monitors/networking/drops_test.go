@@ -0,0 +1,97 @@
+package networking
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aws/eks-node-monitoring-agent/api/monitor"
+	"github.com/aws/eks-node-monitoring-agent/pkg/config"
+	"github.com/aws/eks-node-monitoring-agent/pkg/observer"
+	"github.com/aws/eks-node-monitoring-agent/pkg/osext"
+)
+
+func TestPacketDrops(t *testing.T) {
+	mon := NewNetworkingMonitor()
+	mockManager := &mockManager{
+		obs: observer.BaseObserver{},
+		res: make(chan monitor.Condition, 5),
+	}
+	mon.Register(context.TODO(), mockManager)
+
+	start := time.Now()
+	sample := func(elapsed time.Duration, interfaces map[string]map[string]uint64, softnet ...osext.SoftnetStat) packetDropSample {
+		sample := packetDropSample{time: start.Add(elapsed), interfaces: interfaces, softnet: make(map[int]osext.SoftnetStat)}
+		for _, stat := range softnet {
+			sample.softnet[stat.CPU] = stat
+		}
+		return sample
+	}
+	counters := func(rxDropped, rxMissed, txErrors uint64) map[string]uint64 {
+		return map[string]uint64{"rx_dropped": rxDropped, "rx_missed_errors": rxMissed, "tx_errors": txErrors}
+	}
+
+	// the counters from before the first sample are not reported.
+	assert.NoError(t, mon.checkPacketDrops(sample(0, map[string]map[string]uint64{
+		"eth0": counters(1000000, 0, 0),
+		"eth1": counters(0, 0, 0),
+		"eth2": counters(0, 0, 0),
+	}, osext.SoftnetStat{CPU: 0, Dropped: 1000000}, osext.SoftnetStat{CPU: 1})))
+	assert.Empty(t, mockManager.res)
+
+	assert.NoError(t, mon.checkPacketDrops(sample(5*time.Minute, map[string]map[string]uint64{
+		"eth0": counters(1000000+300, 0, 0),
+		"eth1": counters(3000, 6000, 300),
+		"eth2": counters(3000, 0, 0),
+		// a new interface is only reported from its second sample.
+		"eth3": counters(1000000, 0, 0),
+	}, osext.SoftnetStat{CPU: 0, Dropped: 1000000 + 600, TimeSqueeze: 3000}, osext.SoftnetStat{CPU: 1, Dropped: 900})))
+	if assert.Len(t, mockManager.res, 2) {
+		monitorResult := <-mockManager.res
+		assert.Equal(t, "InterfacePacketDrops", monitorResult.Reason)
+		assert.Equal(t, monitor.SeverityWarning, monitorResult.Severity)
+		assert.Equal(t, `Interface "eth1" dropped 31.0 packets/s over the last 5m0s (rx_dropped 10.0/s, rx_missed_errors 20.0/s, tx_errors 1.0/s), the most of 2 interfaces dropping packets`, monitorResult.Message)
+		monitorResult = <-mockManager.res
+		assert.Equal(t, "SoftnetBacklogDrops", monitorResult.Reason)
+		assert.Equal(t, monitor.SeverityWarning, monitorResult.Severity)
+		assert.Equal(t, "CPU 1 dropped 3.0 packets/s over the last 5m0s because its backlog, bounded by net.core.netdev_max_backlog, was full, and ran out of net.core.netdev_budget 0.0 times/s (time_squeeze), the most of 2 CPUs dropping packets", monitorResult.Message)
+	}
+
+	// counters that were reset are skipped until the next sample.
+	assert.NoError(t, mon.checkPacketDrops(sample(10*time.Minute, map[string]map[string]uint64{
+		"eth1": counters(0, 0, 0),
+	}, osext.SoftnetStat{CPU: 0, Dropped: 0}, osext.SoftnetStat{CPU: 1, Dropped: 900})))
+	assert.Empty(t, mockManager.res)
+}
+
+func TestHandlePacketDrops(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv(config.HOST_ROOT_ENV, root)
+	writeSoftnetStat := func(dropped int) {
+		assert.NoError(t, os.MkdirAll(filepath.Join(root, "proc/net"), 0755))
+		assert.NoError(t, os.WriteFile(filepath.Join(root, "proc/net/softnet_stat"), []byte(fmt.Sprintf("00000010 %08x 00000000\n", dropped)), 0644))
+	}
+	writeSoftnetStat(0)
+
+	mon := NewNetworkingMonitor()
+	mockManager := &mockManager{
+		obs: observer.BaseObserver{},
+		res: make(chan monitor.Condition, 5),
+	}
+	mon.Register(context.TODO(), mockManager)
+	// the interfaces of the host are not in the host root, and are skipped.
+	assert.NoError(t, mon.handlePacketDrops())
+	assert.Empty(t, mon.packetDrops.interfaces)
+	assert.Equal(t, map[int]osext.SoftnetStat{0: {CPU: 0, Processed: 0x10}}, mon.packetDrops.softnet)
+
+	writeSoftnetStat(1 << 20)
+	assert.NoError(t, mon.handlePacketDrops())
+	if assert.Len(t, mockManager.res, 1) {
+		assert.Equal(t, "SoftnetBacklogDrops", (<-mockManager.res).Reason)
+	}
+}
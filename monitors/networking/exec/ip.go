@@ -3,46 +3,33 @@
 package exec
 
 import (
-	"errors"
 	"strings"
 
 	"github.com/aws/eks-node-monitoring-agent/pkg/config"
 	"github.com/aws/eks-node-monitoring-agent/pkg/osext"
 )
 
-func GetRules() (rules []string, merr error) {
-	rules = []string{}
-	for _, cmd := range [][]string{
-		{"ip", "rule", "show"},
-		{"ip", "-6", "rule", "show"},
-	} {
-		out, err := osext.NewExec(config.HostRoot()).Command(cmd[0], cmd[1:]...).CombinedOutput()
-		if err != nil {
-			merr = errors.Join(merr, err)
-			continue
-		}
-		for _, line := range strings.Split(string(out), "\n") {
-			rules = append(rules, line)
-		}
-	}
+// Family is the address family of the rules and routes to list, as the flag
+// passed to `ip`.
+type Family string
+
+const (
+	FamilyIPv4 Family = "-4"
+	FamilyIPv6 Family = "-6"
+)
+
+func GetRules(family Family) ([]string, error) {
+	return ip(string(family), "rule", "show")
+}
 
-	return rules, nil
+func GetRoutes(family Family) ([]string, error) {
+	return ip(string(family), "route", "show", "table", "all")
 }
 
-func GetRoutes() (routes []string, merr error) {
-	routes = []string{}
-	for _, cmd := range [][]string{
-		{"ip", "route", "show", "table", "all"},
-		{"ip", "-6", "route", "show", "table", "all"},
-	} {
-		out, err := osext.NewExec(config.HostRoot()).Command(cmd[0], cmd[1:]...).CombinedOutput()
-		if err != nil {
-			merr = errors.Join(merr, err)
-			continue
-		}
-		for _, line := range strings.Split(string(out), "\n") {
-			routes = append(routes, line)
-		}
+func ip(args ...string) ([]string, error) {
+	out, err := osext.NewExec(config.HostRoot()).Command("ip", args...).CombinedOutput()
+	if err != nil {
+		return nil, err
 	}
-	return routes, nil
+	return strings.Split(string(out), "\n"), nil
 }
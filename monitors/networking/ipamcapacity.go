@@ -0,0 +1,174 @@
+package networking
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aws/amazon-vpc-cni-k8s/pkg/ipamd/datastore"
+
+	"github.com/aws/eks-node-monitoring-agent/internal/pkg/instanceinfo"
+	"github.com/aws/eks-node-monitoring-agent/monitors/networking/ipamd"
+	"github.com/aws/eks-node-monitoring-agent/pkg/config"
+	"github.com/aws/eks-node-monitoring-agent/pkg/reasons"
+)
+
+const (
+	// ipamCapacityLowRatio is the share of the pod addresses of the node below
+	// which the free addresses are reported.
+	ipamCapacityLowRatio = 0.1
+	// ipamExhaustionHorizon is how soon the free addresses must run out at the
+	// current scheduling rate to be reported.
+	ipamExhaustionHorizon = 30 * time.Minute
+	// ipamRateWindow is the window of the samples that the scheduling rate is
+	// measured over.
+	ipamRateWindow = 30 * time.Minute
+	// ipv4PrefixSize is the number of addresses of a /28 prefix, which ipamd
+	// attaches instead of secondary addresses with prefix delegation.
+	ipv4PrefixSize = 16
+)
+
+// ipamSample is the number of assigned pod addresses at a point in time.
+type ipamSample struct {
+	time     time.Time
+	assigned int
+}
+
+// ipamCapacity is the pod address capacity of the node.
+type ipamCapacity struct {
+	// eniSlots and maxENIs are the ENIs that can still be attached, out of the
+	// ENIs of the instance type.
+	eniSlots int
+	maxENIs  int
+	// podENIs are the attached ENIs that hold pod addresses.
+	podENIs  int
+	prefixes int
+	// allocated are the addresses attached to the ENIs, and assigned the ones
+	// in use by pods.
+	allocated int
+	assigned  int
+	// maximum is the number of pod addresses once every ENI of the instance
+	// type is attached and full.
+	maximum int
+}
+
+func (c ipamCapacity) free() int {
+	return c.maximum - c.assigned
+}
+
+// ~~~~ ipam capacity ~~~~
+
+func (m *NetworkingMonitor) handleIPAMCapacity() error {
+	if slices.Contains(m.runtimeContext.Tags(), config.Hybrid) {
+		// Hybrid nodes do not have ipamd
+		return nil
+	}
+	if !slices.Contains(m.runtimeContext.Tags(), config.EKSAuto) {
+		if _, isInstalled, err := m.isVPCCNIInstalled(); err != nil {
+			return fmt.Errorf("failed to check if the VPC CNI is installed: %w", err)
+		} else if !isInstalled {
+			return nil
+		}
+	}
+
+	info, err := m.instanceInfoProvider.GetInstanceInfo(context.TODO())
+	if errors.Is(err, instanceinfo.ErrUnknownInstanceType) {
+		m.log.V(4).Info("unknown instance type, skipping IPAMD capacity analysis", "error", err)
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	enis, err := ipamd.GetEndpoint(ipamd.EndpointEnis)
+	if err != nil {
+		return err
+	}
+	return m.checkIPAMCapacity(enis, info, time.Now())
+}
+
+func (m *NetworkingMonitor) checkIPAMCapacity(enis *datastore.ENIInfos, info *instanceinfo.InstanceInfo, now time.Time) error {
+	if info.MaximumNetworkInterfaces == 0 || info.IPv4AddressesPerInterface == 0 || len(enis.ENIs) == 0 {
+		return nil
+	}
+	for _, eni := range enis.ENIs {
+		if len(eni.IPv6Cidrs) > 0 {
+			// IPv6 clusters assign pods addresses from a /80 prefix, which
+			// does not run out.
+			return nil
+		}
+	}
+	capacity := readIPAMCapacity(enis, info)
+
+	m.ipamSamples = append(slices.DeleteFunc(m.ipamSamples, func(sample ipamSample) bool {
+		return now.Sub(sample.time) > ipamRateWindow
+	}), ipamSample{time: now, assigned: capacity.assigned})
+	// the rate that pods are scheduled onto the node, net of the pods that
+	// are deleted, in addresses per minute.
+	var rate float64
+	if oldest := m.ipamSamples[0]; now.Sub(oldest.time) > 0 {
+		rate = float64(capacity.assigned-oldest.assigned) / now.Sub(oldest.time).Minutes()
+	}
+
+	var exhaustion time.Duration
+	if rate > 0 {
+		exhaustion = time.Duration(float64(capacity.free()) / rate * float64(time.Minute))
+	}
+	lowCapacity := float64(capacity.free()) < ipamCapacityLowRatio*float64(capacity.maximum)
+	if !lowCapacity && (rate <= 0 || exhaustion > ipamExhaustionHorizon) {
+		return nil
+	}
+
+	var message strings.Builder
+	fmt.Fprintf(&message, "%d of %d pod IP addresses are free (%d assigned of %d allocated on %d ENIs", capacity.free(), capacity.maximum, capacity.assigned, capacity.allocated, capacity.podENIs)
+	if capacity.prefixes > 0 {
+		fmt.Fprintf(&message, " in %d prefixes", capacity.prefixes)
+	}
+	fmt.Fprintf(&message, ", %d of %d ENIs can still be attached)", capacity.eniSlots, capacity.maxENIs)
+	if rate > 0 {
+		fmt.Fprintf(&message, ", and pods are scheduled at %0.1f per minute, which exhausts them in %s", rate, exhaustion.Round(time.Minute))
+	}
+	return m.manager.Notify(context.TODO(),
+		reasons.IPAMDCapacityLow.
+			Builder().
+			Message(message.String()).
+			MinOccurrences(1).
+			Build(),
+	)
+}
+
+// readIPAMCapacity finds the pod address capacity of the node from the ENIs of
+// ipamd and the limits of the instance type.
+func readIPAMCapacity(enis *datastore.ENIInfos, info *instanceinfo.InstanceInfo) ipamCapacity {
+	capacity := ipamCapacity{
+		maxENIs:  info.MaximumNetworkInterfaces,
+		eniSlots: max(info.MaximumNetworkInterfaces-len(enis.ENIs), 0),
+	}
+	prefixMode := false
+	for _, eni := range enis.ENIs {
+		if eni.IsTrunk || eni.IsExcludedForPodIPs || len(eni.AvailableIPv4Cidrs) == 0 {
+			// the ENI takes a slot of the instance type without holding pod
+			// addresses, like the primary ENI with custom networking.
+			continue
+		}
+		capacity.podENIs++
+		for _, cidr := range eni.AvailableIPv4Cidrs {
+			capacity.allocated += cidr.Size()
+			capacity.assigned += cidr.AssignedIPAddressesInCidr()
+			if cidr.IsPrefix {
+				capacity.prefixes++
+				prefixMode = true
+			}
+		}
+	}
+	// the primary address of each ENI is not assigned to pods, and the other
+	// addresses are either secondary addresses or /28 prefixes.
+	perENI := info.IPv4AddressesPerInterface - 1
+	if prefixMode {
+		perENI *= ipv4PrefixSize
+	}
+	capacity.maximum = max((capacity.podENIs+capacity.eniSlots)*perENI, capacity.allocated)
+	return capacity
+}
@@ -0,0 +1,187 @@
+package networking
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/aws/amazon-vpc-cni-k8s/pkg/ipamd/datastore"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aws/eks-node-monitoring-agent/api/monitor"
+	"github.com/aws/eks-node-monitoring-agent/internal/pkg/instanceinfo"
+	"github.com/aws/eks-node-monitoring-agent/pkg/config"
+	"github.com/aws/eks-node-monitoring-agent/pkg/observer"
+)
+
+type fakeInstanceInfoProvider struct {
+	info *instanceinfo.InstanceInfo
+	err  error
+}
+
+func (f *fakeInstanceInfoProvider) GetInstanceInfo(context.Context) (*instanceinfo.InstanceInfo, error) {
+	return f.info, f.err
+}
+
+// makeENI returns an ENI with cidrs of the given prefix length starting at
+// base, of which the first assigned addresses are assigned to pods.
+func makeENI(base string, cidrs, bits, assigned int) datastore.ENI {
+	eni := datastore.ENI{AvailableIPv4Cidrs: make(map[string]*datastore.CidrInfo)}
+	addr := netip.MustParseAddr(base)
+	for range cidrs {
+		cidr := &datastore.CidrInfo{
+			Cidr:        net.IPNet{IP: addr.AsSlice(), Mask: net.CIDRMask(bits, 32)},
+			IPAddresses: make(map[string]*datastore.AddressInfo),
+			IsPrefix:    bits != 32,
+		}
+		for range 1 << (32 - bits) {
+			if assigned > 0 {
+				cidr.IPAddresses[addr.String()] = &datastore.AddressInfo{
+					Address: addr.String(),
+					IPAMKey: datastore.IPAMKey{NetworkName: "aws-cni", ContainerID: fmt.Sprint(assigned), IfName: "eth0"},
+				}
+				assigned--
+			}
+			addr = addr.Next()
+		}
+		eni.AvailableIPv4Cidrs[cidr.Cidr.String()] = cidr
+	}
+	return eni
+}
+
+func TestIPAMCapacity(t *testing.T) {
+	// m5.xlarge
+	info := &instanceinfo.InstanceInfo{InstanceType: "m5.xlarge", MaximumNetworkInterfaces: 4, IPv4AddressesPerInterface: 15}
+
+	for _, testCase := range []struct {
+		name    string
+		enis    map[string]datastore.ENI
+		message string
+	}{
+		{
+			name: "SecondaryAddresses",
+			enis: map[string]datastore.ENI{
+				"eni-1": makeENI("10.0.0.10", 14, 32, 14),
+				"eni-2": makeENI("10.0.1.10", 14, 32, 3),
+			},
+		},
+		{
+			name: "SecondaryAddressesLow",
+			enis: map[string]datastore.ENI{
+				"eni-1": makeENI("10.0.0.10", 14, 32, 14),
+				"eni-2": makeENI("10.0.1.10", 14, 32, 14),
+				"eni-3": makeENI("10.0.2.10", 14, 32, 14),
+				"eni-4": makeENI("10.0.3.10", 14, 32, 10),
+			},
+			message: "4 of 56 pod IP addresses are free (52 assigned of 56 allocated on 4 ENIs, 0 of 4 ENIs can still be attached)",
+		},
+		{
+			name: "SlotsTakenWithoutPodAddresses",
+			enis: map[string]datastore.ENI{
+				// the primary ENI with custom networking, and a trunk ENI.
+				"eni-1": {IsPrimary: true},
+				"eni-2": {IsTrunk: true, AvailableIPv4Cidrs: makeENI("10.0.1.10", 1, 32, 0).AvailableIPv4Cidrs},
+				"eni-3": makeENI("10.0.2.10", 14, 32, 14),
+				"eni-4": makeENI("10.0.3.10", 14, 32, 13),
+			},
+			message: "1 of 28 pod IP addresses are free (27 assigned of 28 allocated on 2 ENIs, 0 of 4 ENIs can still be attached)",
+		},
+		{
+			name: "Prefixes",
+			enis: map[string]datastore.ENI{
+				"eni-1": makeENI("10.0.0.16", 2, 28, 20),
+			},
+		},
+		{
+			name: "PrefixesLow",
+			enis: map[string]datastore.ENI{
+				"eni-1": makeENI("10.0.0.0", 14, 28, 224),
+				"eni-2": makeENI("10.1.0.0", 14, 28, 224),
+				"eni-3": makeENI("10.2.0.0", 14, 28, 224),
+				"eni-4": makeENI("10.3.0.0", 14, 28, 180),
+			},
+			message: "44 of 896 pod IP addresses are free (852 assigned of 896 allocated on 4 ENIs in 56 prefixes, 0 of 4 ENIs can still be attached)",
+		},
+		{
+			name: "IPv6",
+			enis: map[string]datastore.ENI{
+				"eni-1": {IsPrimary: true, IPv6Cidrs: map[string]*datastore.CidrInfo{"2001:db8::/80": {}}},
+			},
+		},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			mon := NewNetworkingMonitor()
+			mockManager := &mockManager{
+				obs: observer.BaseObserver{},
+				res: make(chan monitor.Condition, 5),
+			}
+			mon.Register(context.TODO(), mockManager)
+			assert.NoError(t, mon.checkIPAMCapacity(&datastore.ENIInfos{ENIs: testCase.enis}, info, time.Now()))
+			if testCase.message == "" {
+				assert.Empty(t, mockManager.res)
+				return
+			}
+			if assert.Len(t, mockManager.res, 1) {
+				monitorResult := <-mockManager.res
+				assert.Equal(t, "IPAMDCapacityLow", monitorResult.Reason)
+				assert.Equal(t, monitor.SeverityWarning, monitorResult.Severity)
+				assert.Equal(t, testCase.message, monitorResult.Message)
+			}
+		})
+	}
+}
+
+func TestIPAMCapacitySchedulingRate(t *testing.T) {
+	info := &instanceinfo.InstanceInfo{InstanceType: "m5.xlarge", MaximumNetworkInterfaces: 4, IPv4AddressesPerInterface: 15}
+	mon := NewNetworkingMonitor()
+	mockManager := &mockManager{
+		obs: observer.BaseObserver{},
+		res: make(chan monitor.Condition, 5),
+	}
+	mon.Register(context.TODO(), mockManager)
+
+	start := time.Now()
+	assert.NoError(t, mon.checkIPAMCapacity(&datastore.ENIInfos{ENIs: map[string]datastore.ENI{
+		"eni-1": makeENI("10.0.0.10", 14, 32, 3),
+	}}, info, start))
+	assert.Empty(t, mockManager.res)
+
+	// 30 pods in 10 minutes leave 23 addresses for less than 8 minutes.
+	assert.NoError(t, mon.checkIPAMCapacity(&datastore.ENIInfos{ENIs: map[string]datastore.ENI{
+		"eni-1": makeENI("10.0.0.10", 14, 32, 14),
+		"eni-2": makeENI("10.0.1.10", 14, 32, 14),
+		"eni-3": makeENI("10.0.2.10", 14, 32, 5),
+	}}, info, start.Add(10*time.Minute)))
+	if assert.Len(t, mockManager.res, 1) {
+		monitorResult := <-mockManager.res
+		assert.Equal(t, "IPAMDCapacityLow", monitorResult.Reason)
+		assert.Equal(t, "23 of 56 pod IP addresses are free (33 assigned of 42 allocated on 3 ENIs, 1 of 4 ENIs can still be attached), and pods are scheduled at 3.0 per minute, which exhausts them in 8m0s", monitorResult.Message)
+	}
+
+	// the rate is measured over a window, so a burst of pods passes.
+	assert.NoError(t, mon.checkIPAMCapacity(&datastore.ENIInfos{ENIs: map[string]datastore.ENI{
+		"eni-1": makeENI("10.0.0.10", 14, 32, 14),
+		"eni-2": makeENI("10.0.1.10", 14, 32, 14),
+		"eni-3": makeENI("10.0.2.10", 14, 32, 5),
+	}}, info, start.Add(time.Hour)))
+	assert.Empty(t, mockManager.res)
+}
+
+func TestHandleIPAMCapacityUnknownInstanceType(t *testing.T) {
+	rtCtx := &config.RuntimeContext{}
+	rtCtx.AddTags(config.EKSAuto)
+	mon := NewNetworkingMonitor(
+		WithRuntimeContext(rtCtx),
+		WithInstanceTypeInfoProvider(&fakeInstanceInfoProvider{err: fmt.Errorf("%w: x1.test", instanceinfo.ErrUnknownInstanceType)}),
+	)
+	mockManager := &mockManager{
+		obs: observer.BaseObserver{},
+		res: make(chan monitor.Condition, 5),
+	}
+	mon.Register(context.TODO(), mockManager)
+	assert.NoError(t, mon.handleIPAMCapacity())
+	assert.Empty(t, mockManager.res)
+}
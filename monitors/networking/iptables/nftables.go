@@ -0,0 +1,111 @@
+package iptables
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// iptablesCompatTables are the tables that iptables-nft creates in the ip and
+// ip6 families. Their rules are already listed by iptables-save.
+var iptablesCompatTables = []string{"filter", "nat", "mangle", "raw", "security"}
+
+// NFTablesRule is a rule of a native nftables table, as listed by
+// `nft -j list ruleset`.
+type NFTablesRule struct {
+	Family  string
+	Table   string
+	Chain   string
+	Handle  int
+	Comment string
+	// expr holds the statements of the rule, each an object keyed by the
+	// statement name, e.g. {"drop": null}.
+	expr []map[string]json.RawMessage
+}
+
+// ParseNFTablesRuleset returns the rules of the JSON ruleset, skipping the
+// rules of tables created by iptables-nft.
+func ParseNFTablesRuleset(data []byte) ([]NFTablesRule, error) {
+	var ruleset struct {
+		NFTables []struct {
+			Rule *struct {
+				Family  string                       `json:"family"`
+				Table   string                       `json:"table"`
+				Chain   string                       `json:"chain"`
+				Handle  int                          `json:"handle"`
+				Comment string                       `json:"comment"`
+				Expr    []map[string]json.RawMessage `json:"expr"`
+			} `json:"rule"`
+		} `json:"nftables"`
+	}
+	if err := json.Unmarshal(data, &ruleset); err != nil {
+		return nil, fmt.Errorf("parsing nftables ruleset: %w", err)
+	}
+	var rules []NFTablesRule
+	for _, object := range ruleset.NFTables {
+		rule := object.Rule
+		if rule == nil {
+			// tables, chains, sets and the like.
+			continue
+		}
+		if (rule.Family == "ip" || rule.Family == "ip6") && slices.Contains(iptablesCompatTables, rule.Table) {
+			continue
+		}
+		rules = append(rules, NFTablesRule{
+			Family:  rule.Family,
+			Table:   rule.Table,
+			Chain:   rule.Chain,
+			Handle:  rule.Handle,
+			Comment: rule.Comment,
+			expr:    rule.Expr,
+		})
+	}
+	return rules, nil
+}
+
+func (r NFTablesRule) IsReject() bool {
+	return slices.ContainsFunc(r.expr, func(statement map[string]json.RawMessage) bool {
+		_, drop := statement["drop"]
+		_, reject := statement["reject"]
+		return drop || reject
+	})
+}
+
+func (r NFTablesRule) IsExpectedRejectRule(allowedChains []string) bool {
+	if r.Table == "kube-proxy" {
+		// kube-proxy (nftables mode) owns this table, and rejects or drops
+		// traffic to services without endpoints, invalid ports of cluster IPs
+		// and sources outside of spec.loadBalancerSourceRanges. The table is
+		// fully managed by kube-proxy and rewritten on every sync.
+		return true
+	} else if r.Table == "calico" || strings.HasPrefix(r.Chain, "cali-") {
+		// Calico managed chains use drop rules as part of normal network policy enforcement
+		return true
+	}
+	for _, entry := range allowedChains {
+		// entries must use "family/table/chain" format (e.g.
+		// "inet/my-table/my-chain"), other entries are for iptables.
+		parts := strings.Split(entry, "/")
+		if len(parts) != 3 || slices.ContainsFunc(parts, func(part string) bool { return strings.TrimSpace(part) == "" }) {
+			continue
+		}
+		if r.Family == parts[0] && r.Table == parts[1] && r.Chain == parts[2] {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *NFTablesRule) String() string {
+	var statements []string
+	for _, statement := range r.expr {
+		encoded, _ := json.Marshal(statement)
+		statements = append(statements, string(encoded))
+	}
+	s := fmt.Sprintf("%s %s %s handle %d: %s", r.Family, r.Table, r.Chain, r.Handle, strings.Join(statements, " "))
+	if r.Comment != "" {
+		s += fmt.Sprintf(" comment %q", r.Comment)
+	}
+	return s
+}
@@ -0,0 +1,48 @@
+package iptables_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/aws/eks-node-monitoring-agent/monitors/networking/iptables"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNFTablesRuleset(t *testing.T) {
+	data, err := os.ReadFile("testdata/nft-ruleset.json")
+	require.NoError(t, err)
+	rules, err := iptables.ParseNFTablesRuleset(data)
+	require.NoError(t, err)
+
+	// the rules of the iptables-nft filter table are skipped.
+	require.Len(t, rules, 3)
+	kubeProxy, accept, drop := rules[0], rules[1], rules[2]
+
+	t.Run("KubeProxy", func(t *testing.T) {
+		assert.True(t, kubeProxy.IsReject())
+		assert.True(t, kubeProxy.IsExpectedRejectRule(nil))
+	})
+
+	t.Run("Accept", func(t *testing.T) {
+		assert.False(t, accept.IsReject())
+	})
+
+	t.Run("CustomChain", func(t *testing.T) {
+		assert.True(t, drop.IsReject())
+		assert.False(t, drop.IsExpectedRejectRule(nil))
+		assert.True(t, drop.IsExpectedRejectRule([]string{"inet/my-firewall/input"}))
+		assert.False(t, drop.IsExpectedRejectRule([]string{"ip/my-firewall/input"}), "should not match when family differs")
+		assert.False(t, drop.IsExpectedRejectRule([]string{"my-firewall/input"}), "iptables entries should not match")
+		assert.False(t, drop.IsExpectedRejectRule([]string{"inet//input"}), "malformed entries should not match")
+	})
+
+	t.Run("String", func(t *testing.T) {
+		assert.Equal(t, `inet my-firewall input handle 4: {"match":{"op":"==","left":{"payload":{"protocol":"tcp","field":"dport"}},"right":22}} {"drop":null} comment "block ssh"`, drop.String())
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := iptables.ParseNFTablesRuleset([]byte("table inet my-firewall {"))
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,63 @@
+package networking
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// allowanceDescs describe the ENA allowance counters exported for each
+// interface, keyed by the name of the NIC statistic.
+var allowanceDescs = map[string]*prometheus.Desc{
+	BandwidthInExceeded: prometheus.NewDesc(
+		"ena_bw_in_allowance_exceeded_total", "Total number of packets queued or dropped because the inbound aggregate bandwidth exceeded the maximum for the instance.", []string{"interface"}, nil),
+	BandwidthOutExceeded: prometheus.NewDesc(
+		"ena_bw_out_allowance_exceeded_total", "Total number of packets queued or dropped because the outbound aggregate bandwidth exceeded the maximum for the instance.", []string{"interface"}, nil),
+	ConntrackExceeded: prometheus.NewDesc(
+		"ena_conntrack_allowance_exceeded_total", "Total number of packets dropped because connection tracking exceeded the maximum for the instance.", []string{"interface"}, nil),
+	LinkLocalExceeded: prometheus.NewDesc(
+		"ena_linklocal_allowance_exceeded_total", "Total number of packets dropped because the PPS of the traffic to local proxy services exceeded the maximum for the network interface.", []string{"interface"}, nil),
+	PPSExceeded: prometheus.NewDesc(
+		"ena_pps_allowance_exceeded_total", "Total number of packets queued or dropped because the bidirectional PPS exceeded the maximum for the instance.", []string{"interface"}, nil),
+}
+
+var allowanceCollector = &ethtoolAllowanceCollector{}
+
+func init() {
+	metrics.Registry.MustRegister(allowanceCollector)
+}
+
+// ethtoolAllowanceCollector exports the most recently read ENA allowance
+// counters of each interface. The counters are exported as constant metrics,
+// since they are maintained by the driver.
+type ethtoolAllowanceCollector struct {
+	mu sync.Mutex
+	// stats are the allowance counters, keyed by interface and then by the
+	// name of the NIC statistic.
+	stats map[string]map[string]uint64
+}
+
+// set replaces the exported counters, so that interfaces which no longer exist
+// stop being exported.
+func (c *ethtoolAllowanceCollector) set(stats map[string]map[string]uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stats = stats
+}
+
+func (c *ethtoolAllowanceCollector) Describe(ch chan<- *prometheus.Desc) {
+	prometheus.DescribeByCollect(c, ch)
+}
+
+func (c *ethtoolAllowanceCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for interfaceName, stats := range c.stats {
+		for statName, desc := range allowanceDescs {
+			if value, ok := stats[statName]; ok {
+				ch <- prometheus.MustNewConstMetric(desc, prometheus.CounterValue, float64(value), interfaceName)
+			}
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package networking
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEthtoolAllowanceCollector(t *testing.T) {
+	allowanceCollector.set(map[string]map[string]uint64{
+		"ens5": {BandwidthInExceeded: 11072, PPSExceeded: 3},
+		"ens6": {BandwidthInExceeded: 0},
+	})
+	t.Cleanup(func() { allowanceCollector.set(nil) })
+
+	assert.NoError(t, testutil.CollectAndCompare(allowanceCollector, strings.NewReader(`
+# HELP ena_bw_in_allowance_exceeded_total Total number of packets queued or dropped because the inbound aggregate bandwidth exceeded the maximum for the instance.
+# TYPE ena_bw_in_allowance_exceeded_total counter
+ena_bw_in_allowance_exceeded_total{interface="ens5"} 11072
+ena_bw_in_allowance_exceeded_total{interface="ens6"} 0
+# HELP ena_pps_allowance_exceeded_total Total number of packets queued or dropped because the bidirectional PPS exceeded the maximum for the instance.
+# TYPE ena_pps_allowance_exceeded_total counter
+ena_pps_allowance_exceeded_total{interface="ens5"} 3
+`)))
+
+	// interfaces which no longer exist stop being exported.
+	allowanceCollector.set(map[string]map[string]uint64{"ens5": {BandwidthInExceeded: 11080}})
+	assert.Equal(t, 1, testutil.CollectAndCount(allowanceCollector))
+}
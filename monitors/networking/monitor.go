@@ -13,11 +13,14 @@ import (
 	"slices"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/aws/amazon-vpc-cni-k8s/pkg/ipamd/datastore"
 	"github.com/coreos/go-systemd/v22/dbus"
 	"github.com/go-logr/logr"
 	"github.com/shirou/gopsutil/v4/process"
+	"golang.org/x/sys/unix"
 	"golang.org/x/time/rate"
 	"k8s.io/client-go/tools/cache"
 	cri "k8s.io/cri-api/pkg/apis"
@@ -26,12 +29,15 @@ import (
 
 	"github.com/aws/eks-node-monitoring-agent/api/monitor"
 	"github.com/aws/eks-node-monitoring-agent/api/monitor/resource"
+	"github.com/aws/eks-node-monitoring-agent/internal/pkg/instanceinfo"
+	"github.com/aws/eks-node-monitoring-agent/monitors/networking/dns"
 	"github.com/aws/eks-node-monitoring-agent/monitors/networking/efa"
 	toolexec "github.com/aws/eks-node-monitoring-agent/monitors/networking/exec"
 	"github.com/aws/eks-node-monitoring-agent/monitors/networking/ipamd"
 	"github.com/aws/eks-node-monitoring-agent/monitors/networking/iptables"
 	"github.com/aws/eks-node-monitoring-agent/monitors/networking/networkutils"
 	"github.com/aws/eks-node-monitoring-agent/monitors/networking/npa"
+	"github.com/aws/eks-node-monitoring-agent/monitors/networking/probe"
 	"github.com/aws/eks-node-monitoring-agent/pkg/config"
 	"github.com/aws/eks-node-monitoring-agent/pkg/osext"
 	"github.com/aws/eks-node-monitoring-agent/pkg/reasons"
@@ -74,8 +80,32 @@ type NetworkingMonitor struct {
 	allowedIPTablesChains []string
 	// excludedInterfaceNameRegexps holds compiled regexps. Interfaces whose
 	// name matches any of these are skipped during InterfaceNotUp /
-	// InterfaceNotRunning checks.
+	// InterfaceNotRunning / InterfacePacketDrops checks.
 	excludedInterfaceNameRegexps []*regexp.Regexp
+	// dnsProbeNames are the names resolved against each nameserver.
+	dnsProbeNames []string
+	// connectivityProbes are the synthetic probes of the endpoints that the
+	// node depends on.
+	connectivityProbes []config.ConnectivityProbe
+	// instanceInfoProvider resolves the ENI limits of the instance type.
+	instanceInfoProvider instanceinfo.InstanceTypeInfoProvider
+	// ipamSamples are the recent numbers of assigned pod addresses, to measure
+	// the rate that pods are scheduled onto the node.
+	ipamSamples []ipamSample
+	// packetDrops is the previous sample of the drop counters of the
+	// interfaces and CPUs, to compute their rates.
+	packetDrops *packetDropSample
+	// socketBreaches is the number of consecutive checks that the socket
+	// limit of each reason was breached.
+	socketBreaches map[reasons.ReasonMeta]int
+	// netlinkMu guards the state of the links and the default routes, which
+	// netlink events and the default route checks update.
+	netlinkMu sync.Mutex
+	// links is the state of each link, keyed by index.
+	links map[int]*linkState
+	// deletedDefaultRoutes are the deleted default routes of the main table,
+	// keyed by family, until they are added back or reported.
+	deletedDefaultRoutes map[string]deletedRoute
 }
 
 func (m *NetworkingMonitor) Name() string {
@@ -116,6 +146,14 @@ func WithRuntimeService(svc cri.RuntimeService) Option {
 	}
 }
 
+// WithInstanceTypeInfoProvider injects the provider of the instance type
+// limits. Intended for tests.
+func WithInstanceTypeInfoProvider(provider instanceinfo.InstanceTypeInfoProvider) Option {
+	return func(m *NetworkingMonitor) {
+		m.instanceInfoProvider = provider
+	}
+}
+
 func (m *NetworkingMonitor) SetAllowedIPTablesChains(chains []string) {
 	m.allowedIPTablesChains = chains
 }
@@ -136,6 +174,18 @@ func (m *NetworkingMonitor) SetExcludedInterfaceNameRegexps(exprs []string) erro
 	return nil
 }
 
+// SetDNSProbeNames sets the names resolved against each nameserver of the
+// node and the cluster DNS service.
+func (m *NetworkingMonitor) SetDNSProbeNames(names []string) {
+	m.dnsProbeNames = names
+}
+
+// SetConnectivityProbes sets the synthetic probes of the endpoints that the
+// node depends on.
+func (m *NetworkingMonitor) SetConnectivityProbes(probes []config.ConnectivityProbe) {
+	m.connectivityProbes = probes
+}
+
 // isInterfaceExcluded reports whether the given interface name matches any of
 // the configured exclusion regexps.
 func (m *NetworkingMonitor) isInterfaceExcluded(name string) bool {
@@ -149,8 +199,11 @@ func (m *NetworkingMonitor) isInterfaceExcluded(name string) bool {
 
 func NewNetworkingMonitor(options ...Option) *NetworkingMonitor {
 	m := &NetworkingMonitor{
-		exec:           osext.NewExec(config.HostRoot()),
-		runtimeContext: config.GetRuntimeContext(),
+		exec:                 osext.NewExec(config.HostRoot()),
+		runtimeContext:       config.GetRuntimeContext(),
+		dnsProbeNames:        config.DefaultDNSProbeNames,
+		connectivityProbes:   config.DefaultConnectivityProbes,
+		instanceInfoProvider: instanceinfo.NewInstanceTypeInfoProvider(),
 	}
 
 	for _, option := range options {
@@ -183,6 +236,10 @@ func (m *NetworkingMonitor) Register(ctx context.Context, mgr monitor.Manager) e
 		}
 	}()
 
+	m.socketBreaches = make(map[reasons.ReasonMeta]int)
+	m.links = make(map[int]*linkState)
+	m.deletedDefaultRoutes = make(map[string]deletedRoute)
+
 	subscriptionArgs := []util.SubscriptionArgs[string]{
 		{
 			Handler: m.handleIPAMDLogs,
@@ -190,6 +247,18 @@ func (m *NetworkingMonitor) Register(ctx context.Context, mgr monitor.Manager) e
 				return mgr.Subscribe(resource.ResourceTypeFile, []resource.Part{resource.Part(config.IPAMDLogPath)})
 			},
 		},
+		{
+			Handler: m.handleNeighborDmesg,
+			SubscriptionFn: func() (<-chan string, error) {
+				return mgr.Subscribe(resource.ResourceTypeDmesg, []resource.Part{})
+			},
+		},
+		{
+			Handler: m.handleNetlinkEvent,
+			SubscriptionFn: func() (<-chan string, error) {
+				return mgr.Subscribe(resource.ResourceTypeNetlink, []resource.Part{})
+			},
+		},
 	}
 
 	// Walk the kubernetes pod logs directory to find the log stream for
@@ -233,12 +302,20 @@ func (m *NetworkingMonitor) Register(ctx context.Context, mgr monitor.Manager) e
 		go handler.Start(ctx)
 	}
 
+	// the ethtool monitor keeps the counters of each interface across checks,
+	// to detect spikes in them.
+	ethtoolMon := makeEthtoolMonitor(mgr)
 	for _, handler := range []interface{ Start(context.Context) error }{
-		util.NewChannelHandler(func(time.Time) error { return makeEthtoolMonitor(mgr).handleEthtool() }, util.TimeTickWithJitterContext(ctx, 5*time.Minute)),
+		util.NewChannelHandler(func(time.Time) error { return ethtoolMon.handleEthtool() }, util.TimeTickWithJitterContext(ctx, 5*time.Minute)),
 		util.NewChannelHandler(func(time.Time) error { return m.handleIPRulesAndRoutes() }, util.TimeTickWithJitterContext(ctx, 5*time.Minute)),
 		util.NewChannelHandler(func(time.Time) error { return m.handleIPTables() }, util.TimeTickWithJitterContext(ctx, 5*time.Minute)),
 		util.NewChannelHandler(func(time.Time) error { return m.handleInterfaces() }, util.TimeTickWithJitterContext(ctx, interfaceMonitorPeriod)),
+		util.NewChannelHandler(func(time.Time) error { return m.handlePacketDrops() }, util.TimeTickWithJitterContext(ctx, interfaceMonitorPeriod)),
+		util.NewChannelHandler(func(time.Time) error { return m.checkDefaultRoutes(time.Now()) }, util.TimeTickWithJitterContext(ctx, defaultRouteGracePeriod)),
 		util.NewChannelHandler(func(time.Time) error { return m.handleNetworkSysctl() }, util.TimeTickWithJitterContext(ctx, 5*time.Minute)),
+		util.NewChannelHandler(func(time.Time) error { return m.handleNeighborTables() }, util.TimeTickWithJitterContext(ctx, 5*time.Minute)),
+		util.NewChannelHandler(func(time.Time) error { return m.handleSockets() }, util.TimeTickWithJitterContext(ctx, 5*time.Minute)),
+		util.NewChannelHandler(func(time.Time) error { return m.handleIPAMCapacity() }, util.TimeTickWithJitterContext(ctx, 5*time.Minute)),
 		// handleIPAMD interval also currently dictates IPAMD startup duration tolerance on non-auto. if changing
 		// one value, consider separating out the two
 		util.NewChannelHandler(func(time.Time) error { return m.handleIPAMD() }, util.TimeTickWithJitterContext(ctx, 5*time.Minute)),
@@ -272,6 +349,47 @@ func (m *NetworkingMonitor) Register(ctx context.Context, mgr monitor.Manager) e
 		}
 	}()
 
+	// DNS resolution from the node and through the cluster DNS service
+	dnsSystem := dns.NewDNSSystem(m.dnsProbeNames)
+	go func() {
+		for range util.TimeTickWithJitterContext(ctx, time.Minute) {
+			conditions, err := dnsSystem.Resolution(ctx)
+			if err != nil {
+				m.log.Error(err, "failed to check DNS resolution")
+				continue
+			}
+			for _, condition := range conditions {
+				if err := m.manager.Notify(ctx, condition); err != nil {
+					m.log.Error(err, "failed to notify DNS condition")
+				}
+			}
+		}
+	}()
+
+	// synthetic probes of the endpoints that the node depends on, each on its
+	// own interval.
+	for _, connectivityProbe := range m.connectivityProbes {
+		if probe.UsesIMDS(connectivityProbe.Type) && slices.Contains(m.runtimeContext.Tags(), config.Hybrid) {
+			m.log.Info("Skipping connectivity probe - hybrid nodes do not have IMDS", "probe", connectivityProbe.Name)
+			continue
+		}
+		prober := probe.NewProber(connectivityProbe)
+		go func() {
+			for range util.TimeTickWithJitterContext(ctx, prober.Interval()) {
+				condition, err := prober.Probe(ctx)
+				if err != nil {
+					m.log.Error(err, "failed to run connectivity probe", "probe", prober.Name())
+					continue
+				}
+				if condition != nil {
+					if err := m.manager.Notify(ctx, *condition); err != nil {
+						m.log.Error(err, "failed to notify connectivity probe condition")
+					}
+				}
+			}
+		}()
+	}
+
 	return nil
 }
 
@@ -539,7 +657,7 @@ type ethtoolMonitor struct {
 }
 
 type statTracker struct {
-	recorded    int
+	recorded    uint64
 	rateLimiter *rate.Limiter
 }
 
@@ -578,40 +696,50 @@ func (m *ethtoolMonitor) handleEthtool() (merr error) {
 	if err != nil {
 		return err
 	}
+	allowances := make(map[string]map[string]uint64)
 	for _, netInterface := range netInterfaces {
-		ethtoolCmd := []string{"ethtool", "-S", netInterface.Name}
-		ethtoolOut, err := osext.NewExec(config.HostRoot()).Command(ethtoolCmd[0], ethtoolCmd[1:]...).CombinedOutput()
+		stats, err := osext.EthtoolStats(netInterface.Name)
 		if err != nil {
-			// its ok for this to fail with exit code 94, which happens on an
-			// interface with no stats like the loopback interface.
-			if exitError, ok := err.(*exec.ExitError); !ok || exitError.ExitCode() != 94 {
-				merr = errors.Join(merr, fmt.Errorf("failed command %q: %w", ethtoolCmd, err))
-				continue
+			// interfaces like the veths of pods come and go between listing
+			// and reading them.
+			if !errors.Is(err, unix.ENODEV) {
+				merr = errors.Join(merr, err)
 			}
-		}
-		stats, err := parseEthtool(ethtoolOut)
-		if err != nil {
-			merr = errors.Join(merr, err)
 			continue
 		}
+		for statName := range allowanceDescs {
+			if value, ok := stats[statName]; ok {
+				if allowances[netInterface.Name] == nil {
+					allowances[netInterface.Name] = make(map[string]uint64)
+				}
+				allowances[netInterface.Name][statName] = value
+			}
+		}
 		merr = errors.Join(merr, m.checkEthtool(netInterface.Name, stats))
 	}
+	allowanceCollector.set(allowances)
 	return merr
 }
 
 // checkEthtool checks whether the allowance exceeded metrics from ethtool are
 // breached at an unhealthy rate.
-func (m *ethtoolMonitor) checkEthtool(interfaceName string, stats map[string]int) (merr error) {
+func (m *ethtoolMonitor) checkEthtool(interfaceName string, stats map[string]uint64) (merr error) {
 	for statKey, rateLimiterConstructor := range statLimiterConstructors {
 		if statValue, ok := stats[statKey]; ok {
 			cacheKey := makeCompoundStatKey(interfaceName, statKey)
 			if statCache, ok := m.statExceededCache[cacheKey]; ok {
+				if statValue < statCache.recorded {
+					// the counters are reset along with the device, e.g.
+					// when the ENA driver recovers from an error.
+					statCache.recorded = statValue
+					continue
+				}
 				// we want to detect when these metrics could be responsible for issues on the
 				// node. reporting when they increase or are non-zero gets pretty noisy and
 				// doesn't directly indicate any issues. instead, the approach here is to only
 				// emit the event when there is a noticeable spike in the exceeded stats.
-				if exceededCntDelta := statValue - statCache.recorded; !statCache.rateLimiter.AllowN(time.Now(), exceededCntDelta) {
-					merr = errors.Join(m.manager.Notify(context.TODO(),
+				if exceededCntDelta := statValue - statCache.recorded; !statCache.rateLimiter.AllowN(time.Now(), int(exceededCntDelta)) {
+					merr = errors.Join(merr, m.manager.Notify(context.TODO(),
 						statReasons[statKey].
 							Builder().
 							Message(fmt.Sprintf("%s increased on interface %q from %d to %d", statKey, interfaceName, statCache.recorded, statValue)).
@@ -636,48 +764,92 @@ func (m *ethtoolMonitor) checkEthtool(interfaceName string, stats map[string]int
 	return merr
 }
 
-func parseEthtool(ethtoolOut []byte) (map[string]int, error) {
-	// Input looks like any of the following:
-	// ---
-	// no stats available
-	// ---
-	// NIC statistics:
-	//      total_resets: 0
-	//      reset_fail: 0
-	stats := make(map[string]int)
-	ethtoolLines := strings.Split(string(ethtoolOut), "\n")
-	for _, line := range ethtoolLines[1:] {
-		if fields := strings.Split(line, ":"); len(fields) == 2 {
-			if val, err := strconv.Atoi(strings.TrimSpace(fields[1])); err == nil {
-				stats[strings.TrimSpace(fields[0])] = val
-			}
-		}
-	}
-	return stats, nil
-}
-
 // ~~~~ sysctl ~~~~
 
+// sysctlValue is the current and expected value of a sysctl parameter that
+// pod networking depends on.
+type sysctlValue struct {
+	name     string
+	value    int
+	expected int
+}
+
 func (m *NetworkingMonitor) handleNetworkSysctl() error {
-	ipForward, err := osext.ParseSysctl("net.ipv4.ip_forward", func(b []byte) (int, error) { return strconv.Atoi(string(b)) })
+	sysctls := []sysctlValue{{name: "net.ipv4.ip_forward", expected: 1}}
+	ipv6, err := ipv6PodNetworking()
 	if err != nil {
 		return err
 	}
-	return m.checkNetworkSysctl(*ipForward)
+	if ipv6 {
+		// the VPC CNI forwards pod traffic over IPv6, and has the primary ENI
+		// keep accepting router advertisements, which carry its default route,
+		// even though forwarding is enabled.
+		sysctls = append(sysctls, sysctlValue{name: "net.ipv6.conf.all.forwarding", expected: 1})
+		routes, err := toolexec.GetRoutes(toolexec.FamilyIPv4)
+		if err != nil {
+			return err
+		}
+		if dev, ok := primaryInterface(routes); ok {
+			sysctls = append(sysctls, sysctlValue{name: fmt.Sprintf("net.ipv6.conf.%s.accept_ra", dev), expected: 2})
+		}
+	}
+
+	for i := range sysctls {
+		value, err := osext.ParseSysctl(sysctls[i].name, func(b []byte) (int, error) { return strconv.Atoi(string(b)) })
+		if err != nil {
+			return err
+		}
+		sysctls[i].value = *value
+	}
+	return m.checkNetworkSysctl(sysctls)
 }
 
 // checkNetworkSysctl validates whether the current sysctl parameters are
 // configured to work correctly with kubernetes.
-func (m *NetworkingMonitor) checkNetworkSysctl(ipForward int) (merr error) {
-	if ipForward != 1 {
-		merr = errors.Join(merr, m.manager.Notify(context.TODO(),
-			reasons.NetworkSysctl.
-				Builder().
-				Message(fmt.Sprintf("A network related sysctl parameter may be misconfigured")).
-				Build(),
-		))
+func (m *NetworkingMonitor) checkNetworkSysctl(sysctls []sysctlValue) (merr error) {
+	for _, sysctl := range sysctls {
+		if sysctl.value != sysctl.expected {
+			merr = errors.Join(merr, m.manager.Notify(context.TODO(),
+				reasons.NetworkSysctl.
+					Builder().
+					Message(fmt.Sprintf("A network related sysctl parameter may be misconfigured: %s is %d, expected %d", sysctl.name, sysctl.value, sysctl.expected)).
+					Build(),
+			))
+		}
 	}
-	return nil
+	return merr
+}
+
+// ipv6PodNetworking returns whether the VPC CNI assigns IPv6 addresses to
+// pods, as recorded by the allocations in its checkpoint.
+func ipv6PodNetworking() (bool, error) {
+	checkpointData, err := ipamd.GetCheckpoint()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	for _, entry := range checkpointData.Allocations {
+		if entry.IPv6 != "" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// primaryInterface returns the interface of the default route in the main
+// table, which is the primary ENI. Default routes through secondary ENIs are
+// in their own tables.
+func primaryInterface(routes []string) (string, bool) {
+	for _, route := range routes {
+		if matches := defaultRouteRegex.FindStringSubmatch(route); len(matches) >= 2 && matches[1] == "" {
+			if dev := routeDevRegex.FindStringSubmatch(route); len(dev) >= 2 {
+				return dev[1], true
+			}
+		}
+	}
+	return "", false
 }
 
 // ~~~~ ip rules/routes ~~~~
@@ -691,6 +863,13 @@ type ipMetadata struct {
 	primary bool
 }
 
+var (
+	// Parse for `default via <ip> dev eth1 table <table id>`, where the table is
+	// omitted for the main table.
+	defaultRouteRegex = regexp.MustCompile(`^default\s.*?(?:\stable (\w+))?\s*$`)
+	routeDevRegex     = regexp.MustCompile(`\sdev (\S+)`)
+)
+
 func (m *NetworkingMonitor) handleIPRulesAndRoutes() (merr error) {
 	if slices.Contains(m.runtimeContext.Tags(), config.Hybrid) {
 		// Hybrid nodes do not have ipamd
@@ -710,29 +889,25 @@ func (m *NetworkingMonitor) handleIPRulesAndRoutes() (merr error) {
 	if err != nil {
 		return err
 	}
-	rules, err := toolexec.GetRules()
-	if err != nil {
-		return err
-	}
-	routes, err := toolexec.GetRoutes()
-	if err != nil {
-		return err
-	}
 
 	// holds metadata about the ip address
 	ipMeta := make(map[string]*ipMetadata)
 
 	for _, eni := range enis.ENIs {
-		for _, cidr := range eni.AvailableIPv4Cidrs {
-			for _, addr := range cidr.IPAddresses {
-				if addr.IPAMKey.ContainerID == "" || addr.IPAMKey.IfName == "" || addr.IPAMKey.NetworkName == "" {
-					// if the IPAM data is not populated we dont need to track
-					// this address or perform validations.
-					continue
-				}
-				if _, ok := ipMeta[addr.Address]; !ok {
-					ipMeta[addr.Address] = &ipMetadata{
-						primary: eni.IsPrimary,
+		// IPv6 clusters assign pods addresses from the IPv6 prefixes of the
+		// primary ENI instead.
+		for _, cidrs := range []map[string]*datastore.CidrInfo{eni.AvailableIPv4Cidrs, eni.IPv6Cidrs} {
+			for _, cidr := range cidrs {
+				for _, addr := range cidr.IPAddresses {
+					if addr.IPAMKey.ContainerID == "" || addr.IPAMKey.IfName == "" || addr.IPAMKey.NetworkName == "" {
+						// if the IPAM data is not populated we dont need to track
+						// this address or perform validations.
+						continue
+					}
+					if _, ok := ipMeta[addr.Address]; !ok {
+						ipMeta[addr.Address] = &ipMetadata{
+							primary: eni.IsPrimary,
+						}
 					}
 				}
 			}
@@ -745,14 +920,23 @@ func (m *NetworkingMonitor) handleIPRulesAndRoutes() (merr error) {
 	}
 
 	// derive the ip mode using the first ip from the list.
-	ipMode := IPv4
+	ipMode, family := IPv4, toolexec.FamilyIPv4
 	for ip := range ipMeta {
 		if net.ParseIP(ip).To4() == nil {
-			ipMode = IPv6
+			ipMode, family = IPv6, toolexec.FamilyIPv6
 		}
 		break
 	}
 
+	rules, err := toolexec.GetRules(family)
+	if err != nil {
+		return err
+	}
+	routes, err := toolexec.GetRoutes(family)
+	if err != nil {
+		return err
+	}
+
 	return errors.Join(merr,
 		m.checkIPRulesAndRoutes(ipMode, ipMeta, rules, routes),
 	)
@@ -820,40 +1004,46 @@ func (m *NetworkingMonitor) checkIPRulesAndRoutes(ipMode int, ipMeta map[string]
 		}
 	}
 
+	if ipMode == IPv6 {
+		// pods in IPv6 clusters are all on the primary ENI, and reach the VPC
+		// through the default route (::/0) of the main table, which is learned
+		// from router advertisements.
+		tables["main"] = &tableData{
+			defaultRouteExistsErr: fmt.Errorf("Expected entry like `default via <gateway-ip> dev <dev> proto ra metric 1024 pref medium`"),
+		}
+	}
+
 	// ~ parse ip routes ~
 
 	// Parse for either `<pod ip ipv4> dev enia2d278c49cc scope link` or `<pod ip ipv6> dev eni6f27e6b0279 metric 1024 pref medium`
 	podIpIpv4 := regexp.MustCompile(`(.+) dev .+ scope link`)
 	podIpIpv6 := regexp.MustCompile(`(.+) dev .+ metric 1024 pref medium`)
-	// Parse for `default via <ip> dev eth1 table <table id>`
-	defaultRouteRegex := regexp.MustCompile(`default via .+ dev .+ table (\w+)`)
 
 	for _, route := range routes {
+		var podIpRegex *regexp.Regexp
 		switch ipMode {
 		case IPv4:
-			if matches := podIpIpv4.FindStringSubmatch(route); len(matches) >= 2 {
-				ip := matches[1]
-				if meta, ok := ips[ip]; ok {
-					meta.ipRouteExistsErr = nil
-				}
-			}
-			// default routes only apply in IPv4
-			if matches := defaultRouteRegex.FindStringSubmatch(route); len(matches) >= 2 {
-				tableName := matches[1]
-				if table, ok := tables[tableName]; ok {
-					table.defaultRouteExistsErr = nil
-				}
-			}
+			podIpRegex = podIpIpv4
 		case IPv6:
-			if matches := podIpIpv6.FindStringSubmatch(route); len(matches) >= 2 {
-				ip := matches[1]
-				if meta, ok := ips[ip]; ok {
-					meta.ipRouteExistsErr = nil
-				}
-			}
+			podIpRegex = podIpIpv6
 		default:
 			return fmt.Errorf("invalid ip mode")
 		}
+		if matches := podIpRegex.FindStringSubmatch(route); len(matches) >= 2 {
+			ip := matches[1]
+			if meta, ok := ips[ip]; ok {
+				meta.ipRouteExistsErr = nil
+			}
+		}
+		if matches := defaultRouteRegex.FindStringSubmatch(route); len(matches) >= 2 {
+			tableName := matches[1]
+			if tableName == "" {
+				tableName = "main"
+			}
+			if table, ok := tables[tableName]; ok {
+				table.defaultRouteExistsErr = nil
+			}
+		}
 	}
 
 	// ~ evaluate the results of the ips ~
@@ -900,6 +1090,10 @@ func (m *NetworkingMonitor) checkIPRulesAndRoutes(ipMode int, ipMeta map[string]
 
 // ~~~~ ip tables ~~~~
 
+// nftTimeout bounds listing the nftables ruleset, which can hang on a large or
+// locked ruleset.
+const nftTimeout = 30 * time.Second
+
 func (m *NetworkingMonitor) handleIPTables() (merr error) {
 	// parses output from iptables-save in order to assemble a list of the rules
 	// which are allowing/denying packets to the instance.
@@ -928,8 +1122,24 @@ func (m *NetworkingMonitor) handleIPTables() (merr error) {
 			}
 		}
 	}
+
+	// rules of native nftables tables, such as those of kube-proxy in nftables
+	// mode, are not listed by iptables-save.
+	var nftRules []iptables.NFTablesRule
+	out, err := osext.NewExec(config.HostRoot()).Output(context.TODO(), nftTimeout, "nft", "-j", "list", "ruleset")
+	switch {
+	case errors.Is(err, exec.ErrNotFound):
+		// nodes without nft have no native nftables tables to check.
+	case err != nil:
+		merr = errors.Join(merr, fmt.Errorf("failed command %q: %w", "nft", err))
+	default:
+		if nftRules, err = iptables.ParseNFTablesRuleset(out); err != nil {
+			merr = errors.Join(merr, err)
+		}
+	}
 	return errors.Join(merr,
 		m.checkIPTables(rules),
+		m.checkNFTables(nftRules),
 	)
 }
 
@@ -990,6 +1200,24 @@ func (m *NetworkingMonitor) checkIPTables(rules []iptables.IPTablesRule) (merr e
 	return merr
 }
 
+func (m *NetworkingMonitor) checkNFTables(rules []iptables.NFTablesRule) (merr error) {
+	if slices.Contains(m.runtimeContext.Tags(), config.Hybrid) {
+		// customer manages hybrid node, thus can have customized reject rules
+		return nil
+	}
+	for _, rule := range rules {
+		if rule.IsReject() && !rule.IsExpectedRejectRule(m.allowedIPTablesChains) {
+			merr = errors.Join(merr, m.manager.Notify(context.TODO(),
+				reasons.UnexpectedRejectRule.
+					Builder().
+					Message(fmt.Sprintf("Found an unexpected nftables reject rule: %q", rule.String())).
+					Build(),
+			))
+		}
+	}
+	return merr
+}
+
 // ~~~~ MAC Address Policy ~~~~
 
 func (m *NetworkingMonitor) handleMACAddressPolicy() error {
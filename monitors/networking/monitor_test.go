@@ -277,6 +277,32 @@ func TestNetworkingPeriodic(t *testing.T) {
 		})
 	}
 
+	t.Run("UnexpectedRejectRule-NFTables", func(t *testing.T) {
+		data, err := os.ReadFile("iptables/testdata/nft-ruleset.json")
+		require.NoError(t, err)
+		rules, err := iptables.ParseNFTablesRuleset(data)
+		require.NoError(t, err)
+
+		mon := NewNetworkingMonitor()
+		mockManager := &mockManager{
+			obs: observer.BaseObserver{},
+			res: make(chan monitor.Condition, 5),
+		}
+		mon.Register(context.TODO(), mockManager)
+		if !assert.NoError(t, mon.checkNFTables(rules)) {
+			return
+		}
+		if assert.Len(t, mockManager.res, 1) {
+			monitorResult := <-mockManager.res
+			assert.Equal(t, "UnexpectedRejectRule", monitorResult.Reason)
+			assert.Contains(t, monitorResult.Message, "Found an unexpected nftables reject rule: \"inet my-firewall input handle 4:")
+		}
+
+		mon.SetAllowedIPTablesChains([]string{"inet/my-firewall/input"})
+		assert.NoError(t, mon.checkNFTables(rules))
+		assert.Empty(t, mockManager.res)
+	})
+
 	t.Run("IPAMDNotRunning", func(t *testing.T) {
 		ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
 		defer cancel()
@@ -316,13 +342,13 @@ func TestNetworkingPeriodic(t *testing.T) {
 		}
 		ethtoolMonitor := makeEthtoolMonitor(mockManager)
 		mon.Register(ctx, mockManager)
-		ethtoolBytes, err := os.ReadFile("testdata/ethtool-ens5.txt")
-		if !assert.NoError(t, err) {
-			return
-		}
-		stats, err := parseEthtool(ethtoolBytes)
-		if !assert.NoError(t, err) {
-			return
+		stats := map[string]uint64{
+			BandwidthInExceeded:             11072,
+			BandwidthOutExceeded:            0,
+			PPSExceeded:                     0,
+			ConntrackExceeded:               0,
+			LinkLocalExceeded:               0,
+			"conntrack_allowance_available": 51286,
 		}
 		ethtoolMonitor.checkEthtool("ens5", stats)
 		stats[BandwidthInExceeded] = stats[BandwidthInExceeded] + 5000
@@ -336,6 +362,13 @@ func TestNetworkingPeriodic(t *testing.T) {
 			assert.Equal(t, "BandwidthInExceeded", monitorResult.Reason)
 			assert.Equal(t, monitor.SeverityWarning, monitorResult.Severity)
 		}
+
+		// counters that go backwards were reset along with the device.
+		stats[BandwidthInExceeded] = 0
+		assert.NoError(t, ethtoolMonitor.checkEthtool("ens5", stats))
+		stats[BandwidthInExceeded] = 100
+		assert.NoError(t, ethtoolMonitor.checkEthtool("ens5", stats))
+		assert.Empty(t, mockManager.res)
 	})
 
 	t.Run("NetworkSysctl", func(t *testing.T) {
@@ -348,7 +381,7 @@ func TestNetworkingPeriodic(t *testing.T) {
 			res: make(chan monitor.Condition, 5),
 		}
 		mon.Register(ctx, mockManager)
-		if !assert.NoError(t, mon.checkNetworkSysctl(0)) {
+		if !assert.NoError(t, mon.checkNetworkSysctl([]sysctlValue{{name: "net.ipv4.ip_forward", value: 0, expected: 1}})) {
 			return
 		}
 		select {
@@ -360,6 +393,27 @@ func TestNetworkingPeriodic(t *testing.T) {
 		}
 	})
 
+	t.Run("NetworkSysctl-IPv6", func(t *testing.T) {
+		mon := NewNetworkingMonitor()
+		mockManager := &mockManager{
+			obs: observer.BaseObserver{},
+			res: make(chan monitor.Condition, 5),
+		}
+		mon.Register(context.TODO(), mockManager)
+		if !assert.NoError(t, mon.checkNetworkSysctl([]sysctlValue{
+			{name: "net.ipv4.ip_forward", value: 1, expected: 1},
+			{name: "net.ipv6.conf.all.forwarding", value: 1, expected: 1},
+			{name: "net.ipv6.conf.ens5.accept_ra", value: 1, expected: 2},
+		})) {
+			return
+		}
+		if assert.Len(t, mockManager.res, 1) {
+			monitorResult := <-mockManager.res
+			assert.Equal(t, "NetworkSysctl", monitorResult.Reason)
+			assert.Equal(t, "A network related sysctl parameter may be misconfigured: net.ipv6.conf.ens5.accept_ra is 1, expected 2", monitorResult.Message)
+		}
+	})
+
 	t.Run("BadIPMode", func(t *testing.T) {
 		mon := NewNetworkingMonitor()
 		assert.Error(t, mon.checkIPRulesAndRoutes(
@@ -603,6 +657,72 @@ func TestNetworkingPeriodic(t *testing.T) {
 			assert.Equal(t, monitor.SeverityWarning, monitorResult.Severity)
 		}
 	})
+
+	ipv6Meta := map[string]*ipMetadata{
+		"2600:1f14:3d1:2a01:9a6e::1": {primary: true},
+		"2600:1f14:3d1:2a01:9a6e::2": {primary: true},
+	}
+	ipv6Rules := readLines(t, "testdata/ip-6-rule.txt")
+	ipv6Routes := readLines(t, "testdata/ip-6-route.txt")
+	without := func(lines []string, prefix string) []string {
+		return slices.DeleteFunc(slices.Clone(lines), func(line string) bool { return strings.HasPrefix(line, prefix) })
+	}
+
+	for _, test := range []struct {
+		name    string
+		rules   []string
+		routes  []string
+		reason  string
+		message string
+	}{
+		{
+			name:   "IPRulesAndRoutes-IPv6",
+			rules:  ipv6Rules,
+			routes: ipv6Routes,
+		},
+		{
+			name:    "MissingDefaultRoutes-IPv6",
+			rules:   ipv6Rules,
+			routes:  without(ipv6Routes, "default "),
+			reason:  "MissingDefaultRoutes",
+			message: "Missing default route rules for table main: Expected entry like `default via <gateway-ip> dev <dev> proto ra metric 1024 pref medium`",
+		},
+		{
+			name:    "MissingPodRoute-IPv6",
+			rules:   ipv6Rules,
+			routes:  without(ipv6Routes, "2600:1f14:3d1:2a01:9a6e::2 "),
+			reason:  "MissingIPRoutes",
+			message: "Pod IP 2600:1f14:3d1:2a01:9a6e::2 is missing secondary routes: Expected entry like `2600:1f14:3d1:2a01:9a6e::2 dev <dev> metric 1024 pref medium`",
+		},
+		{
+			name:    "MissingPodRule-IPv6",
+			rules:   without(ipv6Rules, "512:\tfrom all to 2600:1f14:3d1:2a01:9a6e::1 "),
+			routes:  ipv6Routes,
+			reason:  "MissingIPRules",
+			message: "Pod IP 2600:1f14:3d1:2a01:9a6e::1 is missing secondary rules: Expected entry like `from all to 2600:1f14:3d1:2a01:9a6e::1 lookup main`",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			mon := NewNetworkingMonitor()
+			mockManager := &mockManager{
+				obs: observer.BaseObserver{},
+				res: make(chan monitor.Condition, 5),
+			}
+			mon.Register(context.TODO(), mockManager)
+			if !assert.NoError(t, mon.checkIPRulesAndRoutes(IPv6, ipv6Meta, test.rules, test.routes)) {
+				return
+			}
+			if test.reason == "" {
+				assert.Empty(t, mockManager.res)
+				return
+			}
+			if assert.Len(t, mockManager.res, 1) {
+				monitorResult := <-mockManager.res
+				assert.Equal(t, test.reason, monitorResult.Reason)
+				assert.Equal(t, test.message, monitorResult.Message)
+			}
+		})
+	}
 }
 
 func TestHandleMACAddressPolicy(t *testing.T) {
@@ -819,29 +939,27 @@ func (c *expiringCache) GetByKey(key string) (interface{}, bool, error) {
 }
 
 func TestUtils(t *testing.T) {
-	t.Run("EthtoolParseNoStats", func(t *testing.T) {
-		ethtoolBytes, err := os.ReadFile("testdata/ethtool-lo.txt")
-		if !assert.NoError(t, err) {
-			return
-		}
-		stats, err := parseEthtool(ethtoolBytes)
-		if !assert.NoError(t, err) {
-			return
-		}
-		assert.Empty(t, stats)
+	t.Run("PrimaryInterface", func(t *testing.T) {
+		dev, ok := primaryInterface(readLines(t, "testdata/ip-4-route.txt"))
+		assert.True(t, ok)
+		assert.Equal(t, "ens5", dev)
+
+		_, ok = primaryInterface([]string{"default via 192.168.32.1 dev ens6 table 2"})
+		assert.False(t, ok)
 	})
 
-	t.Run("EthtoolParse", func(t *testing.T) {
-		ethtoolBytes, err := os.ReadFile("testdata/ethtool-ens5.txt")
-		if !assert.NoError(t, err) {
-			return
-		}
-		stats, err := parseEthtool(ethtoolBytes)
-		if !assert.NoError(t, err) {
-			return
-		}
-		assert.Len(t, stats, 115)
-		assert.Equal(t, 11072, stats[BandwidthInExceeded])
+	t.Run("IPv6PodNetworking", func(t *testing.T) {
+		t.Setenv(config.HOST_ROOT_ENV, t.TempDir())
+		ipv6, err := ipv6PodNetworking()
+		assert.NoError(t, err)
+		assert.False(t, ipv6)
+
+		checkpoint := config.ToHostPath("/var/run/aws-node/ipam.json")
+		assert.NoError(t, os.MkdirAll(filepath.Dir(checkpoint), 0755))
+		assert.NoError(t, os.WriteFile(checkpoint, []byte(`{"version":"vpc-cni-ipam/1","allocations":[{"containerID":"abc","ifName":"eth0","networkName":"aws-cni","ipv6":"2600:1f14:3d1:2a01:9a6e::1"}]}`), 0644))
+		ipv6, err = ipv6PodNetworking()
+		assert.NoError(t, err)
+		assert.True(t, ipv6)
 	})
 }
 
@@ -935,3 +1053,9 @@ func TestNPAGate_SkipsWithoutEKSAutoTag(t *testing.T) {
 		// no condition emitted — gate working as intended
 	}
 }
+
+func readLines(t *testing.T, path string) []string {
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return strings.Split(string(data), "\n")
+}
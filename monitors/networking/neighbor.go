@@ -0,0 +1,149 @@
+package networking
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"strconv"
+
+	"github.com/vishvananda/netlink"
+
+	"github.com/aws/eks-node-monitoring-agent/pkg/osext"
+	"github.com/aws/eks-node-monitoring-agent/pkg/reasons"
+)
+
+// ~~~~ neighbor tables ~~~~
+
+// neighborTable is a neighbor table of the kernel, which holds the link layer
+// addresses of the hosts and pods on the node's links.
+type neighborTable struct {
+	name   string
+	family int
+	// cache is the name of the table in kernel messages.
+	cache string
+	// sysctlPrefix is the prefix of the sysctls holding the thresholds of the
+	// table.
+	sysctlPrefix string
+}
+
+var neighborTables = []neighborTable{
+	{name: "IPv4", family: netlink.FAMILY_V4, cache: "arp_cache", sysctlPrefix: "net.ipv4.neigh.default"},
+	{name: "IPv6", family: netlink.FAMILY_V6, cache: "ndisc_cache", sysctlPrefix: "net.ipv6.neigh.default"},
+}
+
+// neighborTableOverflowRegex matches the kernel message logged when a neighbor
+// cannot be added because the table holds gc_thresh3 entries, e.g.
+// `neighbour: arp_cache: neighbor table overflow!`
+var neighborTableOverflowRegex = regexp.MustCompile(`neighbour: (\w+): neighbor table overflow!`)
+
+// neighborUsage is the number of entries in a neighbor table, along with its
+// garbage collection thresholds. Above gc_thresh2 the kernel aggressively
+// evicts entries, and it refuses new entries at gc_thresh3.
+type neighborUsage struct {
+	entries   int
+	gcThresh2 int
+	gcThresh3 int
+}
+
+func (u neighborUsage) String() string {
+	return fmt.Sprintf("%d entries, gc_thresh2 %d, gc_thresh3 %d", u.entries, u.gcThresh2, u.gcThresh3)
+}
+
+func (m *NetworkingMonitor) handleNeighborTables() (merr error) {
+	for _, table := range neighborTables {
+		usage, err := readNeighborUsage(table)
+		if err != nil {
+			// the IPv6 sysctls do not exist when IPv6 is disabled.
+			if !errors.Is(err, fs.ErrNotExist) {
+				merr = errors.Join(merr, err)
+			}
+			continue
+		}
+		merr = errors.Join(merr, m.checkNeighborTable(table, usage))
+	}
+	return merr
+}
+
+func (m *NetworkingMonitor) checkNeighborTable(table neighborTable, usage neighborUsage) error {
+	if usage.gcThresh3 > 0 && usage.entries >= usage.gcThresh3 {
+		return m.manager.Notify(context.TODO(),
+			reasons.NeighborTableOverflow.
+				Builder().
+				Message(fmt.Sprintf("The %s neighbor table is full: %s", table.name, usage)).
+				Build(),
+		)
+	} else if usage.gcThresh2 > 0 && usage.entries >= usage.gcThresh2 {
+		return m.manager.Notify(context.TODO(),
+			reasons.NeighborTableNearlyFull.
+				Builder().
+				Message(fmt.Sprintf("The %s neighbor table is above gc_thresh2 and entries are being evicted: %s", table.name, usage)).
+				Build(),
+		)
+	}
+	return nil
+}
+
+func (m *NetworkingMonitor) handleNeighborDmesg(line string) error {
+	matches := neighborTableOverflowRegex.FindStringSubmatch(line)
+	if matches == nil {
+		return nil
+	}
+	message := fmt.Sprintf("The kernel failed to add an entry to the %s neighbor table because it is full", matches[1])
+	for _, table := range neighborTables {
+		if table.cache != matches[1] {
+			continue
+		}
+		message = fmt.Sprintf("The kernel failed to add an entry to the %s neighbor table because it is full", table.name)
+		// the table may have been garbage collected since, but the thresholds
+		// help sizing it.
+		if usage, err := readNeighborUsage(table); err == nil {
+			message += fmt.Sprintf(": %s", usage)
+		} else {
+			m.log.Error(err, "failed to read neighbor table usage", "table", table.name)
+		}
+	}
+	return m.manager.Notify(context.TODO(),
+		reasons.NeighborTableOverflow.
+			Builder().
+			Message(message).
+			Build(),
+	)
+}
+
+func readNeighborUsage(table neighborTable) (neighborUsage, error) {
+	var usage neighborUsage
+	for _, threshold := range []struct {
+		name  string
+		value *int
+	}{
+		{"gc_thresh2", &usage.gcThresh2},
+		{"gc_thresh3", &usage.gcThresh3},
+	} {
+		value, err := osext.ParseSysctl(table.sysctlPrefix+"."+threshold.name, func(b []byte) (int, error) { return strconv.Atoi(string(b)) })
+		if err != nil {
+			return usage, err
+		}
+		*threshold.value = *value
+	}
+	neighbors, err := netlink.NeighList(0, table.family)
+	if err != nil {
+		return usage, fmt.Errorf("listing %s neighbors: %w", table.name, err)
+	}
+	usage.entries = countNeighbors(neighbors)
+	return usage, nil
+}
+
+// countNeighbors counts the neighbors held against the thresholds of the table,
+// which exclude permanent and externally learned entries.
+func countNeighbors(neighbors []netlink.Neigh) int {
+	var count int
+	for _, neighbor := range neighbors {
+		if neighbor.State&netlink.NUD_PERMANENT != 0 || neighbor.Flags&netlink.NTF_EXT_LEARNED != 0 {
+			continue
+		}
+		count++
+	}
+	return count
+}
@@ -0,0 +1,114 @@
+package networking
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vishvananda/netlink"
+
+	"github.com/aws/eks-node-monitoring-agent/api/monitor"
+	"github.com/aws/eks-node-monitoring-agent/pkg/config"
+	"github.com/aws/eks-node-monitoring-agent/pkg/observer"
+)
+
+func TestCountNeighbors(t *testing.T) {
+	assert.Equal(t, 2, countNeighbors([]netlink.Neigh{
+		{State: netlink.NUD_REACHABLE},
+		{State: netlink.NUD_STALE},
+		{State: netlink.NUD_PERMANENT},
+		{State: netlink.NUD_REACHABLE, Flags: netlink.NTF_EXT_LEARNED},
+	}))
+}
+
+func TestNeighborTables(t *testing.T) {
+	ipv4 := neighborTables[0]
+
+	for _, testCase := range []struct {
+		name    string
+		usage   neighborUsage
+		reason  string
+		message string
+	}{
+		{
+			name:  "BelowThresholds",
+			usage: neighborUsage{entries: 511, gcThresh2: 512, gcThresh3: 1024},
+		},
+		{
+			name:    "NearlyFull",
+			usage:   neighborUsage{entries: 512, gcThresh2: 512, gcThresh3: 1024},
+			reason:  "NeighborTableNearlyFull",
+			message: "The IPv4 neighbor table is above gc_thresh2 and entries are being evicted: 512 entries, gc_thresh2 512, gc_thresh3 1024",
+		},
+		{
+			name:    "Overflow",
+			usage:   neighborUsage{entries: 1024, gcThresh2: 512, gcThresh3: 1024},
+			reason:  "NeighborTableOverflow",
+			message: "The IPv4 neighbor table is full: 1024 entries, gc_thresh2 512, gc_thresh3 1024",
+		},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			mon := NewNetworkingMonitor()
+			mockManager := &mockManager{
+				obs: observer.BaseObserver{},
+				res: make(chan monitor.Condition, 5),
+			}
+			mon.Register(context.TODO(), mockManager)
+			assert.NoError(t, mon.checkNeighborTable(ipv4, testCase.usage))
+			if testCase.reason == "" {
+				assert.Empty(t, mockManager.res)
+				return
+			}
+			if assert.Len(t, mockManager.res, 1) {
+				monitorResult := <-mockManager.res
+				assert.Equal(t, testCase.reason, monitorResult.Reason)
+				assert.Equal(t, monitor.SeverityWarning, monitorResult.Severity)
+				assert.Equal(t, testCase.message, monitorResult.Message)
+			}
+		})
+	}
+
+	t.Run("MissingSysctls", func(t *testing.T) {
+		t.Setenv(config.HOST_ROOT_ENV, t.TempDir())
+		mon := NewNetworkingMonitor()
+		mockManager := &mockManager{
+			obs: observer.BaseObserver{},
+			res: make(chan monitor.Condition, 5),
+		}
+		mon.Register(context.TODO(), mockManager)
+		assert.NoError(t, mon.handleNeighborTables())
+		assert.Empty(t, mockManager.res)
+	})
+}
+
+func TestNeighborDmesg(t *testing.T) {
+	t.Setenv(config.HOST_ROOT_ENV, t.TempDir())
+
+	for _, testCase := range []struct {
+		line    string
+		message string
+	}{
+		{"[ 1234.567890] IPv4: martian source 10.0.0.1 from 10.0.0.2, on dev eth0", ""},
+		{"[ 1234.567890] neighbour: arp_cache: neighbor table overflow!", "The kernel failed to add an entry to the IPv4 neighbor table because it is full"},
+		{"[ 1234.567890] neighbour: ndisc_cache: neighbor table overflow!", "The kernel failed to add an entry to the IPv6 neighbor table because it is full"},
+	} {
+		t.Run(testCase.line, func(t *testing.T) {
+			mon := NewNetworkingMonitor()
+			mockManager := &mockManager{
+				obs: observer.BaseObserver{},
+				res: make(chan monitor.Condition, 5),
+			}
+			mon.Register(context.TODO(), mockManager)
+			assert.NoError(t, mon.handleNeighborDmesg(testCase.line))
+			if testCase.message == "" {
+				assert.Empty(t, mockManager.res)
+				return
+			}
+			if assert.Len(t, mockManager.res, 1) {
+				monitorResult := <-mockManager.res
+				assert.Equal(t, "NeighborTableOverflow", monitorResult.Reason)
+				assert.Equal(t, testCase.message, monitorResult.Message)
+			}
+		})
+	}
+}
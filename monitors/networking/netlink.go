@@ -0,0 +1,171 @@
+package networking
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/aws/eks-node-monitoring-agent/api/monitor/resource"
+	"github.com/aws/eks-node-monitoring-agent/pkg/config"
+	"github.com/aws/eks-node-monitoring-agent/pkg/reasons"
+)
+
+const (
+	// linkFlapThreshold is the number of times a link must go down within
+	// linkFlapWindow to be reported as flapping.
+	linkFlapThreshold = 3
+	linkFlapWindow    = 5 * time.Minute
+	// defaultRouteGracePeriod is how long a deleted default route of the main
+	// table has to be added back before it is reported, since routes are
+	// often replaced by deleting and adding them.
+	defaultRouteGracePeriod = 30 * time.Second
+)
+
+// linkState is the operational state of a link, and the recent times that
+// it went down.
+type linkState struct {
+	operState string
+	downs     []time.Time
+}
+
+// deletedRoute is a default route of the main table that was deleted, and not
+// added back yet.
+type deletedRoute struct {
+	route resource.NetlinkRoute
+	time  time.Time
+}
+
+// ~~~~ netlink ~~~~
+
+func (m *NetworkingMonitor) handleNetlinkEvent(line string) error {
+	event, err := resource.ParseNetlinkEvent(line)
+	if err != nil {
+		return fmt.Errorf("parsing netlink event: %w", err)
+	}
+	return m.checkNetlinkEvent(event, time.Now())
+}
+
+func (m *NetworkingMonitor) checkNetlinkEvent(event resource.NetlinkEvent, now time.Time) error {
+	m.netlinkMu.Lock()
+	defer m.netlinkMu.Unlock()
+
+	switch {
+	case event.Link != nil:
+		return m.checkLinkEvent(event.Type, *event.Link, now)
+	case event.Route != nil:
+		route := *event.Route
+		if route.Dst != "default" || route.Table != unix.RT_TABLE_MAIN {
+			// the per-ENI route tables of the VPC CNI are checked by
+			// handleIPRulesAndRoutes, and their default routes are deleted
+			// along with their ENI.
+			return nil
+		}
+		switch event.Type {
+		case resource.NetlinkDelRoute:
+			if _, ok := m.deletedDefaultRoutes[route.Family]; !ok {
+				m.deletedDefaultRoutes[route.Family] = deletedRoute{route: route, time: now}
+			}
+		case resource.NetlinkNewRoute:
+			delete(m.deletedDefaultRoutes, route.Family)
+		}
+	}
+	return nil
+}
+
+func (m *NetworkingMonitor) checkLinkEvent(eventType string, link resource.NetlinkLink, now time.Time) error {
+	if link.Loopback || m.isInterfaceExcluded(link.Name) {
+		return nil
+	}
+	// ENIs are the physical links of the instance, which hybrid nodes do not
+	// have.
+	isENI := link.Kind == "device" && !slices.Contains(m.runtimeContext.Tags(), config.Hybrid)
+
+	if eventType == resource.NetlinkDelLink {
+		delete(m.links, link.Index)
+		if !isENI {
+			return nil
+		}
+		return m.manager.Notify(context.TODO(),
+			reasons.ENIDetached.
+				Builder().
+				Message(fmt.Sprintf("Interface %q, MAC: %q was detached", link.Name, link.HardwareAddr)).
+				Build(),
+		)
+	}
+
+	if link.Registered && isENI {
+		if err := m.manager.Notify(context.TODO(),
+			reasons.ENIAttached.
+				Builder().
+				Message(fmt.Sprintf("Interface %q, MAC: %q was attached", link.Name, link.HardwareAddr)).
+				Build(),
+		); err != nil {
+			return err
+		}
+	}
+
+	state, ok := m.links[link.Index]
+	if !ok {
+		m.links[link.Index] = &linkState{operState: link.OperState}
+		return nil
+	}
+	wentDown := state.operState == "up" && link.OperState != "up"
+	state.operState = link.OperState
+	if !wentDown {
+		return nil
+	}
+	state.downs = append(slices.DeleteFunc(state.downs, func(down time.Time) bool {
+		return now.Sub(down) > linkFlapWindow
+	}), now)
+	if len(state.downs) < linkFlapThreshold {
+		return nil
+	}
+	downs := len(state.downs)
+	// a link that keeps flapping is reported again after as many more downs.
+	state.downs = nil
+	return m.manager.Notify(context.TODO(),
+		reasons.InterfaceFlapping.
+			Builder().
+			Message(fmt.Sprintf("Interface %q, MAC: %q went down %d times within %s", link.Name, link.HardwareAddr, downs, linkFlapWindow)).
+			Build(),
+	)
+}
+
+// checkDefaultRoutes reports the default routes of the main table that were
+// deleted, and not added back within defaultRouteGracePeriod.
+func (m *NetworkingMonitor) checkDefaultRoutes(now time.Time) error {
+	m.netlinkMu.Lock()
+	defer m.netlinkMu.Unlock()
+
+	for _, family := range []string{"inet", "inet6"} {
+		deleted, ok := m.deletedDefaultRoutes[family]
+		if !ok || now.Sub(deleted.time) < defaultRouteGracePeriod {
+			continue
+		}
+		delete(m.deletedDefaultRoutes, family)
+		route := deleted.route
+		description := "default"
+		if route.Gateway != "" {
+			description += " via " + route.Gateway
+		}
+		if route.Dev != "" {
+			description += " dev " + route.Dev
+		}
+		version := "IPv4"
+		if family == "inet6" {
+			version = "IPv6"
+		}
+		if err := m.manager.Notify(context.TODO(),
+			reasons.DefaultRouteDeleted.
+				Builder().
+				Message(fmt.Sprintf("The %s route %q of the main route table was deleted and not added back within %s", version, description, defaultRouteGracePeriod)).
+				Build(),
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
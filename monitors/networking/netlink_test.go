@@ -0,0 +1,173 @@
+package networking
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/sys/unix"
+
+	"github.com/aws/eks-node-monitoring-agent/api/monitor"
+	"github.com/aws/eks-node-monitoring-agent/api/monitor/resource"
+	"github.com/aws/eks-node-monitoring-agent/pkg/config"
+	"github.com/aws/eks-node-monitoring-agent/pkg/observer"
+)
+
+func TestInterfaceFlapping(t *testing.T) {
+	mon := NewNetworkingMonitor()
+	mockManager := &mockManager{
+		obs: observer.BaseObserver{},
+		res: make(chan monitor.Condition, 5),
+	}
+	mon.Register(context.TODO(), mockManager)
+
+	start := time.Now()
+	link := func(name, operState string, elapsed time.Duration) {
+		t.Helper()
+		assert.NoError(t, mon.checkNetlinkEvent(resource.NetlinkEvent{
+			Type: resource.NetlinkNewLink,
+			Link: &resource.NetlinkLink{Index: 2, Name: name, Kind: "device", HardwareAddr: "0a:1b:2c:3d:4e:5f", OperState: operState},
+		}, start.Add(elapsed)))
+	}
+
+	link("ens5", "up", 0)
+	for i := range 2 {
+		link("ens5", "down", time.Duration(2*i)*time.Minute)
+		link("ens5", "up", time.Duration(2*i+1)*time.Minute)
+	}
+	assert.Empty(t, mockManager.res)
+	// the first down is out of the window by now.
+	link("ens5", "down", 6*time.Minute)
+	link("ens5", "up", 6*time.Minute)
+	assert.Empty(t, mockManager.res)
+	link("ens5", "lowerlayerdown", 7*time.Minute)
+	if assert.Len(t, mockManager.res, 1) {
+		monitorResult := <-mockManager.res
+		assert.Equal(t, "InterfaceFlapping", monitorResult.Reason)
+		assert.Equal(t, monitor.SeverityWarning, monitorResult.Severity)
+		assert.Equal(t, `Interface "ens5", MAC: "0a:1b:2c:3d:4e:5f" went down 3 times within 5m0s`, monitorResult.Message)
+	}
+
+	// changes other than the operational state are not downs.
+	link("ens5", "lowerlayerdown", 7*time.Minute)
+	link("ens5", "down", 7*time.Minute)
+	assert.Empty(t, mockManager.res)
+
+	// excluded interfaces are skipped.
+	assert.NoError(t, mon.SetExcludedInterfaceNameRegexps([]string{"^ens5$"}))
+	for range 3 {
+		link("ens5", "up", 8*time.Minute)
+		link("ens5", "down", 8*time.Minute)
+	}
+	assert.Empty(t, mockManager.res)
+}
+
+func TestENIAttachDetach(t *testing.T) {
+	for _, testCase := range []struct {
+		name    string
+		tags    []string
+		link    resource.NetlinkLink
+		reasons []string
+	}{
+		{
+			name:    "ENI",
+			link:    resource.NetlinkLink{Index: 3, Name: "ens6", Kind: "device", HardwareAddr: "0a:1b:2c:3d:4e:5f", OperState: "down", Registered: true},
+			reasons: []string{"ENIAttached", "ENIDetached"},
+		},
+		{
+			name: "Veth",
+			link: resource.NetlinkLink{Index: 9, Name: "eni1a2b3c4d5e6", Kind: "veth", OperState: "down", Registered: true},
+		},
+		{
+			name: "Hybrid",
+			tags: []string{config.Hybrid},
+			link: resource.NetlinkLink{Index: 3, Name: "ens6", Kind: "device", OperState: "down", Registered: true},
+		},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			rtCtx := &config.RuntimeContext{}
+			rtCtx.AddTags(testCase.tags...)
+			mon := NewNetworkingMonitor(WithRuntimeContext(rtCtx))
+			mockManager := &mockManager{
+				obs: observer.BaseObserver{},
+				res: make(chan monitor.Condition, 5),
+			}
+			mon.Register(context.TODO(), mockManager)
+
+			assert.NoError(t, mon.checkNetlinkEvent(resource.NetlinkEvent{Type: resource.NetlinkNewLink, Link: &testCase.link}, time.Now()))
+			// only a registered link is attached.
+			changed := testCase.link
+			changed.Registered = false
+			assert.NoError(t, mon.checkNetlinkEvent(resource.NetlinkEvent{Type: resource.NetlinkNewLink, Link: &changed}, time.Now()))
+			assert.NoError(t, mon.checkNetlinkEvent(resource.NetlinkEvent{Type: resource.NetlinkDelLink, Link: &changed}, time.Now()))
+
+			var reasons []string
+			for len(mockManager.res) > 0 {
+				monitorResult := <-mockManager.res
+				assert.Equal(t, monitor.SeverityInfo, monitorResult.Severity)
+				reasons = append(reasons, monitorResult.Reason)
+			}
+			assert.Equal(t, testCase.reasons, reasons)
+			assert.Empty(t, mon.links)
+		})
+	}
+}
+
+func TestDefaultRouteDeleted(t *testing.T) {
+	mon := NewNetworkingMonitor()
+	mockManager := &mockManager{
+		obs: observer.BaseObserver{},
+		res: make(chan monitor.Condition, 5),
+	}
+	mon.Register(context.TODO(), mockManager)
+
+	start := time.Now()
+	route := func(eventType string, route resource.NetlinkRoute, elapsed time.Duration) {
+		t.Helper()
+		assert.NoError(t, mon.checkNetlinkEvent(resource.NetlinkEvent{Type: eventType, Route: &route}, start.Add(elapsed)))
+	}
+	defaultRoute := resource.NetlinkRoute{Family: "inet", Table: unix.RT_TABLE_MAIN, Dst: "default", Gateway: "10.0.0.1", Dev: "ens5", LinkIndex: 2}
+
+	// a replaced route is added back within the grace period.
+	route(resource.NetlinkDelRoute, defaultRoute, 0)
+	route(resource.NetlinkNewRoute, defaultRoute, time.Second)
+	assert.NoError(t, mon.checkDefaultRoutes(start.Add(time.Minute)))
+	assert.Empty(t, mockManager.res)
+
+	// the default routes of the per-ENI route tables and other routes of the
+	// main table are skipped.
+	route(resource.NetlinkDelRoute, resource.NetlinkRoute{Family: "inet", Table: 2, Dst: "default", Gateway: "10.0.0.1", LinkIndex: 3}, time.Minute)
+	route(resource.NetlinkDelRoute, resource.NetlinkRoute{Family: "inet", Table: unix.RT_TABLE_MAIN, Dst: "10.0.0.0/24", LinkIndex: 2}, time.Minute)
+	assert.NoError(t, mon.checkDefaultRoutes(start.Add(2*time.Minute)))
+	assert.Empty(t, mockManager.res)
+
+	route(resource.NetlinkDelRoute, defaultRoute, 2*time.Minute)
+	assert.NoError(t, mon.checkDefaultRoutes(start.Add(2*time.Minute+10*time.Second)))
+	assert.Empty(t, mockManager.res)
+	assert.NoError(t, mon.checkDefaultRoutes(start.Add(3*time.Minute)))
+	if assert.Len(t, mockManager.res, 1) {
+		monitorResult := <-mockManager.res
+		assert.Equal(t, "DefaultRouteDeleted", monitorResult.Reason)
+		assert.Equal(t, monitor.SeverityWarning, monitorResult.Severity)
+		assert.Equal(t, `The IPv4 route "default via 10.0.0.1 dev ens5" of the main route table was deleted and not added back within 30s`, monitorResult.Message)
+	}
+	// the route is only reported once.
+	assert.NoError(t, mon.checkDefaultRoutes(start.Add(4*time.Minute)))
+	assert.Empty(t, mockManager.res)
+}
+
+func TestHandleNetlinkEvent(t *testing.T) {
+	mon := NewNetworkingMonitor()
+	mockManager := &mockManager{
+		obs: observer.BaseObserver{},
+		res: make(chan monitor.Condition, 5),
+	}
+	mon.Register(context.TODO(), mockManager)
+
+	assert.NoError(t, mon.handleNetlinkEvent(`{"type":"RTM_NEWLINK","link":{"index":3,"name":"ens6","kind":"device","operState":"down","registered":true}}`))
+	if assert.Len(t, mockManager.res, 1) {
+		assert.Equal(t, "ENIAttached", (<-mockManager.res).Reason)
+	}
+	assert.Error(t, mon.handleNetlinkEvent("not json"))
+}
@@ -0,0 +1,241 @@
+package probe
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/ec2/imds"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/aws/eks-node-monitoring-agent/api/monitor"
+	"github.com/aws/eks-node-monitoring-agent/monitors/networking/dns"
+	"github.com/aws/eks-node-monitoring-agent/pkg/config"
+	"github.com/aws/eks-node-monitoring-agent/pkg/pathlib"
+	"github.com/aws/eks-node-monitoring-agent/pkg/reasons"
+)
+
+const (
+	defaultIMDSEndpoint = "http://169.254.169.254"
+	defaultDNSPort      = "53"
+)
+
+var (
+	probeDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "connectivity_probe_duration_seconds",
+			Help:    "Latency of the successful connectivity probes of each target.",
+			Buckets: []float64{0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+		},
+		[]string{"probe", "type"},
+	)
+	probeFailures = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "connectivity_probe_failures_total",
+			Help: "Total number of failed connectivity probes of each target.",
+		},
+		[]string{"probe", "type"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		probeDuration,
+		probeFailures,
+	)
+}
+
+// UsesIMDS reports whether a probe of the type finds its target through the
+// instance metadata service, which hybrid nodes do not have.
+func UsesIMDS(probeType string) bool {
+	return slices.Contains([]string{config.ProbeTypeIMDS, config.ProbeTypeSTS, config.ProbeTypeECR}, probeType)
+}
+
+func NewProber(probe config.ConnectivityProbe) *Prober {
+	return &Prober{
+		probe:        probe,
+		imdsEndpoint: defaultIMDSEndpoint,
+		dnsPort:      defaultDNSPort,
+		client: &http.Client{
+			Transport: &http.Transport{
+				Proxy: http.ProxyFromEnvironment,
+				TLSClientConfig: &tls.Config{
+					// the API server is only probed for connectivity, like the
+					// diagnostic logger does.
+					InsecureSkipVerify: probe.Type == config.ProbeTypeAPIServer,
+				},
+				// every probe opens a new connection, so that the latency
+				// includes the connect and handshake, and a broken path is not
+				// hidden by a connection that is still open.
+				DisableKeepAlives: true,
+			},
+			// redirects are a response of the target itself.
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		},
+	}
+}
+
+// Prober probes a target, and keeps the number of consecutive probes that
+// failed.
+type Prober struct {
+	probe        config.ConnectivityProbe
+	imdsEndpoint string
+	dnsPort      string
+	client       *http.Client
+	// region is the region of the instance, found once for the sts and ecr
+	// probes.
+	region string
+
+	consecutiveFailures int
+}
+
+func (p *Prober) Name() string {
+	return p.probe.Name
+}
+
+func (p *Prober) Interval() time.Duration {
+	return p.probe.GetInterval()
+}
+
+// Probe probes the target once, and returns a condition when the target has
+// failed FailureThreshold consecutive probes. An error is returned when the
+// target could not be found, which does not count as a failed probe.
+func (p *Prober) Probe(ctx context.Context) (*monitor.Condition, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.probe.GetTimeout())
+	defer cancel()
+
+	target, err := p.target(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("finding target of connectivity probe %q: %w", p.probe.Name, err)
+	}
+	start := time.Now()
+	if p.probe.Type == config.ProbeTypeTCP || p.probe.Type == config.ProbeTypeClusterDNS {
+		err = p.dial(ctx, target)
+	} else {
+		err = p.get(ctx, target)
+	}
+	if err == nil {
+		probeDuration.WithLabelValues(p.probe.Name, p.probe.Type).Observe(time.Since(start).Seconds())
+		p.consecutiveFailures = 0
+		return nil, nil
+	}
+	probeFailures.WithLabelValues(p.probe.Name, p.probe.Type).Inc()
+	p.consecutiveFailures++
+	if p.consecutiveFailures < p.probe.GetFailureThreshold() {
+		return nil, nil
+	}
+	condition := reasons.ConnectivityProbeFailing.
+		Builder().
+		Message(fmt.Sprintf("Connectivity probe %q to %s failed %d consecutive times: %v", p.probe.Name, target, p.consecutiveFailures, err)).
+		Build()
+	return &condition, nil
+}
+
+// target returns the URL of the target, or its address for probes that
+// connect over TCP.
+func (p *Prober) target(ctx context.Context) (string, error) {
+	switch p.probe.Type {
+	case config.ProbeTypeAPIServer:
+		server, err := apiServer()
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSuffix(server, "/") + "/livez", nil
+	case config.ProbeTypeClusterDNS:
+		addresses, err := dns.ClusterDNS()
+		if err != nil {
+			return "", err
+		}
+		if len(addresses) == 0 {
+			return "", errors.New("the kubelet config has no clusterDNS")
+		}
+		return net.JoinHostPort(addresses[0], p.dnsPort), nil
+	case config.ProbeTypeIMDS:
+		return p.imdsEndpoint + "/latest/meta-data/", nil
+	case config.ProbeTypeSTS, config.ProbeTypeECR:
+		if p.region == "" {
+			out, err := imds.New(imds.Options{Endpoint: p.imdsEndpoint}).GetRegion(ctx, &imds.GetRegionInput{})
+			if err != nil {
+				return "", fmt.Errorf("getting region: %w", err)
+			}
+			p.region = out.Region
+		}
+		return regionalEndpoint(p.probe.Type, p.region), nil
+	default:
+		return p.probe.Target, nil
+	}
+}
+
+// get probes a URL. The built-in targets only need to answer, while http
+// probes must answer with a successful status.
+func (p *Prober) get(ctx context.Context, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if p.probe.Type != config.ProbeTypeHTTP {
+		return nil
+	}
+	if codes := p.probe.SuccessStatusCodes; len(codes) > 0 {
+		if !slices.Contains(codes, resp.StatusCode) {
+			return fmt.Errorf("unexpected status %s", resp.Status)
+		}
+	} else if resp.StatusCode >= 500 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+func (p *Prober) dial(ctx context.Context, address string) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// regionalEndpoint returns the URL of the regional endpoint of the sts or ecr
+// service.
+func regionalEndpoint(probeType, region string) string {
+	domain := "amazonaws.com"
+	if strings.HasPrefix(region, "cn-") {
+		domain = "amazonaws.com.cn"
+	}
+	if probeType == config.ProbeTypeECR {
+		return fmt.Sprintf("https://api.ecr.%s.%s/", region, domain)
+	}
+	return fmt.Sprintf("https://sts.%s.%s/", region, domain)
+}
+
+// apiServer returns the URL of the API server from the kubelet kubeconfig.
+func apiServer() (string, error) {
+	kubeconfigPath := pathlib.ResolveKubeconfig(config.HostRoot())
+	if kubeconfigPath == "" {
+		return "", errors.New("could not find kubeconfig")
+	}
+	kubeconfig, err := clientcmd.LoadFromFile(kubeconfigPath)
+	if err != nil {
+		return "", err
+	}
+	cluster := pathlib.ClusterForCurrentContext(kubeconfig)
+	if cluster == nil || cluster.Server == "" {
+		return "", fmt.Errorf("kubeconfig %s has no cluster server", kubeconfigPath)
+	}
+	return cluster.Server, nil
+}
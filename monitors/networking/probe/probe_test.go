@@ -0,0 +1,184 @@
+package probe
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/eks-node-monitoring-agent/pkg/config"
+)
+
+func TestProbeHTTP(t *testing.T) {
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	threshold := 2
+	prober := NewProber(config.ConnectivityProbe{Name: "app", Type: config.ProbeTypeHTTP, Target: server.URL, FailureThreshold: &threshold})
+	condition, err := prober.Probe(context.Background())
+	assert.NoError(t, err)
+	assert.Nil(t, condition)
+
+	// a client error is an answer of a reachable target.
+	status = http.StatusNotFound
+	condition, err = prober.Probe(context.Background())
+	assert.NoError(t, err)
+	assert.Nil(t, condition)
+
+	status = http.StatusServiceUnavailable
+	condition, err = prober.Probe(context.Background())
+	assert.NoError(t, err)
+	assert.Nil(t, condition, "the target is only reported after consecutive failures")
+	condition, err = prober.Probe(context.Background())
+	assert.NoError(t, err)
+	if assert.NotNil(t, condition) {
+		assert.Equal(t, "ConnectivityProbeFailing", condition.Reason)
+		assert.Equal(t, fmt.Sprintf("Connectivity probe \"app\" to %s failed 2 consecutive times: unexpected status 503 Service Unavailable", server.URL), condition.Message)
+	}
+
+	// a successful probe resets the consecutive failures.
+	status = http.StatusOK
+	condition, err = prober.Probe(context.Background())
+	assert.NoError(t, err)
+	assert.Nil(t, condition)
+	status = http.StatusServiceUnavailable
+	condition, err = prober.Probe(context.Background())
+	assert.NoError(t, err)
+	assert.Nil(t, condition)
+}
+
+func TestProbeHTTPSuccessStatusCodes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	threshold := 1
+	for _, testCase := range []struct {
+		codes   []int
+		failing bool
+	}{
+		{[]int{http.StatusOK, http.StatusUnauthorized}, false},
+		{[]int{http.StatusOK}, true},
+	} {
+		prober := NewProber(config.ConnectivityProbe{Name: "app", Type: config.ProbeTypeHTTP, Target: server.URL, FailureThreshold: &threshold, SuccessStatusCodes: testCase.codes})
+		condition, err := prober.Probe(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, testCase.failing, condition != nil, testCase.codes)
+	}
+}
+
+func TestProbeTCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := listener.Addr().String()
+
+	threshold := 1
+	prober := NewProber(config.ConnectivityProbe{Name: "db", Type: config.ProbeTypeTCP, Target: address, FailureThreshold: &threshold})
+	condition, err := prober.Probe(context.Background())
+	assert.NoError(t, err)
+	assert.Nil(t, condition)
+
+	listener.Close()
+	condition, err = prober.Probe(context.Background())
+	assert.NoError(t, err)
+	if assert.NotNil(t, condition) {
+		assert.Contains(t, condition.Message, "connection refused")
+	}
+}
+
+func TestProbeBuiltinTargets(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv(config.HOST_ROOT_ENV, root)
+	threshold := 1
+
+	t.Run("APIServer", func(t *testing.T) {
+		prober := NewProber(config.ConnectivityProbe{Name: "apiserver", Type: config.ProbeTypeAPIServer, FailureThreshold: &threshold})
+		// finding no target is not a failed probe.
+		_, err := prober.Probe(context.Background())
+		assert.ErrorContains(t, err, "could not find kubeconfig")
+		assert.Zero(t, prober.consecutiveFailures)
+
+		var path string
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			path = r.URL.Path
+			w.WriteHeader(http.StatusUnauthorized)
+		}))
+		defer server.Close()
+		writeFile(t, filepath.Join(root, "var/lib/kubelet/kubeconfig"), fmt.Sprintf(`apiVersion: v1
+kind: Config
+clusters:
+- name: kubernetes
+  cluster:
+    server: %s
+contexts:
+- name: kubelet
+  context:
+    cluster: kubernetes
+    user: kubelet
+current-context: kubelet
+`, server.URL))
+		condition, err := prober.Probe(context.Background())
+		assert.NoError(t, err)
+		assert.Nil(t, condition)
+		assert.Equal(t, "/livez", path)
+	})
+
+	t.Run("ClusterDNS", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer listener.Close()
+		_, port, _ := net.SplitHostPort(listener.Addr().String())
+
+		prober := NewProber(config.ConnectivityProbe{Name: "cluster-dns", Type: config.ProbeTypeClusterDNS, FailureThreshold: &threshold})
+		prober.dnsPort = port
+		_, err = prober.Probe(context.Background())
+		assert.ErrorContains(t, err, "no clusterDNS")
+
+		writeFile(t, filepath.Join(root, "etc/kubernetes/kubelet/config.json"), `{"clusterDNS": ["127.0.0.1"]}`)
+		condition, err := prober.Probe(context.Background())
+		assert.NoError(t, err)
+		assert.Nil(t, condition)
+	})
+
+	t.Run("IMDS", func(t *testing.T) {
+		// IMDSv2 rejects requests without a token, which still answers.
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}))
+		prober := NewProber(config.ConnectivityProbe{Name: "imds", Type: config.ProbeTypeIMDS, FailureThreshold: &threshold})
+		prober.imdsEndpoint = server.URL
+		condition, err := prober.Probe(context.Background())
+		assert.NoError(t, err)
+		assert.Nil(t, condition)
+
+		server.Close()
+		condition, err = prober.Probe(context.Background())
+		assert.NoError(t, err)
+		assert.NotNil(t, condition)
+	})
+}
+
+func TestRegionalEndpoint(t *testing.T) {
+	assert.Equal(t, "https://sts.us-west-2.amazonaws.com/", regionalEndpoint(config.ProbeTypeSTS, "us-west-2"))
+	assert.Equal(t, "https://api.ecr.us-west-2.amazonaws.com/", regionalEndpoint(config.ProbeTypeECR, "us-west-2"))
+	assert.Equal(t, "https://api.ecr.cn-north-1.amazonaws.com.cn/", regionalEndpoint(config.ProbeTypeECR, "cn-north-1"))
+	assert.True(t, UsesIMDS(config.ProbeTypeSTS))
+	assert.False(t, UsesIMDS(config.ProbeTypeAPIServer))
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
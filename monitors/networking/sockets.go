@@ -0,0 +1,190 @@
+package networking
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/aws/eks-node-monitoring-agent/pkg/osext"
+	"github.com/aws/eks-node-monitoring-agent/pkg/reasons"
+)
+
+const (
+	// socketWarningRatio is the utilization of a socket limit above which a
+	// sustained utilization is reported.
+	socketWarningRatio = 0.8
+	// socketSustainedChecks is the number of consecutive checks a socket limit
+	// must be breached to be reported, since connection churn comes and goes
+	// in bursts.
+	socketSustainedChecks = 2
+)
+
+// socketLimits are the sysctls bounding the TCP sockets of the host.
+type socketLimits struct {
+	// maxOrphans is net.ipv4.tcp_max_orphans, above which orphaned sockets
+	// are reset.
+	maxOrphans int
+	// maxTimeWait is net.ipv4.tcp_max_tw_buckets, above which sockets skip
+	// TIME_WAIT.
+	maxTimeWait int
+	// memPressure is the pressure threshold of net.ipv4.tcp_mem, in pages,
+	// above which the kernel moderates the memory of TCP sockets.
+	memPressure int
+	// ephemeralPorts is net.ipv4.ip_local_port_range, the range of local
+	// ports of outgoing connections. It applies to IPv6 as well.
+	ephemeralPorts [2]int
+}
+
+// destination is a remote address reached from a local address. Each of its
+// outgoing connections uses a distinct ephemeral port.
+type destination struct {
+	local  netip.Addr
+	remote netip.AddrPort
+}
+
+// ~~~~ sockets ~~~~
+
+func (m *NetworkingMonitor) handleSockets() error {
+	limits, err := readSocketLimits()
+	if err != nil {
+		return err
+	}
+	sockstat, err := osext.ReadSockstat()
+	if err != nil {
+		return err
+	}
+	sockets, err := osext.ReadTCPSockets()
+	if err != nil {
+		return err
+	}
+	return errors.Join(
+		m.checkSockstat(sockstat, limits),
+		m.checkEphemeralPorts(sockets, limits.ephemeralPorts),
+	)
+}
+
+func readSocketLimits() (socketLimits, error) {
+	var limits socketLimits
+	maxOrphans, err := osext.ParseSysctl("net.ipv4.tcp_max_orphans", func(b []byte) (int, error) { return strconv.Atoi(string(b)) })
+	if err != nil {
+		return limits, err
+	}
+	maxTimeWait, err := osext.ParseSysctl("net.ipv4.tcp_max_tw_buckets", func(b []byte) (int, error) { return strconv.Atoi(string(b)) })
+	if err != nil {
+		return limits, err
+	}
+	tcpMem, err := osext.ParseSysctl("net.ipv4.tcp_mem", parseSysctlInts)
+	if err != nil {
+		return limits, err
+	}
+	if len(*tcpMem) != 3 {
+		return limits, fmt.Errorf("expected 3 values in net.ipv4.tcp_mem, got %v", *tcpMem)
+	}
+	portRange, err := osext.ParseSysctl("net.ipv4.ip_local_port_range", parseSysctlInts)
+	if err != nil {
+		return limits, err
+	}
+	if len(*portRange) != 2 {
+		return limits, fmt.Errorf("expected 2 values in net.ipv4.ip_local_port_range, got %v", *portRange)
+	}
+	return socketLimits{
+		maxOrphans:     *maxOrphans,
+		maxTimeWait:    *maxTimeWait,
+		memPressure:    (*tcpMem)[1],
+		ephemeralPorts: [2]int{(*portRange)[0], (*portRange)[1]},
+	}, nil
+}
+
+// parseSysctlInts parses a sysctl holding whitespace separated integers, like
+// net.ipv4.tcp_mem.
+func parseSysctlInts(b []byte) ([]int, error) {
+	var values []int
+	for _, field := range strings.Fields(string(b)) {
+		value, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// sustained records whether the socket limit of the reason is breached by the
+// current check, and returns whether it was breached for socketSustainedChecks
+// checks in a row.
+func (m *NetworkingMonitor) sustained(reason reasons.ReasonMeta, breached bool) bool {
+	if !breached {
+		delete(m.socketBreaches, reason)
+		return false
+	}
+	m.socketBreaches[reason]++
+	return m.socketBreaches[reason] >= socketSustainedChecks
+}
+
+func (m *NetworkingMonitor) checkSockstat(sockstat osext.Sockstat, limits socketLimits) (merr error) {
+	tcp := sockstat["TCP"]
+	if m.sustained(reasons.TCPOrphanedSocketsHigh, limits.maxOrphans > 0 && float64(tcp["orphan"]) >= socketWarningRatio*float64(limits.maxOrphans)) {
+		merr = errors.Join(merr, m.manager.Notify(context.TODO(),
+			reasons.TCPOrphanedSocketsHigh.
+				Builder().
+				Message(fmt.Sprintf("%d orphaned TCP sockets, %0.1f%% of net.ipv4.tcp_max_orphans %d", tcp["orphan"], 100*float64(tcp["orphan"])/float64(limits.maxOrphans), limits.maxOrphans)).
+				Build(),
+		))
+	}
+	if m.sustained(reasons.TCPTimeWaitSocketsHigh, limits.maxTimeWait > 0 && float64(tcp["tw"]) >= socketWarningRatio*float64(limits.maxTimeWait)) {
+		merr = errors.Join(merr, m.manager.Notify(context.TODO(),
+			reasons.TCPTimeWaitSocketsHigh.
+				Builder().
+				Message(fmt.Sprintf("%d TCP sockets in TIME_WAIT, %0.1f%% of net.ipv4.tcp_max_tw_buckets %d", tcp["tw"], 100*float64(tcp["tw"])/float64(limits.maxTimeWait), limits.maxTimeWait)).
+				Build(),
+		))
+	}
+	if m.sustained(reasons.TCPMemoryPressure, limits.memPressure > 0 && tcp["mem"] >= limits.memPressure) {
+		merr = errors.Join(merr, m.manager.Notify(context.TODO(),
+			reasons.TCPMemoryPressure.
+				Builder().
+				Message(fmt.Sprintf("TCP sockets use %d pages of memory, above the pressure threshold %d of net.ipv4.tcp_mem (%d sockets in use)", tcp["mem"], limits.memPressure, tcp["inuse"]+sockstat["TCP6"]["inuse"])).
+				Build(),
+		))
+	}
+	return merr
+}
+
+func (m *NetworkingMonitor) checkEphemeralPorts(sockets []osext.TCPSocket, ephemeralPorts [2]int) error {
+	size := ephemeralPorts[1] - ephemeralPorts[0] + 1
+	if size <= 0 {
+		return nil
+	}
+	connections := make(map[destination]int)
+	for _, socket := range sockets {
+		port := int(socket.Local.Port())
+		if socket.State == osext.TCPListen || socket.Remote.Port() == 0 || port < ephemeralPorts[0] || port > ephemeralPorts[1] {
+			continue
+		}
+		connections[destination{local: socket.Local.Addr(), remote: socket.Remote}]++
+	}
+	var saturated []destination
+	for dest, count := range connections {
+		if float64(count) >= socketWarningRatio*float64(size) {
+			saturated = append(saturated, dest)
+		}
+	}
+	if !m.sustained(reasons.EphemeralPortsExhausted, len(saturated) > 0) {
+		return nil
+	}
+	// report the most saturated destination.
+	dest := slices.MaxFunc(saturated, func(a, b destination) int {
+		return cmp.Or(cmp.Compare(connections[a], connections[b]), a.remote.Compare(b.remote), a.local.Compare(b.local))
+	})
+	return m.manager.Notify(context.TODO(),
+		reasons.EphemeralPortsExhausted.
+			Builder().
+			Message(fmt.Sprintf("%d of %d ephemeral ports in net.ipv4.ip_local_port_range %d-%d are in use from %s to %s", connections[dest], size, ephemeralPorts[0], ephemeralPorts[1], dest.local, dest.remote)).
+			Build(),
+	)
+}
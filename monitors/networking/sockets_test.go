@@ -0,0 +1,159 @@
+package networking
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aws/eks-node-monitoring-agent/api/monitor"
+	"github.com/aws/eks-node-monitoring-agent/pkg/config"
+	"github.com/aws/eks-node-monitoring-agent/pkg/observer"
+	"github.com/aws/eks-node-monitoring-agent/pkg/osext"
+)
+
+func TestSockstat(t *testing.T) {
+	limits := socketLimits{maxOrphans: 1000, maxTimeWait: 1000, memPressure: 500, ephemeralPorts: [2]int{32768, 60999}}
+
+	for _, testCase := range []struct {
+		name    string
+		tcp     map[string]int
+		reason  string
+		message string
+	}{
+		{
+			name: "BelowLimits",
+			tcp:  map[string]int{"inuse": 10, "orphan": 799, "tw": 799, "mem": 499},
+		},
+		{
+			name:    "Orphans",
+			tcp:     map[string]int{"inuse": 10, "orphan": 800, "tw": 0, "mem": 10},
+			reason:  "TCPOrphanedSocketsHigh",
+			message: "800 orphaned TCP sockets, 80.0% of net.ipv4.tcp_max_orphans 1000",
+		},
+		{
+			name:    "TimeWait",
+			tcp:     map[string]int{"inuse": 10, "orphan": 0, "tw": 950, "mem": 10},
+			reason:  "TCPTimeWaitSocketsHigh",
+			message: "950 TCP sockets in TIME_WAIT, 95.0% of net.ipv4.tcp_max_tw_buckets 1000",
+		},
+		{
+			name:    "MemoryPressure",
+			tcp:     map[string]int{"inuse": 10, "orphan": 0, "tw": 0, "mem": 500},
+			reason:  "TCPMemoryPressure",
+			message: "TCP sockets use 500 pages of memory, above the pressure threshold 500 of net.ipv4.tcp_mem (15 sockets in use)",
+		},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			mon := NewNetworkingMonitor()
+			mockManager := &mockManager{
+				obs: observer.BaseObserver{},
+				res: make(chan monitor.Condition, 5),
+			}
+			mon.Register(context.TODO(), mockManager)
+			sockstat := osext.Sockstat{"TCP": testCase.tcp, "TCP6": {"inuse": 5}}
+			below := osext.Sockstat{"TCP": {"inuse": 10}}
+			// a breach is only reported on consecutive checks.
+			assert.NoError(t, mon.checkSockstat(sockstat, limits))
+			assert.NoError(t, mon.checkSockstat(below, limits))
+			assert.NoError(t, mon.checkSockstat(sockstat, limits))
+			assert.Empty(t, mockManager.res)
+			assert.NoError(t, mon.checkSockstat(sockstat, limits))
+			if testCase.reason == "" {
+				assert.Empty(t, mockManager.res)
+				return
+			}
+			if assert.Len(t, mockManager.res, 1) {
+				monitorResult := <-mockManager.res
+				assert.Equal(t, testCase.reason, monitorResult.Reason)
+				assert.Equal(t, monitor.SeverityWarning, monitorResult.Severity)
+				assert.Equal(t, testCase.message, monitorResult.Message)
+			}
+		})
+	}
+}
+
+func TestEphemeralPorts(t *testing.T) {
+	connect := func(local, remote string, ports int) []osext.TCPSocket {
+		var sockets []osext.TCPSocket
+		for port := range ports {
+			sockets = append(sockets, osext.TCPSocket{
+				Local:  netip.AddrPortFrom(netip.MustParseAddr(local), uint16(61000+port)),
+				Remote: netip.MustParseAddrPort(remote),
+				State:  osext.TCPEstablished,
+			})
+		}
+		return sockets
+	}
+	var sockets []osext.TCPSocket
+	sockets = append(sockets, connect("10.0.0.11", "10.0.0.1:443", 79)...)
+	sockets = append(sockets, connect("10.0.0.11", "10.0.0.2:443", 90)...)
+	// the same destination from another local address has its own ports.
+	sockets = append(sockets, connect("10.0.0.12", "10.0.0.2:443", 50)...)
+	// listening sockets do not use ephemeral ports.
+	sockets = append(sockets, osext.TCPSocket{Local: netip.MustParseAddrPort("0.0.0.0:61000"), State: osext.TCPListen})
+
+	mon := NewNetworkingMonitor()
+	mockManager := &mockManager{
+		obs: observer.BaseObserver{},
+		res: make(chan monitor.Condition, 5),
+	}
+	mon.Register(context.TODO(), mockManager)
+
+	assert.NoError(t, mon.checkEphemeralPorts(sockets, [2]int{61000, 61099}))
+	assert.Empty(t, mockManager.res)
+	assert.NoError(t, mon.checkEphemeralPorts(sockets, [2]int{61000, 61099}))
+	if assert.Len(t, mockManager.res, 1) {
+		monitorResult := <-mockManager.res
+		assert.Equal(t, "EphemeralPortsExhausted", monitorResult.Reason)
+		assert.Equal(t, "90 of 100 ephemeral ports in net.ipv4.ip_local_port_range 61000-61099 are in use from 10.0.0.11 to 10.0.0.2:443", monitorResult.Message)
+	}
+
+	// connections from ports outside of the range are not counted.
+	assert.NoError(t, mon.checkEphemeralPorts(sockets, [2]int{61050, 61149}))
+	assert.NoError(t, mon.checkEphemeralPorts(sockets, [2]int{61000, 61099}))
+	assert.Empty(t, mockManager.res)
+}
+
+func TestHandleSockets(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv(config.HOST_ROOT_ENV, root)
+	for path, content := range map[string]string{
+		"proc/sys/net/ipv4/tcp_max_orphans":     "1000",
+		"proc/sys/net/ipv4/tcp_max_tw_buckets":  "1000",
+		"proc/sys/net/ipv4/tcp_mem":             "100\t200\t300",
+		"proc/sys/net/ipv4/ip_local_port_range": "61000\t61001",
+		"proc/net/sockstat":                     "sockets: used 3\nTCP: inuse 2 orphan 0 tw 0 alloc 2 mem 1\n",
+	} {
+		assert.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(root, path)), 0755))
+		assert.NoError(t, os.WriteFile(filepath.Join(root, path), []byte(content), 0644))
+	}
+	var tcp strings.Builder
+	tcp.WriteString("  sl  local_address rem_address   st\n")
+	for i, port := range []string{"EE48", "EE49"} {
+		fmt.Fprintf(&tcp, "%d: 0B00000A:%s 0100000A:01BB 01 00000000:00000000\n", i, port)
+	}
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "proc/net/tcp"), []byte(tcp.String()), 0644))
+
+	mon := NewNetworkingMonitor()
+	mockManager := &mockManager{
+		obs: observer.BaseObserver{},
+		res: make(chan monitor.Condition, 5),
+	}
+	mon.Register(context.TODO(), mockManager)
+	assert.NoError(t, mon.handleSockets())
+	assert.NoError(t, mon.handleSockets())
+	if assert.Len(t, mockManager.res, 1) {
+		monitorResult := <-mockManager.res
+		assert.Equal(t, "EphemeralPortsExhausted", monitorResult.Reason)
+		assert.Equal(t, "2 of 2 ephemeral ports in net.ipv4.ip_local_port_range 61000-61001 are in use from 10.0.0.11 to 10.0.0.1:443", monitorResult.Message)
+	}
+
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "proc/sys/net/ipv4/tcp_mem"), []byte("100\t200"), 0644))
+	assert.Error(t, mon.handleSockets())
+}
@@ -0,0 +1,118 @@
+package remote
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/aws/eks-node-monitoring-agent/api/monitor"
+	"github.com/aws/eks-node-monitoring-agent/api/monitor/resource"
+	pluginv1 "github.com/aws/eks-node-monitoring-agent/api/plugin/v1"
+	"github.com/aws/eks-node-monitoring-agent/pkg/config"
+	"github.com/aws/eks-node-monitoring-agent/pkg/util"
+)
+
+var _ monitor.Monitor = (*remoteMonitor)(nil)
+
+// subscriberQueueSize is the number of events buffered for each open
+// Subscribe stream. Events are dropped for a stream that falls this far
+// behind, so that a slow plugin cannot stall the agent's observers.
+const subscriberQueueSize = 100
+
+func newRemoteMonitor(plugin config.RemotePlugin) *remoteMonitor {
+	return &remoteMonitor{
+		plugin:      plugin,
+		subscribers: map[chan *pluginv1.Event]struct{}{},
+	}
+}
+
+// remoteMonitor stands in for an out-of-process plugin within the agent. It
+// forwards the plugin's observer streams to its open Subscribe streams and
+// relays its conditions to the manager.
+type remoteMonitor struct {
+	plugin config.RemotePlugin
+
+	mu          sync.Mutex
+	manager     monitor.Manager
+	subscribers map[chan *pluginv1.Event]struct{}
+}
+
+func (m *remoteMonitor) Name() string {
+	return "remote-" + m.plugin.Name
+}
+
+// ConditionType returns the node condition that the plugin's conditions are
+// reported under.
+func (m *remoteMonitor) ConditionType() corev1.NodeConditionType {
+	return corev1.NodeConditionType(m.plugin.GetConditionType())
+}
+
+func (m *remoteMonitor) Conditions() []monitor.Condition {
+	return []monitor.Condition{}
+}
+
+func (m *remoteMonitor) Register(ctx context.Context, mgr monitor.Manager) error {
+	for _, res := range m.plugin.Resources {
+		parts := make([]resource.Part, 0, len(res.Parts))
+		for _, part := range res.Parts {
+			parts = append(parts, resource.Part(part))
+		}
+		channel, err := mgr.Subscribe(resource.Type(res.Type), parts)
+		if err != nil {
+			return err
+		}
+		event := toResource(res)
+		handler := util.NewChannelHandler(func(msg string) error {
+			m.broadcast(&pluginv1.Event{Resource: event, Message: msg, Time: timestamppb.New(time.Now())})
+			return nil
+		}, channel)
+		go handler.Start(ctx)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.manager = mgr
+	return nil
+}
+
+// getManager returns the manager the monitor was registered with, or nil
+// before registration.
+func (m *remoteMonitor) getManager() monitor.Manager {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.manager
+}
+
+func (m *remoteMonitor) subscribe() chan *pluginv1.Event {
+	ch := make(chan *pluginv1.Event, subscriberQueueSize)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.subscribers[ch] = struct{}{}
+	return ch
+}
+
+func (m *remoteMonitor) unsubscribe(ch chan *pluginv1.Event) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.subscribers, ch)
+}
+
+func (m *remoteMonitor) broadcast(event *pluginv1.Event) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for ch := range m.subscribers {
+		select {
+		case ch <- event:
+		default:
+			// the subscriber is not keeping up, drop the event rather than
+			// blocking the observer.
+		}
+	}
+}
+
+func toResource(res config.RemotePluginResource) *pluginv1.Resource {
+	return &pluginv1.Resource{Type: res.Type, Parts: res.Parts}
+}
@@ -0,0 +1,162 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/aws/eks-node-monitoring-agent/api/monitor"
+	pluginv1 "github.com/aws/eks-node-monitoring-agent/api/plugin/v1"
+	"github.com/aws/eks-node-monitoring-agent/pkg/config"
+	"github.com/aws/eks-node-monitoring-agent/pkg/monitor/framework"
+	"github.com/aws/eks-node-monitoring-agent/pkg/monitor/registry"
+)
+
+// PluginNamePrefix prefixes the registry name of every remote plugin.
+const PluginNamePrefix = "remote/"
+
+var severities = map[pluginv1.Severity]monitor.Severity{
+	pluginv1.Severity_SEVERITY_INFO:    monitor.SeverityInfo,
+	pluginv1.Severity_SEVERITY_WARNING: monitor.SeverityWarning,
+	pluginv1.Severity_SEVERITY_FATAL:   monitor.SeverityFatal,
+}
+
+// NewServer creates the plugin API server for the configured remote plugins.
+// The server listens on socketPath once Serve is called.
+func NewServer(socketPath string, plugins []config.RemotePlugin) *Server {
+	s := &Server{
+		socketPath: socketPath,
+		monitors:   map[string]*remoteMonitor{},
+	}
+	for _, plugin := range plugins {
+		mon := newRemoteMonitor(plugin)
+		s.monitors[plugin.Name] = mon
+		s.plugins = append(s.plugins, framework.NewPlugin(PluginNamePrefix+plugin.Name, []monitor.Monitor{mon}))
+	}
+	return s
+}
+
+// Server implements the plugin API, backing each remote plugin with a monitor
+// registered in the agent like any in-process monitor.
+type Server struct {
+	pluginv1.UnimplementedPluginServiceServer
+
+	socketPath string
+	monitors   map[string]*remoteMonitor
+	plugins    []registry.MonitorPlugin
+}
+
+// Plugins returns a registry plugin for each remote plugin, so that remote
+// plugins are listed and registered alongside the built-in ones.
+func (s *Server) Plugins() []registry.MonitorPlugin {
+	return s.plugins
+}
+
+// Serve listens on the server's Unix socket until ctx is cancelled.
+//
+// The socket is the only access control on the API: it is created owner-only
+// inside an owner-only directory, so only processes running as the agent's
+// user can connect.
+func (s *Server) Serve(ctx context.Context) error {
+	if err := os.MkdirAll(filepath.Dir(s.socketPath), 0o700); err != nil {
+		return fmt.Errorf("creating plugin socket directory: %w", err)
+	}
+	// remove the socket left behind by a previous run of the agent.
+	if err := os.Remove(s.socketPath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("removing stale plugin socket: %w", err)
+	}
+	listener, err := net.Listen("unix", s.socketPath)
+	if err != nil {
+		return fmt.Errorf("listening on plugin socket: %w", err)
+	}
+	if err := os.Chmod(s.socketPath, 0o600); err != nil {
+		listener.Close()
+		return fmt.Errorf("restricting plugin socket permissions: %w", err)
+	}
+
+	server := grpc.NewServer()
+	pluginv1.RegisterPluginServiceServer(server, s)
+	go func() {
+		<-ctx.Done()
+		server.Stop()
+	}()
+
+	log.FromContext(ctx).Info("serving remote plugin API", "socket", s.socketPath)
+	return server.Serve(listener)
+}
+
+func (s *Server) lookup(name string) (*remoteMonitor, error) {
+	mon, ok := s.monitors[name]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "remote plugin %q is not configured", name)
+	}
+	return mon, nil
+}
+
+// Register implements pluginv1.PluginServiceServer.
+func (s *Server) Register(ctx context.Context, req *pluginv1.RegisterRequest) (*pluginv1.RegisterResponse, error) {
+	mon, err := s.lookup(req.GetPlugin())
+	if err != nil {
+		return nil, err
+	}
+	log.FromContext(ctx).Info("remote plugin registered", "plugin", req.GetPlugin())
+	resp := &pluginv1.RegisterResponse{ConditionType: mon.plugin.GetConditionType()}
+	for _, res := range mon.plugin.Resources {
+		resp.Resources = append(resp.Resources, toResource(res))
+	}
+	return resp, nil
+}
+
+// Subscribe implements pluginv1.PluginServiceServer.
+func (s *Server) Subscribe(req *pluginv1.SubscribeRequest, stream grpc.ServerStreamingServer[pluginv1.Event]) error {
+	mon, err := s.lookup(req.GetPlugin())
+	if err != nil {
+		return err
+	}
+	events := mon.subscribe()
+	defer mon.unsubscribe(events)
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case event := <-events:
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Notify implements pluginv1.PluginServiceServer.
+func (s *Server) Notify(ctx context.Context, req *pluginv1.NotifyRequest) (*pluginv1.NotifyResponse, error) {
+	mon, err := s.lookup(req.GetPlugin())
+	if err != nil {
+		return nil, err
+	}
+	mgr := mon.getManager()
+	if mgr == nil {
+		return nil, status.Errorf(codes.Unavailable, "remote plugin %q is not registered with the monitor manager yet", req.GetPlugin())
+	}
+	condition := monitor.Condition{
+		Reason:         req.GetCondition().GetReason(),
+		Message:        req.GetCondition().GetMessage(),
+		Severity:       severities[req.GetCondition().GetSeverity()],
+		MinOccurrences: req.GetCondition().GetMinOccurrences(),
+	}
+	if err := registry.ValidateCondition(condition); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := mgr.Notify(ctx, condition); err != nil {
+		return nil, status.FromContextError(err).Err()
+	}
+	return &pluginv1.NotifyResponse{}, nil
+}
@@ -0,0 +1,188 @@
+package remote
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/aws/eks-node-monitoring-agent/api/monitor"
+	"github.com/aws/eks-node-monitoring-agent/api/monitor/resource"
+	pluginv1 "github.com/aws/eks-node-monitoring-agent/api/plugin/v1"
+	"github.com/aws/eks-node-monitoring-agent/pkg/config"
+)
+
+type mockManager struct {
+	res       chan monitor.Condition
+	observers map[string]chan string
+}
+
+func (m *mockManager) Subscribe(rType resource.Type, rParts []resource.Part) (<-chan string, error) {
+	key := string(rType)
+	for _, part := range rParts {
+		key += "-" + string(part)
+	}
+	ch := make(chan string, 10)
+	m.observers[key] = ch
+	return ch, nil
+}
+
+func (m *mockManager) Notify(ctx context.Context, condition monitor.Condition) error {
+	m.res <- condition
+	return nil
+}
+
+// startServer serves the plugin API for plugins on a temporary socket and
+// returns a client connected to it.
+func startServer(t *testing.T, plugins []config.RemotePlugin) (*Server, pluginv1.PluginServiceClient, string) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	// unix socket paths are limited in length, so avoid the long test temp dir.
+	dir, err := os.MkdirTemp("", "nma")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	socketPath := filepath.Join(dir, "plugins", "plugins.sock")
+
+	server := NewServer(socketPath, plugins)
+	go server.Serve(ctx)
+	require.Eventually(t, func() bool {
+		_, err := os.Stat(socketPath)
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	conn, err := grpc.NewClient("unix://"+socketPath, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return server, pluginv1.NewPluginServiceClient(conn), socketPath
+}
+
+func TestServer(t *testing.T) {
+	plugins := []config.RemotePlugin{
+		{
+			Name:          "vendor-csi",
+			ConditionType: "StorageReady",
+			Resources:     []config.RemotePluginResource{{Type: "journal", Parts: []string{"vendor-csi-node"}}},
+		},
+	}
+	server, client, socketPath := startServer(t, plugins)
+	ctx := context.Background()
+
+	t.Run("SocketPermissions", func(t *testing.T) {
+		info, err := os.Stat(socketPath)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+		info, err = os.Stat(filepath.Dir(socketPath))
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o700), info.Mode().Perm())
+	})
+
+	t.Run("Plugins", func(t *testing.T) {
+		require.Len(t, server.Plugins(), 1)
+		plugin := server.Plugins()[0]
+		assert.Equal(t, "remote/vendor-csi", plugin.Name())
+		require.Len(t, plugin.Monitors(), 1)
+		mon := plugin.Monitors()[0].(*remoteMonitor)
+		assert.Equal(t, "remote-vendor-csi", mon.Name())
+		assert.Equal(t, corev1.NodeConditionType("StorageReady"), mon.ConditionType())
+	})
+
+	t.Run("UnknownPlugin", func(t *testing.T) {
+		_, err := client.Register(ctx, &pluginv1.RegisterRequest{Plugin: "unknown"})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("NotifyBeforeRegistration", func(t *testing.T) {
+		_, err := client.Notify(ctx, &pluginv1.NotifyRequest{
+			Plugin:    "vendor-csi",
+			Condition: &pluginv1.Condition{Reason: "VolumeAttachFailed", Severity: pluginv1.Severity_SEVERITY_WARNING},
+		})
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+	})
+
+	mgr := &mockManager{res: make(chan monitor.Condition, 10), observers: map[string]chan string{}}
+	monCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	require.NoError(t, server.monitors["vendor-csi"].Register(monCtx, mgr))
+
+	t.Run("Register", func(t *testing.T) {
+		resp, err := client.Register(ctx, &pluginv1.RegisterRequest{Plugin: "vendor-csi"})
+		require.NoError(t, err)
+		assert.Equal(t, "StorageReady", resp.GetConditionType())
+		require.Len(t, resp.GetResources(), 1)
+		assert.Equal(t, "journal", resp.GetResources()[0].GetType())
+		assert.Equal(t, []string{"vendor-csi-node"}, resp.GetResources()[0].GetParts())
+	})
+
+	t.Run("Subscribe", func(t *testing.T) {
+		streamCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		stream, err := client.Subscribe(streamCtx, &pluginv1.SubscribeRequest{Plugin: "vendor-csi"})
+		require.NoError(t, err)
+
+		// the stream is only attached once the server handles the call, so
+		// keep producing events until one arrives.
+		received := make(chan *pluginv1.Event, 1)
+		go func() {
+			if event, err := stream.Recv(); err == nil {
+				received <- event
+			}
+		}()
+		var event *pluginv1.Event
+		require.Eventually(t, func() bool {
+			mgr.observers["journal-vendor-csi-node"] <- "attach failed"
+			select {
+			case event = <-received:
+				return true
+			default:
+				return false
+			}
+		}, 5*time.Second, 50*time.Millisecond)
+		assert.Equal(t, "attach failed", event.GetMessage())
+		assert.Equal(t, "journal", event.GetResource().GetType())
+		assert.NotNil(t, event.GetTime())
+	})
+
+	t.Run("Notify", func(t *testing.T) {
+		_, err := client.Notify(ctx, &pluginv1.NotifyRequest{
+			Plugin: "vendor-csi",
+			Condition: &pluginv1.Condition{
+				Reason:         "VolumeAttachFailed",
+				Message:        "volume vol-123 failed to attach",
+				Severity:       pluginv1.Severity_SEVERITY_FATAL,
+				MinOccurrences: 2,
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, monitor.Condition{
+			Reason:         "VolumeAttachFailed",
+			Message:        "volume vol-123 failed to attach",
+			Severity:       monitor.SeverityFatal,
+			MinOccurrences: 2,
+		}, <-mgr.res)
+	})
+
+	t.Run("NotifyInvalidCondition", func(t *testing.T) {
+		_, err := client.Notify(ctx, &pluginv1.NotifyRequest{
+			Plugin:    "vendor-csi",
+			Condition: &pluginv1.Condition{Reason: "VolumeAttachFailed"},
+		})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		_, err = client.Notify(ctx, &pluginv1.NotifyRequest{
+			Plugin:    "vendor-csi",
+			Condition: &pluginv1.Condition{Severity: pluginv1.Severity_SEVERITY_INFO},
+		})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.Empty(t, mgr.res)
+	})
+}
@@ -0,0 +1,225 @@
+package runtime
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/aws/eks-node-monitoring-agent/pkg/config"
+	"github.com/aws/eks-node-monitoring-agent/pkg/osext"
+	"github.com/aws/eks-node-monitoring-agent/pkg/reasons"
+)
+
+const (
+	// podPidsWarningRatio is the fraction of a pod's pids.max above which the
+	// pod is reported.
+	podPidsWarningRatio = 0.9
+	// podCPUThrottledWarningRatio is the fraction of a pod's CFS periods
+	// within one check that were throttled above which the pod is reported.
+	podCPUThrottledWarningRatio = 0.5
+	// podCPUMinPeriods is the number of CFS periods a pod must have run for
+	// within one check before its throttling is considered, so that pods that
+	// barely ran are not reported for a handful of throttled periods.
+	podCPUMinPeriods = 100
+)
+
+// kubepodsCgroupPaths are the cgroups that hold all pods, for the systemd and
+// cgroupfs kubelet cgroup drivers. Only cgroup v2 is supported.
+var kubepodsCgroupPaths = []string{
+	"/sys/fs/cgroup/kubepods.slice",
+	"/sys/fs/cgroup/kubepods",
+}
+
+// podCgroup holds the resource usage of a pod's cgroup.
+type podCgroup struct {
+	uid         string
+	pidsCurrent int64
+	// pidsMax is zero when the number of PIDs is unbounded.
+	pidsMax     int64
+	nrPeriods   int64
+	nrThrottled int64
+}
+
+// podCPUStat is the CFS period counters of a pod from the previous check.
+type podCPUStat struct {
+	nrPeriods   int64
+	nrThrottled int64
+}
+
+// ~~~~ pod cgroups ~~~~
+
+func (m *runtimeMonitor) handlePodCgroups() error {
+	cgroups, err := readPodCgroups()
+	if err != nil {
+		return err
+	}
+	return m.checkPodCgroups(cgroups)
+}
+
+// checkPodCgroups reports the pods that are close to the PIDs limit of their
+// cgroup, or whose CPU was throttled for most of the time since the previous
+// check.
+func (m *runtimeMonitor) checkPodCgroups(cgroups []podCgroup) (merr error) {
+	type offender struct {
+		cgroup  podCgroup
+		message func(pod string) string
+		pids    bool
+	}
+	var offenders []offender
+
+	cpuStats := make(map[string]podCPUStat, len(cgroups))
+	for _, cgroup := range cgroups {
+		cpuStats[cgroup.uid] = podCPUStat{nrPeriods: cgroup.nrPeriods, nrThrottled: cgroup.nrThrottled}
+
+		if cgroup.pidsMax > 0 {
+			percentageUsed := float64(cgroup.pidsCurrent) / float64(cgroup.pidsMax)
+			if percentageUsed >= podPidsWarningRatio {
+				offenders = append(offenders, offender{cgroup: cgroup, pids: true, message: func(pod string) string {
+					return fmt.Sprintf("Pod %s is approaching the max number of PIDs of its cgroup. %d of %d total, %0.1f%%", pod, cgroup.pidsCurrent, cgroup.pidsMax, percentageUsed*100)
+				}})
+			}
+		}
+
+		// the counters of a pod seen for the first time only establish a
+		// baseline.
+		last, ok := m.podCPUStats[cgroup.uid]
+		if !ok {
+			continue
+		}
+		periods := cgroup.nrPeriods - last.nrPeriods
+		throttled := cgroup.nrThrottled - last.nrThrottled
+		if periods < podCPUMinPeriods || throttled < 0 {
+			continue
+		}
+		percentageThrottled := float64(throttled) / float64(periods)
+		if percentageThrottled >= podCPUThrottledWarningRatio {
+			offenders = append(offenders, offender{cgroup: cgroup, message: func(pod string) string {
+				return fmt.Sprintf("Pod %s was CPU throttled in %d of %d scheduling periods, %0.1f%%", pod, throttled, periods, percentageThrottled*100)
+			}})
+		}
+	}
+	// pods that no longer exist are dropped from the state.
+	m.podCPUStats = cpuStats
+
+	if len(offenders) == 0 {
+		return nil
+	}
+	pods, err := m.podLister.ByUID(context.Background())
+	if err != nil {
+		// the pods are still reported by UID when the CRI is unavailable.
+		merr = errors.Join(merr, err)
+	}
+	for _, offender := range offenders {
+		pod := fmt.Sprintf("with UID %q", offender.cgroup.uid)
+		if metadata, ok := pods[offender.cgroup.uid]; ok {
+			pod = fmt.Sprintf("%q", metadata)
+		}
+		builder := reasons.PodCPUThrottled.Builder()
+		if offender.pids {
+			builder = reasons.ApproachingPodPidMax.Builder()
+		}
+		merr = errors.Join(merr, m.manager.Notify(context.Background(), builder.Message(offender.message(pod)).Build()))
+	}
+	return merr
+}
+
+// readPodCgroups walks the kubepods cgroup hierarchy and reads the resource
+// usage of every pod's cgroup.
+func readPodCgroups() ([]podCgroup, error) {
+	var cgroups []podCgroup
+	for _, root := range kubepodsCgroupPaths {
+		rootPath := config.ToHostPath(root)
+		err := filepath.WalkDir(rootPath, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				if errors.Is(err, fs.ErrNotExist) {
+					if path == rootPath {
+						return fs.SkipAll
+					}
+					// a cgroup was removed after its parent was listed.
+					return fs.SkipDir
+				}
+				return err
+			}
+			if !entry.IsDir() {
+				return nil
+			}
+			uid, _, ok := osext.PodFromCgroup(entry.Name())
+			if !ok {
+				return nil
+			}
+			cgroup, err := readPodCgroup(path, uid)
+			if err != nil {
+				// pods are deleted all the time, and their cgroup goes away
+				// while it is read.
+				if errors.Is(err, fs.ErrNotExist) {
+					return fs.SkipDir
+				}
+				return err
+			}
+			cgroups = append(cgroups, cgroup)
+			// the container cgroups below the pod are not needed.
+			return fs.SkipDir
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(cgroups) > 0 {
+			break
+		}
+	}
+	return cgroups, nil
+}
+
+func readPodCgroup(path, uid string) (podCgroup, error) {
+	cgroup := podCgroup{uid: uid}
+	var err error
+	if cgroup.pidsCurrent, err = readCgroupInt(filepath.Join(path, "pids.current")); err != nil {
+		return cgroup, err
+	}
+	// pids.max holds "max" when the number of PIDs is unbounded.
+	if cgroup.pidsMax, err = readCgroupInt(filepath.Join(path, "pids.max")); err != nil && !errors.Is(err, strconv.ErrSyntax) {
+		return cgroup, err
+	}
+	data, err := os.ReadFile(filepath.Join(path, "cpu.stat"))
+	if err != nil {
+		return cgroup, err
+	}
+	// cpu.stat is formatted like:
+	// ---
+	// usage_usec 1234
+	// nr_periods 100
+	// nr_throttled 10
+	// throttled_usec 5678
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), " ")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "nr_periods":
+			cgroup.nrPeriods, err = strconv.ParseInt(value, 10, 64)
+		case "nr_throttled":
+			cgroup.nrThrottled, err = strconv.ParseInt(value, 10, 64)
+		}
+		if err != nil {
+			return cgroup, fmt.Errorf("parsing %s: %w", filepath.Join(path, "cpu.stat"), err)
+		}
+	}
+	return cgroup, nil
+}
+
+func readCgroupInt(path string) (int64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(string(bytes.TrimSpace(data)), 10, 64)
+}
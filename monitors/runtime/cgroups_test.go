@@ -0,0 +1,162 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	cri "k8s.io/cri-api/pkg/apis"
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
+
+	"github.com/aws/eks-node-monitoring-agent/api/monitor"
+	"github.com/aws/eks-node-monitoring-agent/pkg/config"
+	"github.com/aws/eks-node-monitoring-agent/pkg/util/pods"
+)
+
+// fakeRuntimeService is a stub cri.RuntimeService that returns canned pod
+// sandboxes. Other interface methods inherit the embedded nil and panic if
+// invoked, surfacing unexpected dependencies.
+type fakeRuntimeService struct {
+	cri.RuntimeService
+
+	sandboxes []*runtimeapi.PodSandbox
+	err       error
+}
+
+func (f *fakeRuntimeService) ListPodSandbox(context.Context, *runtimeapi.PodSandboxFilter) ([]*runtimeapi.PodSandbox, error) {
+	return f.sandboxes, f.err
+}
+
+const (
+	podUID      = "8d2b6f4e-3c1a-4e5f-9a7b-1c2d3e4f5a6b"
+	otherPodUID = "0f1e2d3c-4b5a-6978-8796-a5b4c3d2e1f0"
+)
+
+func writePodCgroup(t *testing.T, dir string, pidsCurrent int, pidsMax string, nrPeriods, nrThrottled int) {
+	t.Helper()
+	files := map[string]string{
+		"pids.current": fmt.Sprintf("%d\n", pidsCurrent),
+		"pids.max":     pidsMax + "\n",
+		"cpu.stat":     fmt.Sprintf("usage_usec 123456\nuser_usec 100000\nsystem_usec 23456\nnr_periods %d\nnr_throttled %d\nthrottled_usec 98765\n", nrPeriods, nrThrottled),
+	}
+	for name, content := range files {
+		assert.NoError(t, os.MkdirAll(dir, 0755))
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+}
+
+func TestReadPodCgroups(t *testing.T) {
+	t.Run("SystemdDriver", func(t *testing.T) {
+		root := t.TempDir()
+		t.Setenv(config.HOST_ROOT_ENV, root)
+		kubepods := filepath.Join(root, "sys/fs/cgroup/kubepods.slice")
+		writePodCgroup(t, filepath.Join(kubepods, "kubepods-burstable.slice", "kubepods-burstable-pod8d2b6f4e_3c1a_4e5f_9a7b_1c2d3e4f5a6b.slice"), 10, "1024", 300, 30)
+		writePodCgroup(t, filepath.Join(kubepods, "kubepods-pod0f1e2d3c_4b5a_6978_8796_a5b4c3d2e1f0.slice"), 5, "max", 0, 0)
+		// container cgroups below the pod are not read.
+		assert.NoError(t, os.MkdirAll(filepath.Join(kubepods, "kubepods-pod0f1e2d3c_4b5a_6978_8796_a5b4c3d2e1f0.slice", "cri-containerd-abc.scope"), 0755))
+
+		cgroups, err := readPodCgroups()
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []podCgroup{
+			{uid: podUID, pidsCurrent: 10, pidsMax: 1024, nrPeriods: 300, nrThrottled: 30},
+			{uid: otherPodUID, pidsCurrent: 5},
+		}, cgroups)
+	})
+
+	t.Run("CgroupfsDriver", func(t *testing.T) {
+		root := t.TempDir()
+		t.Setenv(config.HOST_ROOT_ENV, root)
+		writePodCgroup(t, filepath.Join(root, "sys/fs/cgroup/kubepods/besteffort", "pod"+podUID), 3, "100", 10, 1)
+
+		cgroups, err := readPodCgroups()
+		assert.NoError(t, err)
+		assert.Equal(t, []podCgroup{{uid: podUID, pidsCurrent: 3, pidsMax: 100, nrPeriods: 10, nrThrottled: 1}}, cgroups)
+	})
+
+	t.Run("PodDeletedWhileRead", func(t *testing.T) {
+		root := t.TempDir()
+		t.Setenv(config.HOST_ROOT_ENV, root)
+		kubepods := filepath.Join(root, "sys/fs/cgroup/kubepods.slice")
+		deleted := filepath.Join(kubepods, "kubepods-pod0f1e2d3c_4b5a_6978_8796_a5b4c3d2e1f0.slice")
+		writePodCgroup(t, deleted, 5, "max", 0, 0)
+		assert.NoError(t, os.Remove(filepath.Join(deleted, "cpu.stat")))
+		writePodCgroup(t, filepath.Join(kubepods, "kubepods-pod8d2b6f4e_3c1a_4e5f_9a7b_1c2d3e4f5a6b.slice"), 10, "1024", 300, 30)
+
+		cgroups, err := readPodCgroups()
+		assert.NoError(t, err)
+		assert.Equal(t, []podCgroup{{uid: podUID, pidsCurrent: 10, pidsMax: 1024, nrPeriods: 300, nrThrottled: 30}}, cgroups)
+	})
+
+	t.Run("NoKubepods", func(t *testing.T) {
+		t.Setenv(config.HOST_ROOT_ENV, t.TempDir())
+		cgroups, err := readPodCgroups()
+		assert.NoError(t, err)
+		assert.Empty(t, cgroups)
+	})
+}
+
+func TestCheckPodCgroups(t *testing.T) {
+	newMonitor := func(runtimeService cri.RuntimeService) (*runtimeMonitor, *mockManager) {
+		mon := makeRuntimeMonitor()
+		mon.podLister = pods.Lister{RuntimeService: runtimeService}
+		mockManager := &mockManager{res: make(chan monitor.Condition, 5)}
+		mon.manager = mockManager
+		return mon, mockManager
+	}
+	runtimeService := &fakeRuntimeService{sandboxes: []*runtimeapi.PodSandbox{
+		{Metadata: &runtimeapi.PodSandboxMetadata{Uid: podUID, Namespace: "default", Name: "web-0"}},
+	}}
+
+	t.Run("Noop", func(t *testing.T) {
+		mon, mockManager := newMonitor(runtimeService)
+		assert.NoError(t, mon.checkPodCgroups([]podCgroup{{uid: podUID, pidsCurrent: 10, pidsMax: 1024, nrPeriods: 1000, nrThrottled: 900}}))
+		// few periods are not enough to report throttling.
+		assert.NoError(t, mon.checkPodCgroups([]podCgroup{{uid: podUID, pidsCurrent: 10, pidsMax: 1024, nrPeriods: 1050, nrThrottled: 950}}))
+		// unbounded PIDs are never reported.
+		assert.NoError(t, mon.checkPodCgroups([]podCgroup{{uid: podUID, pidsCurrent: 1000000, nrPeriods: 2050, nrThrottled: 1000}}))
+		assert.Equal(t, 0, len(mockManager.res))
+	})
+
+	t.Run("ApproachingPodPidMax", func(t *testing.T) {
+		mon, mockManager := newMonitor(runtimeService)
+		assert.NoError(t, mon.checkPodCgroups([]podCgroup{{uid: podUID, pidsCurrent: 950, pidsMax: 1024}}))
+		monitorResult := <-mockManager.res
+		assert.Equal(t, monitor.SeverityWarning, monitorResult.Severity)
+		assert.Equal(t, "ApproachingPodPidMax", monitorResult.Reason)
+		assert.Contains(t, monitorResult.Message, `"default/web-0"`)
+		assert.Contains(t, monitorResult.Message, "950 of 1024 total")
+	})
+
+	t.Run("PodCPUThrottled", func(t *testing.T) {
+		mon, mockManager := newMonitor(runtimeService)
+		assert.NoError(t, mon.checkPodCgroups([]podCgroup{
+			{uid: podUID, nrPeriods: 1000, nrThrottled: 100},
+			{uid: otherPodUID, nrPeriods: 1000, nrThrottled: 100},
+		}))
+		assert.Equal(t, 0, len(mockManager.res))
+
+		assert.NoError(t, mon.checkPodCgroups([]podCgroup{
+			{uid: podUID, nrPeriods: 4000, nrThrottled: 2500},
+			{uid: otherPodUID, nrPeriods: 4000, nrThrottled: 200},
+		}))
+		monitorResult := <-mockManager.res
+		assert.Equal(t, monitor.SeverityWarning, monitorResult.Severity)
+		assert.Equal(t, "PodCPUThrottled", monitorResult.Reason)
+		assert.Contains(t, monitorResult.Message, `"default/web-0"`)
+		assert.Contains(t, monitorResult.Message, "2400 of 3000 scheduling periods, 80.0%")
+		assert.Equal(t, 0, len(mockManager.res))
+	})
+
+	t.Run("RuntimeUnavailable", func(t *testing.T) {
+		mon, mockManager := newMonitor(&fakeRuntimeService{err: errors.New("connection refused")})
+		err := mon.checkPodCgroups([]podCgroup{{uid: otherPodUID, pidsCurrent: 100, pidsMax: 100}})
+		assert.ErrorContains(t, err, "connection refused")
+		monitorResult := <-mockManager.res
+		assert.Equal(t, "ApproachingPodPidMax", monitorResult.Reason)
+		assert.Contains(t, monitorResult.Message, fmt.Sprintf("with UID %q", otherPodUID))
+	})
+}
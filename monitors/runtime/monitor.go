@@ -17,6 +17,7 @@ import (
 	"github.com/aws/eks-node-monitoring-agent/pkg/osext"
 	"github.com/aws/eks-node-monitoring-agent/pkg/reasons"
 	"github.com/aws/eks-node-monitoring-agent/pkg/util"
+	"github.com/aws/eks-node-monitoring-agent/pkg/util/pods"
 	"github.com/coreos/go-systemd/v22/dbus"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
@@ -32,6 +33,7 @@ func NewRuntimeMonitor(node *corev1.Node, kubeClient client.Client) *runtimeMoni
 		node:             node,
 		kubeClient:       kubeClient,
 		unitRestartCount: map[string]uint32{},
+		podCPUStats:      map[string]podCPUStat{},
 	}
 }
 
@@ -43,6 +45,8 @@ type runtimeMonitor struct {
 	node             *corev1.Node
 	kubeClient       client.Client
 	unitRestartCount map[string]uint32
+	podCPUStats      map[string]podCPUStat // pod UID -> CFS period counters
+	podLister        pods.Lister
 
 	manager monitor.Manager
 }
@@ -82,6 +86,9 @@ func (m *runtimeMonitor) Register(ctx context.Context, mgr monitor.Manager) erro
 	containerdHandler := util.NewChannelHandler(func(time.Time) error { return m.handleContainerd() }, util.TimeTickWithJitterContext(ctx, containerdDeprecationInterval))
 	go containerdHandler.Start(ctx)
 
+	podCgroupsHandler := util.NewChannelHandler(func(time.Time) error { return m.handlePodCgroups() }, util.TimeTickWithJitterContext(ctx, 5*time.Minute))
+	go podCgroupsHandler.Start(ctx)
+
 	if !slices.Contains(rtCtx.Tags(), config.Bottlerocket) {
 		handler := util.NewChannelHandler(func(time.Time) error { return m.handleSystemdServices() }, util.TimeTickWithJitterContext(ctx, 5*time.Minute))
 		go handler.Start(ctx)
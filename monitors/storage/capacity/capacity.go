@@ -0,0 +1,288 @@
+package capacity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/sys/unix"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/yaml"
+
+	"github.com/aws/eks-node-monitoring-agent/api/monitor"
+	"github.com/aws/eks-node-monitoring-agent/pkg/config"
+	"github.com/aws/eks-node-monitoring-agent/pkg/pathlib"
+	"github.com/aws/eks-node-monitoring-agent/pkg/reasons"
+)
+
+// eviction signals of the kubelet for filesystems.
+// see: https://kubernetes.io/docs/concepts/scheduling-eviction/node-pressure-eviction/#eviction-signals
+const (
+	NodeFSAvailable   = "nodefs.available"
+	NodeFSInodesFree  = "nodefs.inodesFree"
+	ImageFSAvailable  = "imagefs.available"
+	ImageFSInodesFree = "imagefs.inodesFree"
+)
+
+// defaultEvictionHard are the hard eviction thresholds the kubelet uses on
+// Linux when its config does not set evictionHard, or for the signals that it
+// does not set when mergeDefaultEvictionSettings is true.
+var defaultEvictionHard = map[string]string{
+	NodeFSAvailable:   "10%",
+	NodeFSInodesFree:  "5%",
+	ImageFSAvailable:  "15%",
+	ImageFSInodesFree: "5%",
+}
+
+// approachingMargin is how close to an eviction threshold a filesystem is
+// reported, as a fraction of the filesystem's capacity.
+const approachingMargin = 0.05
+
+// filesystem is a directory whose filesystem the kubelet evicts pods for.
+type filesystem struct {
+	path string
+	// imageFS is true for the directory of the container runtime, which the
+	// kubelet treats as the imagefs when it is on its own filesystem.
+	imageFS bool
+}
+
+var filesystems = []filesystem{
+	{path: "/var/lib/kubelet"},
+	{path: "/var/lib/containerd", imageFS: true},
+	{path: "/var/log"},
+}
+
+// kubeletConfig holds the fields of the kubelet config used to check the
+// capacity of filesystems.
+type kubeletConfig struct {
+	EvictionHard                 map[string]string `json:"evictionHard"`
+	EvictionSoft                 map[string]string `json:"evictionSoft"`
+	MergeDefaultEvictionSettings *bool             `json:"mergeDefaultEvictionSettings"`
+}
+
+func NewCapacitySystem() *capacitySystem {
+	return &capacitySystem{
+		statfs: unix.Statfs,
+	}
+}
+
+type capacitySystem struct {
+	statfs func(path string, buf *unix.Statfs_t) error
+}
+
+// Capacity reports the filesystems used by the kubelet and the container
+// runtime whose free space or inodes are approaching the thresholds at which
+// the kubelet starts evicting pods.
+func (s *capacitySystem) Capacity(ctx context.Context) ([]monitor.Condition, error) {
+	logger := log.FromContext(ctx)
+
+	thresholds, err := evictionThresholds()
+	if err != nil {
+		return nil, err
+	}
+
+	type usage struct {
+		paths   []string
+		stat    unix.Statfs_t
+		nodeFS  bool
+		imageFS bool
+	}
+	// the directories commonly share a filesystem, which is only checked once.
+	var usages []*usage
+	for _, fs := range filesystems {
+		var stat unix.Statfs_t
+		if err := s.statfs(config.ToHostPath(fs.path), &stat); err != nil {
+			if errors.Is(err, unix.ENOENT) {
+				continue
+			}
+			return nil, fmt.Errorf("statfs %s: %w", fs.path, err)
+		}
+		index := slices.IndexFunc(usages, func(u *usage) bool { return u.stat.Fsid == stat.Fsid })
+		if index < 0 {
+			usages = append(usages, &usage{stat: stat})
+			index = len(usages) - 1
+		}
+		usages[index].paths = append(usages[index].paths, fs.path)
+		usages[index].nodeFS = usages[index].nodeFS || !fs.imageFS
+		usages[index].imageFS = usages[index].imageFS || fs.imageFS
+	}
+
+	var conditions []monitor.Condition
+	for _, u := range usages {
+		path := strings.Join(u.paths, ", ")
+		blockSize := float64(u.stat.Bsize)
+		capacity := float64(u.stat.Blocks) * blockSize
+		available := float64(u.stat.Bavail) * blockSize
+		inodes := float64(u.stat.Files)
+		inodesFree := float64(u.stat.Ffree)
+		logger.V(1).Info("checked filesystem capacity", "path", path, "capacity", capacity, "available", available, "inodes", inodes, "inodesFree", inodesFree)
+
+		var signals []string
+		if u.nodeFS {
+			signals = append(signals, NodeFSAvailable, NodeFSInodesFree)
+		}
+		if u.imageFS {
+			signals = append(signals, ImageFSAvailable, ImageFSInodesFree)
+		}
+		for _, signal := range signals {
+			total, free := capacity, available
+			if signal == NodeFSInodesFree || signal == ImageFSInodesFree {
+				total, free = inodes, inodesFree
+			}
+			// some filesystems, such as btrfs, do not report a number of inodes.
+			if total == 0 {
+				continue
+			}
+			// the kubelet starts evicting at the soft threshold when it is
+			// larger than the hard one.
+			var threshold float64
+			for _, t := range thresholds[signal] {
+				threshold = max(threshold, t.value(total))
+			}
+			if threshold == 0 {
+				continue
+			}
+			if condition, ok := checkSignal(signal, path, threshold, total, free); ok {
+				conditions = append(conditions, condition)
+			}
+		}
+	}
+	return conditions, nil
+}
+
+// checkSignal reports a filesystem whose free amount of a resource is within
+// approachingMargin of the eviction threshold of signal.
+func checkSignal(signal, path string, threshold, total, free float64) (monitor.Condition, bool) {
+	if free >= threshold+approachingMargin*total {
+		return monitor.Condition{}, false
+	}
+	var reason reasons.ReasonMeta
+	switch signal {
+	case NodeFSAvailable:
+		reason = reasons.ApproachingNodeFSEviction
+	case ImageFSAvailable:
+		reason = reasons.ApproachingImageFSEviction
+	default:
+		reason = reasons.InodesNearlyExhausted
+	}
+	resourceName := "space"
+	if signal == NodeFSInodesFree || signal == ImageFSInodesFree {
+		resourceName = "inodes"
+	}
+	return reason.
+		Builder().
+		Message(fmt.Sprintf("The filesystem of %s has %0.1f%% of its %s free, approaching the %s eviction threshold of %0.1f%%",
+			path, free/total*100, resourceName, signal, threshold/total*100)).
+		Build(), true
+}
+
+// threshold is an eviction threshold, either as a quantity or as a percentage
+// of the capacity.
+type threshold struct {
+	quantity   *resource.Quantity
+	percentage float64
+}
+
+// value returns the threshold for a resource with the given capacity.
+func (t threshold) value(capacity float64) float64 {
+	if t.quantity != nil {
+		return float64(t.quantity.Value())
+	}
+	return t.percentage / 100 * capacity
+}
+
+func parseThreshold(value string) (threshold, error) {
+	if percentage, ok := strings.CutSuffix(value, "%"); ok {
+		parsed, err := strconv.ParseFloat(percentage, 64)
+		if err != nil {
+			return threshold{}, err
+		}
+		return threshold{percentage: parsed}, nil
+	}
+	quantity, err := resource.ParseQuantity(value)
+	if err != nil {
+		return threshold{}, err
+	}
+	return threshold{quantity: &quantity}, nil
+}
+
+// evictionThresholds returns the hard and soft eviction thresholds of the
+// kubelet by signal.
+func evictionThresholds() (map[string][]threshold, error) {
+	kubeletConfig, err := readKubeletConfig()
+	if err != nil {
+		return nil, err
+	}
+	// the kubelet disables the signals that a configured evictionHard leaves
+	// out, unless it is told to merge the defaults.
+	evictionHard := kubeletConfig.EvictionHard
+	if evictionHard == nil || (kubeletConfig.MergeDefaultEvictionSettings != nil && *kubeletConfig.MergeDefaultEvictionSettings) {
+		evictionHard = mergeSignals(mergeSignals(nil, defaultEvictionHard), kubeletConfig.EvictionHard)
+	}
+
+	thresholds := map[string][]threshold{}
+	for _, eviction := range []map[string]string{evictionHard, kubeletConfig.EvictionSoft} {
+		for signal, value := range eviction {
+			if _, ok := defaultEvictionHard[signal]; !ok {
+				continue
+			}
+			parsed, err := parseThreshold(value)
+			if err != nil {
+				return nil, fmt.Errorf("parsing eviction threshold %s=%q: %w", signal, value, err)
+			}
+			thresholds[signal] = append(thresholds[signal], parsed)
+		}
+	}
+	return thresholds, nil
+}
+
+// readKubeletConfig reads the kubelet config and the drop-in configs that
+// override it, in lexical order. Signals set by a drop-in override the same
+// signals of the config.
+func readKubeletConfig() (*kubeletConfig, error) {
+	merged := &kubeletConfig{}
+	paths, err := pathlib.ResolveKubeletConfigs(config.HostRoot())
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+		var kubeletConfig kubeletConfig
+		if err := yaml.Unmarshal(data, &kubeletConfig); err != nil {
+			return nil, fmt.Errorf("parsing kubelet config %s: %w", path, err)
+		}
+		merged.EvictionHard = mergeSignals(merged.EvictionHard, kubeletConfig.EvictionHard)
+		merged.EvictionSoft = mergeSignals(merged.EvictionSoft, kubeletConfig.EvictionSoft)
+		if kubeletConfig.MergeDefaultEvictionSettings != nil {
+			merged.MergeDefaultEvictionSettings = kubeletConfig.MergeDefaultEvictionSettings
+		}
+	}
+	return merged, nil
+}
+
+// mergeSignals sets the signals of override on base. An empty override still
+// makes a nil base non-nil, since an empty evictionHard is not an unset one.
+func mergeSignals(base, override map[string]string) map[string]string {
+	if override == nil {
+		return base
+	}
+	if base == nil {
+		base = map[string]string{}
+	}
+	for signal, value := range override {
+		base[signal] = value
+	}
+	return base
+}
@@ -0,0 +1,169 @@
+package capacity
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/sys/unix"
+
+	"github.com/aws/eks-node-monitoring-agent/api/monitor"
+	"github.com/aws/eks-node-monitoring-agent/pkg/config"
+)
+
+// fakeStatfs returns the stats of fake filesystems by host path.
+func fakeStatfs(root string, stats map[string]unix.Statfs_t) func(string, *unix.Statfs_t) error {
+	return func(path string, buf *unix.Statfs_t) error {
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		stat, ok := stats["/"+rel]
+		if !ok {
+			return unix.ENOENT
+		}
+		*buf = stat
+		return nil
+	}
+}
+
+// makeStat returns the stat of a 100GiB filesystem with a million inodes.
+func makeStat(fsid int32, availablePercent, inodesFreePercent uint64) unix.Statfs_t {
+	return unix.Statfs_t{
+		Fsid:   unix.Fsid{Val: [2]int32{fsid, 0}},
+		Bsize:  4096,
+		Blocks: 100 * 1024 * 256,
+		Bavail: availablePercent * 1024 * 256,
+		Files:  1000000,
+		Ffree:  inodesFreePercent * 10000,
+	}
+}
+
+func setupRoot(t *testing.T, kubeletConfig string, dropIns map[string]string) string {
+	root := t.TempDir()
+	t.Setenv(config.HOST_ROOT_ENV, root)
+	configDir := filepath.Join(root, "etc/kubernetes/kubelet")
+	assert.NoError(t, os.MkdirAll(filepath.Join(configDir, "config.json.d"), 0755))
+	if kubeletConfig != "" {
+		assert.NoError(t, os.WriteFile(filepath.Join(configDir, "config.json"), []byte(kubeletConfig), 0644))
+	}
+	for name, content := range dropIns {
+		assert.NoError(t, os.WriteFile(filepath.Join(configDir, "config.json.d", name), []byte(content), 0644))
+	}
+	return root
+}
+
+func TestCapacity(t *testing.T) {
+	t.Run("Healthy", func(t *testing.T) {
+		root := setupRoot(t, "", nil)
+		system := NewCapacitySystem()
+		system.statfs = fakeStatfs(root, map[string]unix.Statfs_t{
+			"/var/lib/kubelet":    makeStat(1, 50, 50),
+			"/var/lib/containerd": makeStat(1, 50, 50),
+			"/var/log":            makeStat(1, 50, 50),
+		})
+		conditions, err := system.Capacity(context.TODO())
+		assert.NoError(t, err)
+		assert.Empty(t, conditions)
+	})
+
+	t.Run("DefaultThresholds", func(t *testing.T) {
+		root := setupRoot(t, "", nil)
+		system := NewCapacitySystem()
+		system.statfs = fakeStatfs(root, map[string]unix.Statfs_t{
+			// 14% is within the margin of the default 10% nodefs threshold.
+			"/var/lib/kubelet": makeStat(1, 14, 50),
+			"/var/log":         makeStat(1, 14, 50),
+			// 19% is within the margin of the default 15% imagefs threshold.
+			"/var/lib/containerd": makeStat(2, 19, 9),
+		})
+		conditions, err := system.Capacity(context.TODO())
+		assert.NoError(t, err)
+		if assert.Len(t, conditions, 3) {
+			assert.Equal(t, monitor.SeverityWarning, conditions[0].Severity)
+			assert.Equal(t, "ApproachingNodeFSEviction", conditions[0].Reason)
+			assert.Equal(t, "The filesystem of /var/lib/kubelet, /var/log has 14.0% of its space free, approaching the nodefs.available eviction threshold of 10.0%", conditions[0].Message)
+			assert.Equal(t, "ApproachingImageFSEviction", conditions[1].Reason)
+			assert.Contains(t, conditions[1].Message, "/var/lib/containerd has 19.0% of its space free")
+			assert.Equal(t, "InodesNearlyExhausted", conditions[2].Reason)
+			assert.Contains(t, conditions[2].Message, "9.0% of its inodes free, approaching the imagefs.inodesFree eviction threshold of 5.0%")
+		}
+	})
+
+	t.Run("ConfiguredThresholds", func(t *testing.T) {
+		root := setupRoot(t,
+			`{"kind": "KubeletConfiguration", "evictionHard": {"memory.available": "100Mi", "nodefs.available": "5%"}, "evictionSoft": {"imagefs.available": "30%"}}`,
+			map[string]string{
+				"00-nodefs.conf": "evictionHard:\n  nodefs.available: 20Gi\n",
+			},
+		)
+		system := NewCapacitySystem()
+		system.statfs = fakeStatfs(root, map[string]unix.Statfs_t{
+			// 24% is within the margin of the 20Gi threshold of the drop-in.
+			"/var/lib/kubelet": makeStat(1, 24, 50),
+			// 33% is within the margin of the 30% soft threshold.
+			"/var/lib/containerd": makeStat(2, 33, 50),
+		})
+		conditions, err := system.Capacity(context.TODO())
+		assert.NoError(t, err)
+		if assert.Len(t, conditions, 2) {
+			assert.Equal(t, "ApproachingNodeFSEviction", conditions[0].Reason)
+			assert.Contains(t, conditions[0].Message, "eviction threshold of 20.0%")
+			assert.Equal(t, "ApproachingImageFSEviction", conditions[1].Reason)
+			assert.Contains(t, conditions[1].Message, "eviction threshold of 30.0%")
+		}
+	})
+
+	t.Run("PartialEvictionHard", func(t *testing.T) {
+		// like the EKS AMIs, evictionHard is set without the imagefs signals,
+		// which the kubelet then does not enforce.
+		const kubeletConfig = `{"kind": "KubeletConfiguration", "evictionHard": {"memory.available": "100Mi", "nodefs.available": "10%", "nodefs.inodesFree": "5%"}}`
+		stats := map[string]unix.Statfs_t{
+			"/var/lib/kubelet":    makeStat(1, 50, 50),
+			"/var/lib/containerd": makeStat(2, 17, 3),
+		}
+		root := setupRoot(t, kubeletConfig, nil)
+		system := NewCapacitySystem()
+		system.statfs = fakeStatfs(root, stats)
+		conditions, err := system.Capacity(context.TODO())
+		assert.NoError(t, err)
+		assert.Empty(t, conditions)
+
+		// unless the kubelet merges the defaults in.
+		root = setupRoot(t, kubeletConfig, map[string]string{
+			"00-merge.conf": "mergeDefaultEvictionSettings: true\n",
+		})
+		system.statfs = fakeStatfs(root, stats)
+		conditions, err = system.Capacity(context.TODO())
+		assert.NoError(t, err)
+		if assert.Len(t, conditions, 2) {
+			assert.Equal(t, "ApproachingImageFSEviction", conditions[0].Reason)
+			assert.Contains(t, conditions[0].Message, "eviction threshold of 15.0%")
+			assert.Equal(t, "InodesNearlyExhausted", conditions[1].Reason)
+			assert.Contains(t, conditions[1].Message, "imagefs.inodesFree eviction threshold of 5.0%")
+		}
+	})
+
+	t.Run("SharedImageFS", func(t *testing.T) {
+		root := setupRoot(t, "", nil)
+		system := NewCapacitySystem()
+		system.statfs = fakeStatfs(root, map[string]unix.Statfs_t{
+			"/var/lib/kubelet":    makeStat(1, 17, 50),
+			"/var/lib/containerd": makeStat(1, 17, 50),
+		})
+		conditions, err := system.Capacity(context.TODO())
+		assert.NoError(t, err)
+		if assert.Len(t, conditions, 1) {
+			assert.Equal(t, "ApproachingImageFSEviction", conditions[0].Reason)
+			assert.Contains(t, conditions[0].Message, "/var/lib/kubelet, /var/lib/containerd")
+		}
+	})
+
+	t.Run("InvalidThreshold", func(t *testing.T) {
+		setupRoot(t, `{"evictionHard": {"nodefs.available": "lots"}}`, nil)
+		_, err := NewCapacitySystem().Capacity(context.TODO())
+		assert.ErrorContains(t, err, `nodefs.available="lots"`)
+	})
+}
@@ -1,6 +1,8 @@
 package ebs
 
 import (
+	"time"
+
 	"github.com/aws/eks-node-monitoring-agent/monitors/storage/nvme"
 	"github.com/aws/eks-node-monitoring-agent/pkg/config"
 )
@@ -12,6 +14,8 @@ func NewEBSSystem() *ebsNVMeSystem {
 		lastExceededVolumeThroughput:   map[string]uint64{},
 		lastExceededInstanceIops:       map[string]uint64{},
 		lastExceededInstanceThroughput: map[string]uint64{},
+		lastLatency:                    map[string]*volumeLatency{},
+		latencyThreshold:               config.DefaultEBSLatencyThreshold,
 		deviceControllerFn:             func(device *ebsnvme.Device) DeviceController { return &ebsNVMeDeviceController{device} },
 	}
 }
@@ -24,5 +28,8 @@ type ebsNVMeSystem struct {
 	lastExceededInstanceIops       map[string]uint64
 	lastExceededInstanceThroughput map[string]uint64
 
+	lastLatency      map[string]*volumeLatency
+	latencyThreshold time.Duration
+
 	deviceControllerFn func(*ebsnvme.Device) DeviceController
 }
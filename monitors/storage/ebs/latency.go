@@ -0,0 +1,134 @@
+package ebs
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aws/eks-node-monitoring-agent/api/monitor"
+	"github.com/aws/eks-node-monitoring-agent/monitors/storage/nvme"
+	"github.com/aws/eks-node-monitoring-agent/pkg/reasons"
+)
+
+const (
+	// latencyDegradedIntervals is the number of consecutive throttling periods
+	// that the p99 latency of a volume must exceed the threshold for before it
+	// is reported.
+	latencyDegradedIntervals = 2
+	// minLatencySampleOps is the number of operations a volume must complete
+	// within a period for its percentiles to be considered, so that mostly idle
+	// volumes are not reported for a handful of slow operations.
+	minLatencySampleOps = 1000
+)
+
+// LatencyPercentiles are the latency percentiles of the operations that a
+// volume completed within an interval, along with the number of operations.
+// Percentiles are the upper bound of the histogram bin that they fall in.
+type LatencyPercentiles struct {
+	Ops  uint64
+	P50  time.Duration
+	P99  time.Duration
+	P999 time.Duration
+}
+
+// HistogramPercentiles returns the latency percentiles of the operations
+// recorded in current since previous. A nil previous histogram is treated as
+// empty. The bin counters are free running, so the differences are computed
+// with wrapping arithmetic.
+func HistogramPercentiles(current, previous *ebsnvme.EbsNvmeHistogram) LatencyPercentiles {
+	numBins := min(int(current.NumBins), len(current.Bins))
+	// the bin boundaries are only comparable when the layout did not change.
+	if previous != nil && previous.NumBins != current.NumBins {
+		previous = nil
+	}
+	counts := make([]uint64, numBins)
+	var percentiles LatencyPercentiles
+	for i := range numBins {
+		count := current.Bins[i].Count
+		if previous != nil {
+			count -= previous.Bins[i].Count
+		}
+		counts[i] = uint64(count)
+		percentiles.Ops += uint64(count)
+	}
+	if percentiles.Ops == 0 {
+		return percentiles
+	}
+	percentile := func(p float64) time.Duration {
+		target := p * float64(percentiles.Ops)
+		var cumulative uint64
+		for i, count := range counts {
+			cumulative += count
+			if float64(cumulative) >= target {
+				return time.Duration(current.Bins[i].Upper) * time.Microsecond
+			}
+		}
+		return time.Duration(current.Bins[numBins-1].Upper) * time.Microsecond
+	}
+	percentiles.P50 = percentile(0.5)
+	percentiles.P99 = percentile(0.99)
+	percentiles.P999 = percentile(0.999)
+	return percentiles
+}
+
+// volumeLatency holds the previous histograms of a volume and the number of
+// consecutive periods its latency was degraded.
+type volumeLatency struct {
+	read            ebsnvme.EbsNvmeHistogram
+	write           ebsnvme.EbsNvmeHistogram
+	degradedPeriods int
+}
+
+// SetLatencyThreshold sets the p99 latency above which a volume is reported.
+func (s *ebsNVMeSystem) SetLatencyThreshold(threshold time.Duration) {
+	s.latencyThreshold = threshold
+}
+
+func (s *ebsNVMeSystem) checkVolumeLatency(stats *ebsnvme.NvmeGetAmznStatsLogpage, volumeID string, blockDeviceName string) []monitor.Condition {
+	last, ok := s.lastLatency[volumeID]
+	s.lastLatency[volumeID] = &volumeLatency{
+		read:  stats.ReadIoLatencyHistogram,
+		write: stats.WriteIoLatencyHistogram,
+	}
+	// the first histograms of a volume only establish a baseline.
+	if !ok {
+		return nil
+	}
+
+	var degraded []string
+	for _, direction := range []struct {
+		name              string
+		current, previous *ebsnvme.EbsNvmeHistogram
+	}{
+		{"read", &stats.ReadIoLatencyHistogram, &last.read},
+		{"write", &stats.WriteIoLatencyHistogram, &last.write},
+	} {
+		percentiles := HistogramPercentiles(direction.current, direction.previous)
+		if percentiles.Ops < minLatencySampleOps || percentiles.P99 <= s.latencyThreshold {
+			continue
+		}
+		degraded = append(degraded, fmt.Sprintf("%s p99 latency %s (p50 %s, p99.9 %s, %d ops)",
+			direction.name, percentiles.P99, percentiles.P50, percentiles.P999, percentiles.Ops))
+	}
+	if len(degraded) == 0 {
+		return nil
+	}
+
+	s.lastLatency[volumeID].degradedPeriods = last.degradedPeriods + 1
+	if s.lastLatency[volumeID].degradedPeriods < latencyDegradedIntervals {
+		return nil
+	}
+	return []monitor.Condition{
+		reasons.EBSVolumeLatencyDegraded.
+			Builder().
+			Message(fmt.Sprintf("Volume %s (%s) latency exceeded %s for %d consecutive periods: %s, queue length %d",
+				volumeID, blockDeviceName, s.latencyThreshold, s.lastLatency[volumeID].degradedPeriods, joinDegraded(degraded), stats.VolumeQueueLength)).
+			Build(),
+	}
+}
+
+func joinDegraded(degraded []string) string {
+	if len(degraded) == 1 {
+		return degraded[0]
+	}
+	return degraded[0] + " and " + degraded[1]
+}
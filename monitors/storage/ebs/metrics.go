@@ -0,0 +1,129 @@
+package ebs
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aws/eks-node-monitoring-agent/monitors/storage/nvme"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// StatsPollInterval is the interval at which the statistics of EBS volumes are
+// polled for export. It is independent of the throttling period, since the
+// exported counters are cumulative.
+const StatsPollInterval = time.Minute
+
+var volumeLabels = []string{"volume_id", "device"}
+
+var (
+	ebsReadOpsDesc = prometheus.NewDesc(
+		"ebs_nvme_read_ops_total", "Total number of read operations completed by the EBS volume.", volumeLabels, nil)
+	ebsWriteOpsDesc = prometheus.NewDesc(
+		"ebs_nvme_write_ops_total", "Total number of write operations completed by the EBS volume.", volumeLabels, nil)
+	ebsReadBytesDesc = prometheus.NewDesc(
+		"ebs_nvme_read_bytes_total", "Total number of bytes read from the EBS volume.", volumeLabels, nil)
+	ebsWriteBytesDesc = prometheus.NewDesc(
+		"ebs_nvme_write_bytes_total", "Total number of bytes written to the EBS volume.", volumeLabels, nil)
+	ebsReadTimeDesc = prometheus.NewDesc(
+		"ebs_nvme_read_seconds_total", "Total time spent by the EBS volume on read operations.", volumeLabels, nil)
+	ebsWriteTimeDesc = prometheus.NewDesc(
+		"ebs_nvme_write_seconds_total", "Total time spent by the EBS volume on write operations.", volumeLabels, nil)
+	ebsVolumeExceededIopsDesc = prometheus.NewDesc(
+		"ebs_nvme_volume_performance_exceeded_iops_seconds_total", "Total time that the IOPS demand exceeded the provisioned IOPS of the EBS volume.", volumeLabels, nil)
+	ebsVolumeExceededTpDesc = prometheus.NewDesc(
+		"ebs_nvme_volume_performance_exceeded_tp_seconds_total", "Total time that the throughput demand exceeded the provisioned throughput of the EBS volume.", volumeLabels, nil)
+	ebsInstanceExceededIopsDesc = prometheus.NewDesc(
+		"ebs_nvme_instance_performance_exceeded_iops_seconds_total", "Total time that the EBS IOPS demand exceeded the maximum IOPS of the instance.", volumeLabels, nil)
+	ebsInstanceExceededTpDesc = prometheus.NewDesc(
+		"ebs_nvme_instance_performance_exceeded_tp_seconds_total", "Total time that the EBS throughput demand exceeded the maximum throughput of the instance.", volumeLabels, nil)
+	ebsQueueLengthDesc = prometheus.NewDesc(
+		"ebs_nvme_volume_queue_length", "Number of operations waiting to be completed by the EBS volume.", volumeLabels, nil)
+	ebsReadLatencyDesc = prometheus.NewDesc(
+		"ebs_nvme_read_io_latency_seconds", "Latency of the read operations completed by the EBS volume.", volumeLabels, nil)
+	ebsWriteLatencyDesc = prometheus.NewDesc(
+		"ebs_nvme_write_io_latency_seconds", "Latency of the write operations completed by the EBS volume.", volumeLabels, nil)
+)
+
+var statsCollector = &volumeStatsCollector{}
+
+func init() {
+	metrics.Registry.MustRegister(statsCollector)
+}
+
+// volumeStatsCollector exports the most recently polled statistics of the EBS
+// volumes attached to the node. The statistics are exported as constant
+// metrics, since the counters are maintained by the volumes themselves.
+type volumeStatsCollector struct {
+	mu      sync.Mutex
+	volumes []volumeStats
+}
+
+// set replaces the exported statistics, so that volumes which are no longer
+// attached stop being exported.
+func (c *volumeStatsCollector) set(volumes []volumeStats) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.volumes = volumes
+}
+
+func (c *volumeStatsCollector) Describe(ch chan<- *prometheus.Desc) {
+	prometheus.DescribeByCollect(c, ch)
+}
+
+func (c *volumeStatsCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, volume := range c.volumes {
+		labels := []string{volume.volumeID, volume.blockDeviceName}
+		stats := volume.stats
+		for desc, value := range map[*prometheus.Desc]float64{
+			ebsReadOpsDesc:              float64(stats.TotalReadOps),
+			ebsWriteOpsDesc:             float64(stats.TotalWriteOps),
+			ebsReadBytesDesc:            float64(stats.TotalReadBytes),
+			ebsWriteBytesDesc:           float64(stats.TotalWriteBytes),
+			ebsReadTimeDesc:             microsecondsToSeconds(stats.TotalReadTime),
+			ebsWriteTimeDesc:            microsecondsToSeconds(stats.TotalWriteTime),
+			ebsVolumeExceededIopsDesc:   microsecondsToSeconds(stats.EbsVolumePerformanceExceededIops),
+			ebsVolumeExceededTpDesc:     microsecondsToSeconds(stats.EbsVolumePerformanceExceededTp),
+			ebsInstanceExceededIopsDesc: microsecondsToSeconds(stats.Ec2InstanceEbsPerformanceExceededIops),
+			ebsInstanceExceededTpDesc:   microsecondsToSeconds(stats.Ec2InstanceEbsPerformanceExceededTp),
+		} {
+			ch <- prometheus.MustNewConstMetric(desc, prometheus.CounterValue, value, labels...)
+		}
+		ch <- prometheus.MustNewConstMetric(ebsQueueLengthDesc, prometheus.GaugeValue, float64(stats.VolumeQueueLength), labels...)
+		ch <- latencyHistogram(ebsReadLatencyDesc, &stats.ReadIoLatencyHistogram, stats.TotalReadTime, labels)
+		ch <- latencyHistogram(ebsWriteLatencyDesc, &stats.WriteIoLatencyHistogram, stats.TotalWriteTime, labels)
+	}
+}
+
+// latencyHistogram converts the latency histogram of a volume into a
+// Prometheus histogram, with the upper bound of each bin as a bucket. The sum
+// is the total time spent on the operations.
+func latencyHistogram(desc *prometheus.Desc, histogram *ebsnvme.EbsNvmeHistogram, totalTime uint64, labels []string) prometheus.Metric {
+	numBins := min(int(histogram.NumBins), len(histogram.Bins))
+	buckets := make(map[float64]uint64, numBins)
+	var count uint64
+	for i := range numBins {
+		bin := histogram.Bins[i]
+		count += uint64(bin.Count)
+		buckets[microsecondsToSeconds(bin.Upper)] = count
+	}
+	return prometheus.MustNewConstHistogram(desc, count, microsecondsToSeconds(totalTime), buckets, labels...)
+}
+
+func microsecondsToSeconds(us uint64) float64 {
+	return float64(us) / float64(time.Second.Microseconds())
+}
+
+// CollectStats polls the statistics of the EBS volumes attached to the node
+// and exports them on the metrics endpoint.
+func (s *ebsNVMeSystem) CollectStats(ctx context.Context) error {
+	volumes, err := s.queryVolumeStats(ctx)
+	if err != nil {
+		return err
+	}
+	statsCollector.set(volumes)
+	return nil
+}
@@ -0,0 +1,64 @@
+package ebs
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aws/eks-node-monitoring-agent/monitors/storage/nvme"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollectStats(t *testing.T) {
+	SetupRoot(t)
+	SetupNVMe(t, "nvmefoo")
+
+	ebsSystem := NewEBSSystem()
+	ebsSystem.deviceControllerFn = makeDeviceControllerFn(&fakeDeviceController{
+		StatsFn: func(device *ebsnvme.Device, src *ebsnvme.NvmeGetAmznStatsLogpage) (*ebsnvme.NvmeGetAmznStatsLogpage, error) {
+			src.TotalReadOps = 100
+			src.TotalReadTime = 2_500_000
+			src.EbsVolumePerformanceExceededIops = 1_500_000
+			src.VolumeQueueLength = 4
+			src.ReadIoLatencyHistogram = makeHistogram(60, 30, 10)
+			return src, nil
+		},
+	})
+	assert.NoError(t, ebsSystem.CollectStats(context.TODO()))
+
+	assert.NoError(t, testutil.CollectAndCompare(statsCollector, strings.NewReader(`
+# HELP ebs_nvme_read_ops_total Total number of read operations completed by the EBS volume.
+# TYPE ebs_nvme_read_ops_total counter
+ebs_nvme_read_ops_total{device="bar",volume_id="foo"} 100
+# HELP ebs_nvme_read_seconds_total Total time spent by the EBS volume on read operations.
+# TYPE ebs_nvme_read_seconds_total counter
+ebs_nvme_read_seconds_total{device="bar",volume_id="foo"} 2.5
+# HELP ebs_nvme_volume_performance_exceeded_iops_seconds_total Total time that the IOPS demand exceeded the provisioned IOPS of the EBS volume.
+# TYPE ebs_nvme_volume_performance_exceeded_iops_seconds_total counter
+ebs_nvme_volume_performance_exceeded_iops_seconds_total{device="bar",volume_id="foo"} 1.5
+# HELP ebs_nvme_volume_queue_length Number of operations waiting to be completed by the EBS volume.
+# TYPE ebs_nvme_volume_queue_length gauge
+ebs_nvme_volume_queue_length{device="bar",volume_id="foo"} 4
+# HELP ebs_nvme_read_io_latency_seconds Latency of the read operations completed by the EBS volume.
+# TYPE ebs_nvme_read_io_latency_seconds histogram
+ebs_nvme_read_io_latency_seconds_bucket{device="bar",volume_id="foo",le="0.000128"} 60
+ebs_nvme_read_io_latency_seconds_bucket{device="bar",volume_id="foo",le="0.000256"} 90
+ebs_nvme_read_io_latency_seconds_bucket{device="bar",volume_id="foo",le="0.000512"} 100
+ebs_nvme_read_io_latency_seconds_bucket{device="bar",volume_id="foo",le="+Inf"} 100
+ebs_nvme_read_io_latency_seconds_sum{device="bar",volume_id="foo"} 2.5
+ebs_nvme_read_io_latency_seconds_count{device="bar",volume_id="foo"} 100
+`),
+		"ebs_nvme_read_ops_total",
+		"ebs_nvme_read_seconds_total",
+		"ebs_nvme_volume_performance_exceeded_iops_seconds_total",
+		"ebs_nvme_volume_queue_length",
+		"ebs_nvme_read_io_latency_seconds",
+	))
+
+	t.Run("Detached", func(t *testing.T) {
+		SetupRoot(t)
+		assert.NoError(t, ebsSystem.CollectStats(context.TODO()))
+		assert.Equal(t, 0, testutil.CollectAndCount(statsCollector))
+	})
+}
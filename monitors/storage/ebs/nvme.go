@@ -46,7 +46,30 @@ func (e *ebsNVMeDeviceController) QueryIdCtrlFromDevice() (*ebsnvme.NvmeIdentify
 	return identityDevice.QueryIdCtrlFromDevice()
 }
 
+// volumeStats is the stats log page of an EBS volume.
+type volumeStats struct {
+	volumeID        string
+	blockDeviceName string
+	stats           *ebsnvme.NvmeGetAmznStatsLogpage
+}
+
 func (s *ebsNVMeSystem) NVMeThrottles(ctx context.Context) ([]monitor.Condition, error) {
+	volumes, err := s.queryVolumeStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var conditions []monitor.Condition
+	for _, volume := range volumes {
+		conditions = append(conditions, s.checkVolumeStatistics(volume.stats, volume.volumeID, volume.blockDeviceName)...)
+		conditions = append(conditions, s.checkVolumeLatency(volume.stats, volume.volumeID, volume.blockDeviceName)...)
+	}
+	return conditions, nil
+}
+
+// queryVolumeStats queries the stats log page of every EBS volume attached to
+// the node. Devices that can't be queried are skipped.
+func (s *ebsNVMeSystem) queryVolumeStats(ctx context.Context) ([]volumeStats, error) {
 	logger := log.FromContext(ctx)
 
 	devicePaths, err := filepath.Glob(config.ToHostPath("/dev/nvme*"))
@@ -54,7 +77,7 @@ func (s *ebsNVMeSystem) NVMeThrottles(ctx context.Context) ([]monitor.Condition,
 		return nil, fmt.Errorf("discovering NVME devices, %w", err)
 	}
 
-	var conditions []monitor.Condition
+	var volumes []volumeStats
 
 	checkedVolumes := set.New[string]()
 
@@ -92,10 +115,10 @@ func (s *ebsNVMeSystem) NVMeThrottles(ctx context.Context) ([]monitor.Condition,
 			continue
 		}
 
-		conditions = append(conditions, s.checkVolumeStatistics(stats, volumeID, blockDeviceName)...)
+		volumes = append(volumes, volumeStats{volumeID: volumeID, blockDeviceName: blockDeviceName, stats: stats})
 	}
 
-	return conditions, err
+	return volumes, nil
 }
 
 func (s *ebsNVMeSystem) checkVolumeStatistics(stats *ebsnvme.NvmeGetAmznStatsLogpage, volumeID string, blockDeviceName string) []monitor.Condition {
@@ -2,9 +2,11 @@ package ebs
 
 import (
 	"context"
+	"math"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/aws/eks-node-monitoring-agent/api/monitor"
 	"github.com/aws/eks-node-monitoring-agent/monitors/storage/nvme"
@@ -140,6 +142,131 @@ func TestEbsThrottling(t *testing.T) {
 	})
 }
 
+// makeHistogram returns a histogram with bins of doubling latency starting at
+// 128us, holding the given counts.
+func makeHistogram(counts ...uint32) ebsnvme.EbsNvmeHistogram {
+	histogram := ebsnvme.EbsNvmeHistogram{NumBins: uint64(len(counts))}
+	lower := uint64(0)
+	upper := uint64(128)
+	for i, count := range counts {
+		histogram.Bins[i] = ebsnvme.NvmeHistogramBin{Lower: lower, Upper: upper, Count: count}
+		lower, upper = upper, upper*2
+	}
+	return histogram
+}
+
+func TestHistogramPercentiles(t *testing.T) {
+	t.Run("Empty", func(t *testing.T) {
+		histogram := makeHistogram(0, 0, 0)
+		assert.Equal(t, LatencyPercentiles{}, HistogramPercentiles(&histogram, nil))
+	})
+
+	t.Run("Interval", func(t *testing.T) {
+		previous := makeHistogram(500, 100, 0, 0)
+		// 1000 ops within the interval: 900 within 128us, 95 within 256us,
+		// 4 within 512us and 1 within 1024us.
+		current := makeHistogram(1400, 195, 4, 1)
+		assert.Equal(t, LatencyPercentiles{
+			Ops:  1000,
+			P50:  128 * time.Microsecond,
+			P99:  256 * time.Microsecond,
+			P999: 512 * time.Microsecond,
+		}, HistogramPercentiles(&current, &previous))
+	})
+
+	t.Run("WrappedCounter", func(t *testing.T) {
+		previous := makeHistogram(math.MaxUint32-9, 0)
+		current := makeHistogram(10, 30)
+		percentiles := HistogramPercentiles(&current, &previous)
+		assert.Equal(t, uint64(50), percentiles.Ops)
+		assert.Equal(t, 256*time.Microsecond, percentiles.P50)
+	})
+
+	t.Run("LayoutChanged", func(t *testing.T) {
+		previous := makeHistogram(100, 100, 100)
+		current := makeHistogram(10, 10)
+		assert.Equal(t, uint64(20), HistogramPercentiles(&current, &previous).Ops)
+	})
+}
+
+func TestEbsLatency(t *testing.T) {
+	SetupRoot(t)
+	SetupNVMe(t, "nvmefoo")
+
+	// each call to the stats function advances the volume's histograms by one
+	// period of the given counts.
+	newSystem := func(periods ...[]uint32) *ebsNVMeSystem {
+		ebsSystem := NewEBSSystem()
+		read := makeHistogram(0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0)
+		call := 0
+		ebsSystem.deviceControllerFn = makeDeviceControllerFn(&fakeDeviceController{
+			StatsFn: func(device *ebsnvme.Device, src *ebsnvme.NvmeGetAmznStatsLogpage) (*ebsnvme.NvmeGetAmznStatsLogpage, error) {
+				for i, count := range periods[call] {
+					read.Bins[i].Count += count
+				}
+				call++
+				src.ReadIoLatencyHistogram = read
+				src.WriteIoLatencyHistogram = makeHistogram(0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0)
+				src.VolumeQueueLength = 32
+				return src, nil
+			},
+		})
+		return ebsSystem
+	}
+	// the p99 latency of both is 65.536ms, and the p99.9 latency of the slower
+	// one is 131.072ms.
+	slow := []uint32{9700, 100, 0, 0, 0, 0, 0, 0, 0, 200}
+	slower := []uint32{9700, 0, 0, 0, 0, 0, 0, 0, 0, 200, 100}
+	fast := []uint32{9900, 100}
+
+	t.Run("Degraded", func(t *testing.T) {
+		ebsSystem := newSystem(slow, slower, slower, slower)
+		ebsSystem.SetLatencyThreshold(50 * time.Millisecond)
+		for range 2 {
+			conditions, err := ebsSystem.NVMeThrottles(context.TODO())
+			assert.NoError(t, err)
+			assert.Len(t, conditions, 0)
+		}
+		conditions, err := ebsSystem.NVMeThrottles(context.TODO())
+		assert.NoError(t, err)
+		assert.Equal(t, []monitor.Condition{{
+			Reason:   "EBSVolumeLatencyDegraded",
+			Message:  "Volume foo (bar) latency exceeded 50ms for 2 consecutive periods: read p99 latency 65.536ms (p50 128µs, p99.9 131.072ms, 10000 ops), queue length 32",
+			Severity: monitor.SeverityWarning,
+		}}, conditions)
+	})
+
+	t.Run("Recovered", func(t *testing.T) {
+		ebsSystem := newSystem(slow, slower, fast, slower)
+		ebsSystem.SetLatencyThreshold(50 * time.Millisecond)
+		for range 4 {
+			conditions, err := ebsSystem.NVMeThrottles(context.TODO())
+			assert.NoError(t, err)
+			assert.Len(t, conditions, 0)
+		}
+	})
+
+	t.Run("BelowThreshold", func(t *testing.T) {
+		ebsSystem := newSystem(slow, slower, slower, slower)
+		for range 4 {
+			conditions, err := ebsSystem.NVMeThrottles(context.TODO())
+			assert.NoError(t, err)
+			assert.Len(t, conditions, 0)
+		}
+	})
+
+	t.Run("Idle", func(t *testing.T) {
+		idle := []uint32{90, 0, 0, 0, 0, 0, 0, 0, 0, 0, 10}
+		ebsSystem := newSystem(idle, idle, idle, idle)
+		ebsSystem.SetLatencyThreshold(time.Millisecond)
+		for range 4 {
+			conditions, err := ebsSystem.NVMeThrottles(context.TODO())
+			assert.NoError(t, err)
+			assert.Len(t, conditions, 0)
+		}
+	})
+}
+
 func makeDeviceControllerFn(controller *fakeDeviceController) func(*ebsnvme.Device) DeviceController {
 	return func(device *ebsnvme.Device) DeviceController {
 		controller.device = device
@@ -0,0 +1,155 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/moby/sys/mountinfo"
+
+	"github.com/aws/eks-node-monitoring-agent/pkg/config"
+	"github.com/aws/eks-node-monitoring-agent/pkg/reasons"
+)
+
+// ~~~~ Read-only filesystems ~~~~
+
+// blockMount is the state of a filesystem backed by a block device.
+type blockMount struct {
+	// mountPoint is the shortest of the mount points of the filesystem, since
+	// bind mounts of it are mounted under it.
+	mountPoint string
+	readOnly   bool
+}
+
+// readBlockMounts returns the filesystems of the host which are backed by a
+// block device, keyed by the device. The mount table of the host's init
+// process is read, so that mounts made outside of the agent's mount namespace
+// are included.
+func readBlockMounts() (map[string]blockMount, error) {
+	file, err := os.Open(config.ToHostPath("/proc/1/mountinfo"))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	infos, err := mountinfo.GetMountsFromReader(file, func(info *mountinfo.Info) (skip, stop bool) {
+		return !strings.HasPrefix(info.Source, "/dev/") || strings.HasPrefix(info.Source, "/dev/loop"), false
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	mounts := map[string]blockMount{}
+	for _, info := range infos {
+		mount, ok := mounts[info.Source]
+		if ok && len(mount.mountPoint) <= len(info.Mountpoint) {
+			continue
+		}
+		// the superblock options hold the state of the filesystem itself,
+		// while the mount options may be read-only for a single bind mount.
+		mounts[info.Source] = blockMount{
+			mountPoint: info.Mountpoint,
+			readOnly:   slices.Contains(strings.Split(info.VFSOptions, ","), "ro"),
+		}
+	}
+	return mounts, nil
+}
+
+// handleReadOnlyFilesystems reports filesystems which were remounted read-only
+// since the previous check, as the kernel does when it detects errors.
+// Filesystems which are read-only when they are first seen are expected to be,
+// like the root filesystem of some operating systems.
+func (m *StorageMonitor) handleReadOnlyFilesystems() error {
+	mounts, err := readBlockMounts()
+	if err != nil {
+		return err
+	}
+	defer func() { m.blockMounts = mounts }()
+
+	if m.blockMounts == nil {
+		return nil
+	}
+	for _, device := range slices.Sorted(maps.Keys(mounts)) {
+		mount := mounts[device]
+		if previous, ok := m.blockMounts[device]; ok && !previous.readOnly && mount.readOnly {
+			if err := m.manager.Notify(context.Background(),
+				reasons.FilesystemReadOnly.
+					Builder().
+					Message(fmt.Sprintf("Filesystem %s on %s was remounted read-only", mount.mountPoint, device)).
+					Build(),
+			); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Regex patterns for detecting filesystem errors, each capturing the device.
+// Matches patterns like:
+// - "EXT4-fs error (device nvme0n1p1): ext4_lookup:1855: inode #2: comm ls: deleted inode referenced: 12"
+// - "EXT4-fs (nvme0n1p1): Remounting filesystem read-only"
+// - "XFS (nvme1n1): Corruption detected. Unmount and run xfs_repair"
+// - "XFS (nvme1n1): Filesystem has been shut down due to log error (0x2)."
+// - "XFS (nvme1n1): Corruption of in-memory data (0x8) detected at xfs_trans_cancel+0x14c/0x170 (fs/xfs/xfs_trans.c:1097).  Shutting down filesystem"
+var filesystemErrors = []struct {
+	regexp *regexp.Regexp
+	reason reasons.ReasonMeta
+	// message is formatted with the description of the device.
+	message string
+}{
+	{
+		regexp.MustCompile(`EXT4-fs error \(device ([^)]+)\)`),
+		reasons.FilesystemCorruption,
+		"The ext4 filesystem %s reported errors, indicating it is corrupted",
+	},
+	{
+		regexp.MustCompile(`EXT4-fs \(([^)]+)\): Remounting filesystem read-only`),
+		reasons.FilesystemReadOnly,
+		"The ext4 filesystem %s was remounted read-only after errors",
+	},
+	{
+		regexp.MustCompile(`XFS \(([^)]+)\): .*(?:Filesystem has been shut down|Shutting down filesystem)`),
+		reasons.FilesystemShutdown,
+		"The xfs filesystem %s was shut down and fails all further I/O",
+	},
+	{
+		regexp.MustCompile(`XFS \(([^)]+)\): Corruption detected`),
+		reasons.FilesystemCorruption,
+		"The xfs filesystem %s reported errors, indicating it is corrupted",
+	},
+}
+
+func (m *StorageMonitor) handleFilesystemErrors(line string) error {
+	for _, filesystemError := range filesystemErrors {
+		if matches := filesystemError.regexp.FindStringSubmatch(line); len(matches) > 1 {
+			return m.manager.Notify(context.Background(),
+				filesystemError.reason.
+					Builder().
+					Message(fmt.Sprintf(filesystemError.message, m.describeDevice(matches[1]))).
+					Build(),
+			)
+		}
+	}
+	return nil
+}
+
+// describeDevice names the mount point of the filesystem on the device along
+// with the device, when it is mounted.
+func (m *StorageMonitor) describeDevice(device string) string {
+	mounts, err := readBlockMounts()
+	if err != nil {
+		m.log.V(2).Info("failed to read mounts", "error", err)
+	}
+	for source, mount := range mounts {
+		if filepath.Base(source) == device {
+			return fmt.Sprintf("%s on %s", mount.mountPoint, source)
+		}
+	}
+	return fmt.Sprintf("on %s", device)
+}
@@ -0,0 +1,126 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/eks-node-monitoring-agent/api/monitor"
+	"github.com/aws/eks-node-monitoring-agent/pkg/config"
+)
+
+const (
+	mountinfoRW = `22 1 259:1 / / rw,noatime shared:1 - xfs /dev/nvme0n1p1 rw,attr2,inode64
+23 22 0:21 / /proc rw,nosuid,nodev,noexec,relatime shared:5 - proc proc rw
+24 22 259:4 / /mnt/data rw,relatime shared:10 - ext4 /dev/nvme1n1 rw
+25 24 259:4 /pods /var/lib/kubelet/pods/abc/volumes/data ro,relatime shared:10 - ext4 /dev/nvme1n1 rw
+26 22 7:0 / /snap/core rw,relatime shared:11 - squashfs /dev/loop0 ro
+`
+	mountinfoRO = `22 1 259:1 / / rw,noatime shared:1 - xfs /dev/nvme0n1p1 rw,attr2,inode64
+23 22 0:21 / /proc rw,nosuid,nodev,noexec,relatime shared:5 - proc proc rw
+24 22 259:4 / /mnt/data ro,relatime shared:10 - ext4 /dev/nvme1n1 ro,errors=remount-ro
+25 24 259:4 /pods /var/lib/kubelet/pods/abc/volumes/data ro,relatime shared:10 - ext4 /dev/nvme1n1 ro,errors=remount-ro
+26 22 7:0 / /snap/core rw,relatime shared:11 - squashfs /dev/loop0 ro
+`
+)
+
+func writeMountinfo(t *testing.T, content string) {
+	path := config.ToHostPath("/proc/1/mountinfo")
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func TestReadOnlyFilesystems(t *testing.T) {
+	t.Setenv(config.HOST_ROOT_ENV, t.TempDir())
+
+	t.Run("Remounted", func(t *testing.T) {
+		mockManager := &mockManager{res: make(chan monitor.Condition, 5)}
+		mon := NewStorageMonitor()
+		mon.manager = mockManager
+
+		writeMountinfo(t, mountinfoRW)
+		require.NoError(t, mon.handleReadOnlyFilesystems())
+		writeMountinfo(t, mountinfoRO)
+		require.NoError(t, mon.handleReadOnlyFilesystems())
+		require.Len(t, mockManager.res, 1)
+		condition := <-mockManager.res
+		assert.Equal(t, "FilesystemReadOnly", condition.Reason)
+		assert.Equal(t, monitor.SeverityFatal, condition.Severity)
+		assert.Equal(t, "Filesystem /mnt/data on /dev/nvme1n1 was remounted read-only", condition.Message)
+
+		// a filesystem is only reported when it is remounted.
+		require.NoError(t, mon.handleReadOnlyFilesystems())
+		assert.Len(t, mockManager.res, 0)
+	})
+
+	t.Run("ReadOnlyWhenFirstSeen", func(t *testing.T) {
+		mockManager := &mockManager{res: make(chan monitor.Condition, 5)}
+		mon := NewStorageMonitor()
+		mon.manager = mockManager
+
+		writeMountinfo(t, mountinfoRO)
+		require.NoError(t, mon.handleReadOnlyFilesystems())
+		require.NoError(t, mon.handleReadOnlyFilesystems())
+		assert.Len(t, mockManager.res, 0)
+	})
+}
+
+func TestFilesystemErrors(t *testing.T) {
+	t.Setenv(config.HOST_ROOT_ENV, t.TempDir())
+	writeMountinfo(t, mountinfoRW)
+
+	for _, tt := range []struct {
+		logLine string
+		reason  string
+		message string
+	}{
+		{
+			logLine: "[ 1234.567890] EXT4-fs error (device nvme1n1): ext4_lookup:1855: inode #2: comm ls: deleted inode referenced: 12",
+			reason:  "FilesystemCorruption",
+			message: "The ext4 filesystem /mnt/data on /dev/nvme1n1 reported errors, indicating it is corrupted",
+		},
+		{
+			logLine: "[ 1234.567891] EXT4-fs (nvme1n1): Remounting filesystem read-only",
+			reason:  "FilesystemReadOnly",
+			message: "The ext4 filesystem /mnt/data on /dev/nvme1n1 was remounted read-only after errors",
+		},
+		{
+			logLine: "[ 1234.567892] XFS (nvme0n1p1): Corruption detected. Unmount and run xfs_repair",
+			reason:  "FilesystemCorruption",
+			message: "The xfs filesystem / on /dev/nvme0n1p1 reported errors, indicating it is corrupted",
+		},
+		{
+			logLine: "[ 1234.567893] XFS (nvme0n1p1): Filesystem has been shut down due to log error (0x2).",
+			reason:  "FilesystemShutdown",
+			message: "The xfs filesystem / on /dev/nvme0n1p1 was shut down and fails all further I/O",
+		},
+		{
+			logLine: "[ 1234.567894] XFS (dm-0): Corruption of in-memory data (0x8) detected at xfs_trans_cancel+0x14c/0x170 (fs/xfs/xfs_trans.c:1097).  Shutting down filesystem.",
+			reason:  "FilesystemShutdown",
+			message: "The xfs filesystem on dm-0 was shut down and fails all further I/O",
+		},
+		{
+			logLine: "[ 1234.567895] XFS (nvme0n1p1): Mounting V5 Filesystem",
+		},
+	} {
+		t.Run(tt.logLine, func(t *testing.T) {
+			mockManager := &mockManager{res: make(chan monitor.Condition, 1)}
+			mon := NewStorageMonitor()
+			mon.manager = mockManager
+
+			require.NoError(t, mon.handleFilesystemErrors(tt.logLine))
+			if tt.reason == "" {
+				assert.Len(t, mockManager.res, 0)
+				return
+			}
+			require.Len(t, mockManager.res, 1)
+			condition := <-mockManager.res
+			assert.Equal(t, tt.reason, condition.Reason)
+			assert.Equal(t, monitor.SeverityFatal, condition.Severity)
+			assert.Equal(t, tt.message, condition.Message)
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package instancestore
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"k8s.io/utils/set"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/aws/eks-node-monitoring-agent/api/monitor"
+	"github.com/aws/eks-node-monitoring-agent/monitors/storage/nvme"
+	"github.com/aws/eks-node-monitoring-agent/pkg/config"
+	"github.com/aws/eks-node-monitoring-agent/pkg/reasons"
+)
+
+// percentUsedThreshold is the estimate of the endurance used by a drive, as a
+// percentage of its rated endurance, at which it is reported.
+const percentUsedThreshold = 90
+
+// criticalWarnings are the names of the bits of the critical warning field of
+// the SMART log. The spare bit is omitted since a low available spare is
+// reported on its own.
+var criticalWarnings = []struct {
+	bit  uint8
+	name string
+}{
+	{ebsnvme.NVME_SMART_CRIT_TEMPERATURE, "temperature"},
+	{ebsnvme.NVME_SMART_CRIT_RELIABILITY, "reliability degraded"},
+	{ebsnvme.NVME_SMART_CRIT_MEDIA, "media read-only"},
+	{ebsnvme.NVME_SMART_CRIT_VOLATILE_MEMORY, "volatile memory backup failed"},
+	{ebsnvme.NVME_SMART_CRIT_PERSISTENT_MEMORY, "persistent memory read-only"},
+}
+
+type DeviceController interface {
+	QueryIdCtrlFromDevice() (*ebsnvme.NvmeIdentifyController, error)
+	QuerySmartLogFromDevice() (*ebsnvme.NvmeSmartLog, error)
+}
+
+type nvmeDeviceController struct {
+	device *ebsnvme.Device
+}
+
+func (c *nvmeDeviceController) QueryIdCtrlFromDevice() (*ebsnvme.NvmeIdentifyController, error) {
+	identityDevice := ebsnvme.IdDevice{Device: c.device}
+	return identityDevice.QueryIdCtrlFromDevice()
+}
+
+func (c *nvmeDeviceController) QuerySmartLogFromDevice() (*ebsnvme.NvmeSmartLog, error) {
+	smartDevice := ebsnvme.SmartDevice{Device: c.device}
+	return smartDevice.QuerySmartLogFromDevice()
+}
+
+func NewInstanceStoreSystem() *instanceStoreSystem {
+	return &instanceStoreSystem{
+		lastMediaErrors:    map[string]uint64{},
+		deviceControllerFn: func(device *ebsnvme.Device) DeviceController { return &nvmeDeviceController{device} },
+	}
+}
+
+type instanceStoreSystem struct {
+	lastMediaErrors map[string]uint64
+
+	deviceControllerFn func(*ebsnvme.Device) DeviceController
+}
+
+// SmartHealth reports the NVMe instance store drives of the node whose SMART
+// health log indicates that they are failing or wearing out.
+func (s *instanceStoreSystem) SmartHealth(ctx context.Context) ([]monitor.Condition, error) {
+	logger := log.FromContext(ctx)
+
+	devicePaths, err := filepath.Glob(config.ToHostPath("/dev/nvme*"))
+	if err != nil {
+		return nil, fmt.Errorf("discovering NVME devices, %w", err)
+	}
+
+	var conditions []monitor.Condition
+
+	checkedDrives := set.New[string]()
+
+	for _, devicePath := range devicePaths {
+		deviceController := s.deviceControllerFn(ebsnvme.NewDevice(devicePath))
+
+		idInfo, err := deviceController.QueryIdCtrlFromDevice()
+		if err != nil {
+			logger.V(2).Info("ignoring device due to inability to ID", "device", devicePath, "error", err)
+			continue
+		}
+
+		if idInfo.Vid != ebsnvme.AMZN_NVME_VID || idInfo.GetModelNumber() != ebsnvme.AMZN_NVME_INSTANCE_STORE_MN {
+			logger.V(6).Info("ignoring non-instance-store device", "nvmeId", idInfo.Vid, "modelNumber", idInfo.GetModelNumber())
+			continue
+		}
+
+		// the controller and each of its namespaces share a serial number.
+		serial := strings.TrimSpace(ebsnvme.BytesToString(idInfo.Sn[:]))
+		if checkedDrives.Has(serial) {
+			continue
+		}
+		checkedDrives.Insert(serial)
+
+		smartLog, err := deviceController.QuerySmartLogFromDevice()
+		if err != nil {
+			logger.V(2).Info("ignoring device due to inability to query SMART log", "device", devicePath, "error", err)
+			continue
+		}
+
+		conditions = append(conditions, s.checkSmartLog(smartLog, serial, filepath.Base(devicePath))...)
+	}
+
+	return conditions, nil
+}
+
+func (s *instanceStoreSystem) checkSmartLog(smartLog *ebsnvme.NvmeSmartLog, serial string, deviceName string) []monitor.Condition {
+	var conditions []monitor.Condition
+
+	var warnings []string
+	for _, warning := range criticalWarnings {
+		if smartLog.CriticalWarning&warning.bit != 0 {
+			warnings = append(warnings, warning.name)
+		}
+	}
+	if len(warnings) > 0 {
+		conditions = append(conditions,
+			reasons.InstanceStoreCriticalWarning.
+				Builder().
+				Message(fmt.Sprintf("Instance store %s (%s) reported critical warnings: %s (temperature %dK)", serial, deviceName, strings.Join(warnings, ", "), smartLog.GetTemperature())).
+				Build(),
+		)
+	}
+
+	// the counter of media errors is cumulative over the life of the drive,
+	// so only new errors are reported.
+	if mediaErrors := smartLog.GetMediaErrors(); mediaErrors > s.lastMediaErrors[serial] {
+		conditions = append(conditions,
+			reasons.InstanceStoreMediaErrors.
+				Builder().
+				Message(fmt.Sprintf("Instance store %s (%s) reported %d new media and data integrity errors (%d total)", serial, deviceName, mediaErrors-s.lastMediaErrors[serial], mediaErrors)).
+				Build(),
+		)
+		s.lastMediaErrors[serial] = mediaErrors
+	}
+
+	if smartLog.AvailSpare < smartLog.SpareThresh {
+		conditions = append(conditions,
+			reasons.InstanceStoreSpareLow.
+				Builder().
+				Message(fmt.Sprintf("Instance store %s (%s) available spare %d%% is below its threshold of %d%%", serial, deviceName, smartLog.AvailSpare, smartLog.SpareThresh)).
+				Build(),
+		)
+	}
+
+	if smartLog.PercentUsed >= percentUsedThreshold {
+		conditions = append(conditions,
+			reasons.InstanceStoreWearOut.
+				Builder().
+				Message(fmt.Sprintf("Instance store %s (%s) has used %d%% of its rated endurance", serial, deviceName, smartLog.PercentUsed)).
+				Build(),
+		)
+	}
+
+	return conditions
+}
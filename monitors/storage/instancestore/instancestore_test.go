@@ -0,0 +1,124 @@
+package instancestore
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aws/eks-node-monitoring-agent/api/monitor"
+	"github.com/aws/eks-node-monitoring-agent/monitors/storage/nvme"
+	"github.com/aws/eks-node-monitoring-agent/pkg/config"
+)
+
+func TestSmartHealth(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv(config.HOST_ROOT_ENV, root)
+	for _, device := range []string{"nvme0", "nvme0n1", "nvme1"} {
+		assert.NoError(t, os.MkdirAll(filepath.Join(root, "dev", device), 0755))
+	}
+
+	newSystem := func(smartLogs map[string]*ebsnvme.NvmeSmartLog) *instanceStoreSystem {
+		system := NewInstanceStoreSystem()
+		system.deviceControllerFn = func(device *ebsnvme.Device) DeviceController {
+			return &fakeDeviceController{device: device, smartLogs: smartLogs}
+		}
+		return system
+	}
+
+	t.Run("Healthy", func(t *testing.T) {
+		system := newSystem(map[string]*ebsnvme.NvmeSmartLog{
+			"nvme0": {AvailSpare: 100, SpareThresh: 10, PercentUsed: 3},
+		})
+		conditions, err := system.SmartHealth(context.TODO())
+		assert.NoError(t, err)
+		assert.Empty(t, conditions)
+	})
+
+	t.Run("Failing", func(t *testing.T) {
+		smartLog := &ebsnvme.NvmeSmartLog{
+			CriticalWarning: ebsnvme.NVME_SMART_CRIT_SPARE | ebsnvme.NVME_SMART_CRIT_RELIABILITY | ebsnvme.NVME_SMART_CRIT_MEDIA,
+			Temperature:     [2]uint8{0x3b, 0x01},
+			AvailSpare:      5,
+			SpareThresh:     10,
+			PercentUsed:     95,
+			MediaErrors:     [16]byte{3},
+		}
+		system := newSystem(map[string]*ebsnvme.NvmeSmartLog{"nvme0": smartLog})
+		conditions, err := system.SmartHealth(context.TODO())
+		assert.NoError(t, err)
+		assert.Equal(t, []monitor.Condition{
+			{
+				Reason:   "InstanceStoreCriticalWarning",
+				Message:  "Instance store AWSnvme0 (nvme0) reported critical warnings: reliability degraded, media read-only (temperature 315K)",
+				Severity: monitor.SeverityFatal,
+			},
+			{
+				Reason:   "InstanceStoreMediaErrors",
+				Message:  "Instance store AWSnvme0 (nvme0) reported 3 new media and data integrity errors (3 total)",
+				Severity: monitor.SeverityWarning,
+			},
+			{
+				Reason:   "InstanceStoreSpareLow",
+				Message:  "Instance store AWSnvme0 (nvme0) available spare 5% is below its threshold of 10%",
+				Severity: monitor.SeverityWarning,
+			},
+			{
+				Reason:   "InstanceStoreWearOut",
+				Message:  "Instance store AWSnvme0 (nvme0) has used 95% of its rated endurance",
+				Severity: monitor.SeverityWarning,
+			},
+		}, conditions)
+
+		// media errors which were already reported are not reported again.
+		smartLog.CriticalWarning = 0
+		smartLog.AvailSpare = 100
+		smartLog.PercentUsed = 0
+		conditions, err = system.SmartHealth(context.TODO())
+		assert.NoError(t, err)
+		assert.Empty(t, conditions)
+
+		smartLog.MediaErrors = [16]byte{5}
+		conditions, err = system.SmartHealth(context.TODO())
+		assert.NoError(t, err)
+		assert.Equal(t, []monitor.Condition{{
+			Reason:   "InstanceStoreMediaErrors",
+			Message:  "Instance store AWSnvme0 (nvme0) reported 2 new media and data integrity errors (5 total)",
+			Severity: monitor.SeverityWarning,
+		}}, conditions)
+	})
+}
+
+// fakeDeviceController identifies nvme1 as an EBS volume and every other
+// device as a namespace of the instance store drive of its controller.
+type fakeDeviceController struct {
+	device    *ebsnvme.Device
+	smartLogs map[string]*ebsnvme.NvmeSmartLog
+}
+
+func (f *fakeDeviceController) controller() string {
+	name := filepath.Base(f.device.Path)
+	if i := len("nvme0"); len(name) > i {
+		name = name[:i]
+	}
+	return name
+}
+
+func (f *fakeDeviceController) QueryIdCtrlFromDevice() (*ebsnvme.NvmeIdentifyController, error) {
+	id := &ebsnvme.NvmeIdentifyController{
+		Vid: ebsnvme.AMZN_NVME_VID,
+	}
+	if f.controller() == "nvme1" {
+		copy(id.Mn[:], ebsnvme.AMZN_NVME_EBS_MN)
+	} else {
+		copy(id.Mn[:], ebsnvme.AMZN_NVME_INSTANCE_STORE_MN)
+	}
+	copy(id.Sn[:], "AWS"+f.controller())
+	return id, nil
+}
+
+func (f *fakeDeviceController) QuerySmartLogFromDevice() (*ebsnvme.NvmeSmartLog, error) {
+	return f.smartLogs[f.controller()], nil
+}
@@ -13,7 +13,9 @@ import (
 
 	"github.com/aws/eks-node-monitoring-agent/api/monitor"
 	"github.com/aws/eks-node-monitoring-agent/api/monitor/resource"
+	"github.com/aws/eks-node-monitoring-agent/monitors/storage/capacity"
 	"github.com/aws/eks-node-monitoring-agent/monitors/storage/ebs"
+	"github.com/aws/eks-node-monitoring-agent/monitors/storage/instancestore"
 	"github.com/aws/eks-node-monitoring-agent/pkg/config"
 	"github.com/aws/eks-node-monitoring-agent/pkg/osext"
 	"github.com/aws/eks-node-monitoring-agent/pkg/reasons"
@@ -37,9 +39,14 @@ type processIODetails struct {
 }
 
 type StorageMonitor struct {
-	manager    monitor.Manager
-	log        logr.Logger
-	delayCache cache.Store
+	manager             monitor.Manager
+	log                 logr.Logger
+	delayCache          cache.Store
+	ebsLatencyThreshold time.Duration
+	// blockMounts is the snapshot of the filesystems backed by block devices
+	// from the previous check for read-only remounts.
+	blockMounts map[string]blockMount
+	mountProber *mountProber
 }
 
 func buildIODelayCacheKey(id string, name string) string {
@@ -52,10 +59,17 @@ func delayCacheFunc(obj interface{}) (string, error) {
 
 func NewStorageMonitor() *StorageMonitor {
 	return &StorageMonitor{
-		delayCache: cache.NewTTLStore(delayCacheFunc, IODelayCacheDefaultExpirationTime),
+		delayCache:  cache.NewTTLStore(delayCacheFunc, IODelayCacheDefaultExpirationTime),
+		mountProber: newMountProber(),
 	}
 }
 
+// SetEBSLatencyThreshold sets the p99 latency above which EBS volumes are
+// reported.
+func (m *StorageMonitor) SetEBSLatencyThreshold(threshold time.Duration) {
+	m.ebsLatencyThreshold = threshold
+}
+
 func (m *StorageMonitor) Name() string {
 	return "storage"
 }
@@ -83,10 +97,18 @@ func (m *StorageMonitor) Register(ctx context.Context, mgr monitor.Manager) erro
 		return err
 	}
 
+	filesystemDmesg, err := mgr.Subscribe(resource.ResourceTypeDmesg, []resource.Part{})
+	if err != nil {
+		return err
+	}
+
 	for _, handler := range []interface{ Start(context.Context) error }{
 		util.NewChannelHandler(m.handleVarLogMessages, var_log_messages),
 		util.NewChannelHandler(m.handleKubeletLogs, kubelet_logs),
 		util.NewChannelHandler(m.handleBlockDeviceIOErrors, dmesg),
+		util.NewChannelHandler(m.handleFilesystemErrors, filesystemDmesg),
+		util.NewChannelHandler(func(time.Time) error { return m.handleReadOnlyFilesystems() }, util.TimeTickWithJitterContext(ctx, time.Minute)),
+		util.NewChannelHandler(func(time.Time) error { return m.handleNetworkMounts(ctx) }, util.TimeTickWithJitterContext(ctx, 5*time.Minute)),
 		util.NewChannelHandler(func(time.Time) error { return m.handleXFS() }, util.TimeTickWithJitterContext(ctx, 10*time.Minute)),
 		util.NewChannelHandler(func(time.Time) error { return m.handleIODelays() }, util.TimeTickWithJitterContext(ctx, 10*time.Minute)),
 	} {
@@ -103,6 +125,9 @@ func (m *StorageMonitor) Register(ctx context.Context, mgr monitor.Manager) erro
 
 	// EBS NVMe throttling monitoring (runs on all nodes with NVMe devices)
 	ebsSystem := ebs.NewEBSSystem()
+	if m.ebsLatencyThreshold > 0 {
+		ebsSystem.SetLatencyThreshold(m.ebsLatencyThreshold)
+	}
 	go func() {
 		for range util.TimeTickWithJitterContext(ctx, 10*time.Minute) {
 			conditions, err := ebsSystem.NVMeThrottles(ctx)
@@ -117,6 +142,47 @@ func (m *StorageMonitor) Register(ctx context.Context, mgr monitor.Manager) erro
 			}
 		}
 	}()
+	go func() {
+		for range util.TimeTickWithJitterContext(ctx, ebs.StatsPollInterval) {
+			if err := ebsSystem.CollectStats(ctx); err != nil {
+				m.log.Error(err, "failed to collect EBS NVMe statistics")
+			}
+		}
+	}()
+
+	// SMART health of NVMe instance store drives
+	instanceStoreSystem := instancestore.NewInstanceStoreSystem()
+	go func() {
+		for range util.TimeTickWithJitterContext(ctx, 10*time.Minute) {
+			conditions, err := instanceStoreSystem.SmartHealth(ctx)
+			if err != nil {
+				m.log.Error(err, "failed to check instance store SMART health")
+				continue
+			}
+			for _, condition := range conditions {
+				if err := m.manager.Notify(ctx, condition); err != nil {
+					m.log.Error(err, "failed to notify instance store condition")
+				}
+			}
+		}
+	}()
+
+	// filesystem capacity against the kubelet eviction thresholds
+	capacitySystem := capacity.NewCapacitySystem()
+	go func() {
+		for range util.TimeTickWithJitterContext(ctx, 5*time.Minute) {
+			conditions, err := capacitySystem.Capacity(ctx)
+			if err != nil {
+				m.log.Error(err, "failed to check filesystem capacity")
+				continue
+			}
+			for _, condition := range conditions {
+				if err := m.manager.Notify(ctx, condition); err != nil {
+					m.log.Error(err, "failed to notify filesystem capacity condition")
+				}
+			}
+		}
+	}()
 
 	return nil
 }
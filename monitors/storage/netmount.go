@@ -0,0 +1,187 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/moby/sys/mountinfo"
+	"golang.org/x/sys/unix"
+
+	"github.com/aws/eks-node-monitoring-agent/pkg/config"
+	"github.com/aws/eks-node-monitoring-agent/pkg/reasons"
+)
+
+// ~~~~ Network mounts ~~~~
+
+// networkFSTypes are the filesystem types of network mounts, covering NFS
+// (including EFS and FSx for OpenZFS and ONTAP), FSx for Lustre and SMB (FSx
+// for Windows File Server).
+var networkFSTypes = []string{"nfs", "nfs4", "lustre", "cifs", "smb3"}
+
+// netMountProbeTimeout bounds a single statfs of a network mount. A responsive
+// server answers well within it, even across availability zones.
+const netMountProbeTimeout = 10 * time.Second
+
+// mountProber runs statfs against network mounts with a bound on how long the
+// caller waits.
+//
+// A statfs on an unresponsive network mount blocks in uninterruptible sleep
+// until the server returns, which may be never, so on timeout the probe is
+// abandoned rather than waited on. Abandoning is only safe because a mount
+// source with an outstanding probe is not probed again until that probe
+// returns: a permanently hung server costs one goroutine, rather than a fresh
+// one for every check. Probes are keyed by source rather than mount point,
+// since the mount points of a source come and go with the pods using it.
+type mountProber struct {
+	statfs  func(path string, buf *unix.Statfs_t) error
+	timeout time.Duration
+
+	mu sync.Mutex
+	// running holds the time each outstanding probe was started at, keyed by
+	// mount source.
+	running map[string]time.Time
+}
+
+func newMountProber() *mountProber {
+	return &mountProber{
+		statfs:  unix.Statfs,
+		timeout: netMountProbeTimeout,
+		running: map[string]time.Time{},
+	}
+}
+
+// errProbeTimeout is returned for a mount that did not respond within
+// the timeout, including on later probes while the first one is outstanding.
+type errProbeTimeout struct {
+	blocked time.Duration
+}
+
+func (e errProbeTimeout) Error() string {
+	return fmt.Sprintf("statfs has not returned after %s", e.blocked)
+}
+
+// probe runs statfs against the mount point of the mount, and returns
+// errProbeTimeout when it does not return within the timeout.
+//
+// The mount point is resolved through the root of the host's init process,
+// since mounts the kubelet creates after the agent started do not propagate to
+// the host root mounted into the agent's container, where the mount point is
+// an empty directory that never hangs.
+func (p *mountProber) probe(ctx context.Context, mount *mountinfo.Info) error {
+	p.mu.Lock()
+	if startedAt, ok := p.running[mount.Source]; ok {
+		p.mu.Unlock()
+		return errProbeTimeout{blocked: time.Since(startedAt).Truncate(time.Second)}
+	}
+	startedAt := time.Now()
+	p.running[mount.Source] = startedAt
+	p.mu.Unlock()
+
+	// buffered so an abandoned probe can still publish and exit.
+	done := make(chan error, 1)
+	go func() {
+		var buf unix.Statfs_t
+		err := p.statfs(hostMountPath(mount.Mountpoint), &buf)
+		// released before publishing, so that a caller which receives the result
+		// always observes the mount as free again.
+		p.mu.Lock()
+		delete(p.running, mount.Source)
+		p.mu.Unlock()
+		done <- err
+	}()
+
+	timer := time.NewTimer(p.timeout)
+	defer timer.Stop()
+	select {
+	case err := <-done:
+		return err
+	case <-timer.C:
+		return errProbeTimeout{blocked: time.Since(startedAt).Truncate(time.Second)}
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// hostMountPath returns the path of a mount point of the host's mount
+// namespace.
+func hostMountPath(mountPoint string) string {
+	return config.ToHostPath(filepath.Join("/proc/1/root", mountPoint))
+}
+
+// readNetworkMounts returns the network mounts of the host. A source mounted
+// at several mount points, as the kubelet does for each pod using a volume, is
+// returned once with the shortest of its mount points, so that a hung server
+// is probed once.
+func readNetworkMounts() ([]*mountinfo.Info, error) {
+	file, err := os.Open(config.ToHostPath("/proc/1/mountinfo"))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	infos, err := mountinfo.GetMountsFromReader(file, func(info *mountinfo.Info) (skip, stop bool) {
+		return !slices.Contains(networkFSTypes, info.FSType), false
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var mounts []*mountinfo.Info
+	for _, info := range infos {
+		i := slices.IndexFunc(mounts, func(mount *mountinfo.Info) bool { return mount.Source == info.Source })
+		if i < 0 {
+			mounts = append(mounts, info)
+		} else if len(info.Mountpoint) < len(mounts[i].Mountpoint) {
+			mounts[i] = info
+		}
+	}
+	return mounts, nil
+}
+
+// handleNetworkMounts reports network mounts which do not respond to statfs.
+// The mounts are probed concurrently, so that one hung mount does not delay
+// the check of the others.
+func (m *StorageMonitor) handleNetworkMounts(ctx context.Context) error {
+	mounts, err := readNetworkMounts()
+	if err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	unresponsive := make([]error, len(mounts))
+	for i, mount := range mounts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := m.mountProber.probe(ctx, mount); err != nil {
+				if errors.As(err, &errProbeTimeout{}) {
+					unresponsive[i] = err
+				} else {
+					m.log.V(2).Info("failed to statfs network mount", "mountPoint", mount.Mountpoint, "error", err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	for i, mount := range mounts {
+		if unresponsive[i] == nil {
+			continue
+		}
+		if err := m.manager.Notify(ctx,
+			reasons.NetworkMountUnresponsive.
+				Builder().
+				Message(fmt.Sprintf("Network mount %s (%s) at %s is unresponsive: %s", mount.Source, mount.FSType, mount.Mountpoint, unresponsive[i])).
+				Build(),
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
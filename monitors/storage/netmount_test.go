@@ -0,0 +1,117 @@
+package storage
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/moby/sys/mountinfo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+
+	"github.com/aws/eks-node-monitoring-agent/api/monitor"
+	"github.com/aws/eks-node-monitoring-agent/pkg/config"
+)
+
+func TestMountProber(t *testing.T) {
+	release := make(chan struct{})
+	var calls atomic.Int32
+	prober := newMountProber()
+	prober.timeout = 10 * time.Millisecond
+	prober.statfs = func(string, *unix.Statfs_t) error {
+		calls.Add(1)
+		<-release
+		return nil
+	}
+	mount := &mountinfo.Info{Source: "fs-0123.efs.us-west-2.amazonaws.com:/", Mountpoint: "/mnt/efs"}
+
+	err := prober.probe(context.TODO(), mount)
+	assert.ErrorAs(t, err, &errProbeTimeout{})
+
+	// the hung probe is not started again while it is outstanding.
+	err = prober.probe(context.TODO(), mount)
+	assert.ErrorAs(t, err, &errProbeTimeout{})
+	assert.Equal(t, int32(1), calls.Load())
+
+	close(release)
+	require.Eventually(t, func() bool {
+		prober.mu.Lock()
+		defer prober.mu.Unlock()
+		return len(prober.running) == 0
+	}, time.Second, time.Millisecond)
+
+	assert.NoError(t, prober.probe(context.TODO(), mount))
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestNetworkMounts(t *testing.T) {
+	t.Setenv(config.HOST_ROOT_ENV, t.TempDir())
+	writeMountinfo(t, `22 1 259:1 / / rw,noatime shared:1 - xfs /dev/nvme0n1p1 rw,attr2,inode64
+30 22 0:50 / /var/lib/kubelet/pods/abc/volumes/kubernetes.io~csi/efs/mount rw,relatime shared:20 - nfs4 127.0.0.1:/ rw,vers=4.1
+31 22 0:50 / /mnt/efs rw,relatime shared:21 - nfs4 127.0.0.1:/ rw,vers=4.1
+32 22 0:51 / /mnt/fsx rw,relatime shared:22 - lustre 172.31.0.10@tcp:/fsx rw
+`)
+
+	release := make(chan struct{})
+	defer close(release)
+	mockManager := &mockManager{res: make(chan monitor.Condition, 5)}
+	mon := NewStorageMonitor()
+	mon.manager = mockManager
+	mon.mountProber.timeout = 10 * time.Millisecond
+	mon.mountProber.statfs = func(path string, buf *unix.Statfs_t) error {
+		if path == hostMountPath("/mnt/efs") {
+			<-release
+		}
+		return nil
+	}
+
+	require.NoError(t, mon.handleNetworkMounts(context.TODO()))
+	require.Len(t, mockManager.res, 1)
+	condition := <-mockManager.res
+	assert.Equal(t, "NetworkMountUnresponsive", condition.Reason)
+	assert.Equal(t, monitor.SeverityWarning, condition.Severity)
+	assert.Equal(t, "Network mount 127.0.0.1:/ (nfs4) at /mnt/efs is unresponsive: statfs has not returned after 0s", condition.Message)
+}
+
+func TestNetworkMountCreatedAfterStartup(t *testing.T) {
+	t.Setenv(config.HOST_ROOT_ENV, t.TempDir())
+	writeMountinfo(t, `22 1 259:1 / / rw,noatime shared:1 - xfs /dev/nvme0n1p1 rw,attr2,inode64
+30 22 0:50 / /var/lib/kubelet/pods/abc/volumes/kubernetes.io~csi/efs/mount rw,relatime shared:20 - nfs4 127.0.0.1:/ rw,vers=4.1
+`)
+	const mountPoint = "/var/lib/kubelet/pods/abc/volumes/kubernetes.io~csi/efs/mount"
+
+	release := make(chan struct{})
+	defer close(release)
+	var (
+		mu    sync.Mutex
+		paths []string
+	)
+	mockManager := &mockManager{res: make(chan monitor.Condition, 5)}
+	mon := NewStorageMonitor()
+	mon.manager = mockManager
+	mon.mountProber.timeout = 10 * time.Millisecond
+	mon.mountProber.statfs = func(path string, buf *unix.Statfs_t) error {
+		mu.Lock()
+		paths = append(paths, path)
+		mu.Unlock()
+		switch path {
+		case config.ToHostPath(mountPoint):
+			// the mount did not propagate to the host root of the container,
+			// so only the empty mount point directory is found there.
+			return nil
+		case config.ToHostPath("/proc/1/root" + mountPoint):
+			<-release
+		}
+		return nil
+	}
+
+	require.NoError(t, mon.handleNetworkMounts(context.TODO()))
+	require.Len(t, mockManager.res, 1)
+	assert.Equal(t, "NetworkMountUnresponsive", (<-mockManager.res).Reason)
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{config.ToHostPath("/proc/1/root" + mountPoint)}, paths)
+}
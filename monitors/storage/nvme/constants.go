@@ -6,12 +6,26 @@ const (
 	NVME_ADMIN_IDENTIFY  = 0x06
 	NVME_GET_LOG_PAGE    = 0x02
 	NVME_IOCTL_ADMIN_CMD = 0xC0484E41
+	NVME_NSID_ALL        = 0xFFFFFFFF
 )
 
-// Amazon EBS NVMe constants
+// NVMe SMART / Health Information constants
 const (
-	AMZN_NVME_EBS_MN           = "Amazon Elastic Block Store"
-	AMZN_NVME_STATS_LOGPAGE_ID = 0xD0
-	AMZN_NVME_STATS_MAGIC      = 0x3C23B510
-	AMZN_NVME_VID              = 0x1D0F
+	NVME_SMART_LOGPAGE_ID = 0x02
+
+	NVME_SMART_CRIT_SPARE             = 1 << 0
+	NVME_SMART_CRIT_TEMPERATURE       = 1 << 1
+	NVME_SMART_CRIT_RELIABILITY       = 1 << 2
+	NVME_SMART_CRIT_MEDIA             = 1 << 3
+	NVME_SMART_CRIT_VOLATILE_MEMORY   = 1 << 4
+	NVME_SMART_CRIT_PERSISTENT_MEMORY = 1 << 5
+)
+
+// Amazon NVMe constants
+const (
+	AMZN_NVME_EBS_MN            = "Amazon Elastic Block Store"
+	AMZN_NVME_INSTANCE_STORE_MN = "Amazon EC2 NVMe Instance Storage"
+	AMZN_NVME_STATS_LOGPAGE_ID  = 0xD0
+	AMZN_NVME_STATS_MAGIC       = 0x3C23B510
+	AMZN_NVME_VID               = 0x1D0F
 )
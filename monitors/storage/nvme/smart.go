@@ -0,0 +1,36 @@
+package ebsnvme
+
+import (
+	"unsafe"
+)
+
+// SmartDevice represents an NVMe device for SMART / health collection
+type SmartDevice struct {
+	*Device
+}
+
+// NewSmartDevice creates a new NVMe SMART device
+func NewSmartDevice(path string) *SmartDevice {
+	return &SmartDevice{
+		Device: NewDevice(path),
+	}
+}
+
+// QuerySmartLogFromDevice queries the SMART / Health Information log page from the device
+func (d *SmartDevice) QuerySmartLogFromDevice() (*NvmeSmartLog, error) {
+	smartLog := &NvmeSmartLog{}
+	numDwords := uint32(unsafe.Sizeof(*smartLog)) / 4
+	adminCmd := NvmeAdminCommand{
+		Opcode: NVME_GET_LOG_PAGE,
+		Addr:   uint64(uintptr(unsafe.Pointer(smartLog))),
+		Alen:   uint32(unsafe.Sizeof(*smartLog)),
+		Nsid:   NVME_NSID_ALL,
+		Cdw10:  uint32(NVME_SMART_LOGPAGE_ID | ((numDwords - 1) << 16)),
+	}
+
+	if err := d.NvmeIoctl(&adminCmd); err != nil {
+		return nil, err
+	}
+
+	return smartLog, nil
+}
@@ -1,6 +1,10 @@
 package ebsnvme
 
-import "strings"
+import (
+	"encoding/binary"
+	"math"
+	"strings"
+)
 
 // NVMe Admin Command structure
 type NvmeAdminCommand struct {
@@ -132,3 +136,55 @@ type NvmeGetAmznStatsLogpage struct {
 	WriteIoLatencyHistogram               EbsNvmeHistogram
 	Reserved2                             [496]byte
 }
+
+// NVMe SMART / Health Information Log Page structure
+type NvmeSmartLog struct {
+	CriticalWarning      uint8
+	Temperature          [2]uint8
+	AvailSpare           uint8
+	SpareThresh          uint8
+	PercentUsed          uint8
+	EnduranceCritWarning uint8
+	Reserved0            [25]byte
+	DataUnitsRead        [16]byte
+	DataUnitsWritten     [16]byte
+	HostReads            [16]byte
+	HostWrites           [16]byte
+	CtrlBusyTime         [16]byte
+	PowerCycles          [16]byte
+	PowerOnHours         [16]byte
+	UnsafeShutdowns      [16]byte
+	MediaErrors          [16]byte
+	NumErrLogEntries     [16]byte
+	WarningTempTime      uint32
+	CriticalCompTime     uint32
+	TempSensor           [8]uint16
+	ThmTemp1TransCount   uint32
+	ThmTemp2TransCount   uint32
+	ThmTemp1TotalTime    uint32
+	ThmTemp2TotalTime    uint32
+	Reserved1            [280]byte
+}
+
+// GetTemperature gets the composite temperature of the controller in Kelvin
+func (log *NvmeSmartLog) GetTemperature() uint16 {
+	return binary.LittleEndian.Uint16(log.Temperature[:])
+}
+
+// GetMediaErrors gets the number of unrecovered data integrity errors
+func (log *NvmeSmartLog) GetMediaErrors() uint64 {
+	return Uint128ToUint64(log.MediaErrors)
+}
+
+// GetNumErrLogEntries gets the number of error information log entries over the life of the controller
+func (log *NvmeSmartLog) GetNumErrLogEntries() uint64 {
+	return Uint128ToUint64(log.NumErrLogEntries)
+}
+
+// Uint128ToUint64 converts a little endian 128 bit counter to uint64, saturating on overflow
+func Uint128ToUint64(b [16]byte) uint64 {
+	if binary.LittleEndian.Uint64(b[8:]) != 0 {
+		return math.MaxUint64
+	}
+	return binary.LittleEndian.Uint64(b[:8])
+}
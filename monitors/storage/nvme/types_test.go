@@ -0,0 +1,50 @@
+package ebsnvme
+
+import (
+	"math"
+	"testing"
+	"unsafe"
+)
+
+func TestSmartLogSize(t *testing.T) {
+	// the SMART / Health Information log page is 512 bytes.
+	if size := unsafe.Sizeof(NvmeSmartLog{}); size != 512 {
+		t.Errorf("Expected size 512 but got %d", size)
+	}
+	if offset := unsafe.Offsetof(NvmeSmartLog{}.MediaErrors); offset != 160 {
+		t.Errorf("Expected media errors at offset 160 but got %d", offset)
+	}
+}
+
+func TestUint128ToUint64(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    [16]byte
+		expected uint64
+	}{
+		{
+			name:     "Zero",
+			input:    [16]byte{},
+			expected: 0,
+		},
+		{
+			name:     "Little endian",
+			input:    [16]byte{0x01, 0x02},
+			expected: 0x0201,
+		},
+		{
+			name:     "Overflow",
+			input:    [16]byte{8: 0x01},
+			expected: math.MaxUint64,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := Uint128ToUint64(tc.input)
+			if result != tc.expected {
+				t.Errorf("Expected %d but got %d", tc.expected, result)
+			}
+		})
+	}
+}
@@ -21,6 +21,10 @@ func ToHostPath(path string) string {
 	return filepath.Join(HostRoot(), path)
 }
 
+// StateDir is the host directory where the agent persists state that must
+// survive agent restarts and node reboots.
+const StateDir = "/var/lib/eks-node-monitoring-agent"
+
 // Common paths
 var (
 	SystemMessagesPath = ToHostPath("/var/log/messages")
@@ -3,22 +3,375 @@ package config
 import (
 	"errors"
 	"fmt"
+	"net"
+	"net/url"
 	"os"
+	"path/filepath"
 	"regexp"
 	"slices"
 	"sort"
 	"strings"
+	"time"
+	"unicode"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/yaml"
+
+	"github.com/aws/eks-node-monitoring-agent/pkg/conditions"
 )
 
 const DefaultConfigPath = "/etc/nma/config.yaml"
 
+// RemotePluginMonitorName is the plugin name under which out-of-process
+// monitor plugins are configured.
+const RemotePluginMonitorName = "remote-plugin-monitor"
+
+// DefaultRemotePluginSocketPath is the host path of the Unix socket that
+// remote plugins connect to.
+const DefaultRemotePluginSocketPath = "/run/eks-node-monitoring-agent/plugins.sock"
+
+// CustomPluginMonitorName is the plugin name under which exec-based custom
+// checks are configured.
+const CustomPluginMonitorName = "custom-plugin-monitor"
+
+// Defaults applied to custom checks that do not set the corresponding field.
+const (
+	DefaultCustomCheckInterval       = 5 * time.Minute
+	DefaultCustomCheckTimeout        = 30 * time.Second
+	DefaultCustomCheckConcurrency    = 3
+	DefaultCustomCheckMaxOutputBytes = 512
+)
+
+// DefaultEBSLatencyThreshold is the p99 latency of an EBS volume above which
+// the storage-monitor reports degraded latency.
+const DefaultEBSLatencyThreshold = 100 * time.Millisecond
+
+// DefaultDNSProbeNames are the names the networking monitor resolves against
+// each nameserver when it has none explicitly configured.
+var DefaultDNSProbeNames = []string{"amazonaws.com"}
+
+// Defaults applied to connectivity probes that do not set the corresponding
+// field.
+const (
+	DefaultConnectivityProbeInterval         = time.Minute
+	DefaultConnectivityProbeTimeout          = 5 * time.Second
+	DefaultConnectivityProbeFailureThreshold = 3
+)
+
+// Types of connectivity probes. The apiserver, cluster-dns, imds, sts and ecr
+// probes find their target on the node, while http and tcp probes are given
+// one.
+const (
+	ProbeTypeAPIServer  = "apiserver"
+	ProbeTypeClusterDNS = "cluster-dns"
+	ProbeTypeIMDS       = "imds"
+	ProbeTypeSTS        = "sts"
+	ProbeTypeECR        = "ecr"
+	ProbeTypeHTTP       = "http"
+	ProbeTypeTCP        = "tcp"
+)
+
+var probeTypes = []string{ProbeTypeAPIServer, ProbeTypeClusterDNS, ProbeTypeIMDS, ProbeTypeSTS, ProbeTypeECR, ProbeTypeHTTP, ProbeTypeTCP}
+
+// DefaultConnectivityProbes are the probes the networking monitor runs when it
+// has none explicitly configured.
+var DefaultConnectivityProbes = []ConnectivityProbe{
+	{Name: "apiserver", Type: ProbeTypeAPIServer},
+	{Name: "cluster-dns", Type: ProbeTypeClusterDNS},
+	{Name: "imds", Type: ProbeTypeIMDS},
+}
+
+// Modes control whether the conditions a monitor reports are exported. In
+// shadow mode conditions are still evaluated, logged and counted in the
+// shadow_condition_count metric, but they never reach the node or its events.
+const (
+	ModeEnforce = "enforce"
+	ModeShadow  = "shadow"
+)
+
 // MonitorSettings holds per-monitor configuration.
 type MonitorSettings struct {
 	Enabled                      *bool    `yaml:"enabled,omitempty" json:"enabled,omitempty"`
 	AllowedIPTablesChains        []string `yaml:"allowedIPTablesChains,omitempty" json:"allowedIPTablesChains,omitempty"`
 	ExcludedInterfaceNameRegexps []string `yaml:"excludedInterfaceNameRegexps,omitempty" json:"excludedInterfaceNameRegexps,omitempty"`
+	// Mode is either enforce or shadow. Defaults to enforce.
+	Mode string `yaml:"mode,omitempty" json:"mode,omitempty"`
+	// Reasons overrides settings for individual reasons reported by the
+	// monitor, keyed by reason.
+	Reasons map[string]ReasonSettings `yaml:"reasons,omitempty" json:"reasons,omitempty"`
+	// CustomChecks, CustomCheckConcurrency and CustomCheckMaxOutputBytes are
+	// only supported by the custom-plugin-monitor.
+	CustomChecks              []CustomCheck `yaml:"customChecks,omitempty" json:"customChecks,omitempty"`
+	CustomCheckConcurrency    *int          `yaml:"customCheckConcurrency,omitempty" json:"customCheckConcurrency,omitempty"`
+	CustomCheckMaxOutputBytes *int          `yaml:"customCheckMaxOutputBytes,omitempty" json:"customCheckMaxOutputBytes,omitempty"`
+	// RemotePlugins and RemotePluginSocketPath are only supported by the
+	// remote-plugin-monitor.
+	RemotePlugins          []RemotePlugin `yaml:"remotePlugins,omitempty" json:"remotePlugins,omitempty"`
+	RemotePluginSocketPath string         `yaml:"remotePluginSocketPath,omitempty" json:"remotePluginSocketPath,omitempty"`
+	// EBSLatencyThreshold is only supported by the storage-monitor.
+	EBSLatencyThreshold *metav1.Duration `yaml:"ebsLatencyThreshold,omitempty" json:"ebsLatencyThreshold,omitempty"`
+	// DNSProbeNames is only supported by the networking monitor.
+	DNSProbeNames []string `yaml:"dnsProbeNames,omitempty" json:"dnsProbeNames,omitempty"`
+	// ConnectivityProbes is only supported by the networking monitor.
+	ConnectivityProbes []ConnectivityProbe `yaml:"connectivityProbes,omitempty" json:"connectivityProbes,omitempty"`
+}
+
+// ReasonSettings holds per-reason configuration.
+type ReasonSettings struct {
+	// Mode is either enforce or shadow, and takes precedence over the mode of
+	// the monitor.
+	Mode string `yaml:"mode" json:"mode"`
+}
+
+// CustomCheck is an executable health check that follows the
+// node-problem-detector custom plugin protocol: exit code 0 means healthy, 1
+// means a problem was found, and anything else means the state is unknown. The
+// first line of output is used as the condition message.
+type CustomCheck struct {
+	// Name identifies the check in logs.
+	Name string `yaml:"name" json:"name"`
+	// Path is the executable to run, resolved against the host root.
+	Path string   `yaml:"path" json:"path"`
+	Args []string `yaml:"args,omitempty" json:"args,omitempty"`
+	// Interval is how often the check runs. Defaults to 5m.
+	Interval *metav1.Duration `yaml:"interval,omitempty" json:"interval,omitempty"`
+	// Timeout bounds a single run. Defaults to 30s and must not exceed Interval.
+	Timeout *metav1.Duration `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+	// Reason is the PascalCase reason reported when the check finds a problem.
+	Reason string `yaml:"reason" json:"reason"`
+	// Severity is one of Info, Warning or Fatal. Defaults to Warning.
+	Severity string `yaml:"severity,omitempty" json:"severity,omitempty"`
+	// ConditionType is the node condition the problem is reported under.
+	// Defaults to KernelReady.
+	ConditionType string `yaml:"conditionType,omitempty" json:"conditionType,omitempty"`
+}
+
+// ConnectivityProbe is a synthetic probe of an endpoint that the node depends
+// on. A target is reported once its probes keep failing.
+type ConnectivityProbe struct {
+	// Name identifies the probe in conditions and metrics.
+	Name string `yaml:"name" json:"name"`
+	// Type is one of apiserver, cluster-dns, imds, sts, ecr, http or tcp.
+	Type string `yaml:"type" json:"type"`
+	// Target is the URL of http probes and the host:port of tcp probes.
+	Target string `yaml:"target,omitempty" json:"target,omitempty"`
+	// Interval is how often the probe runs. Defaults to 1m.
+	Interval *metav1.Duration `yaml:"interval,omitempty" json:"interval,omitempty"`
+	// Timeout bounds a single probe. Defaults to 5s and must not exceed
+	// Interval.
+	Timeout *metav1.Duration `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+	// FailureThreshold is the number of consecutive failed probes after which
+	// the target is reported. Defaults to 3.
+	FailureThreshold *int `yaml:"failureThreshold,omitempty" json:"failureThreshold,omitempty"`
+	// SuccessStatusCodes are the status codes of successful http probes.
+	// Defaults to any status below 500.
+	SuccessStatusCodes []int `yaml:"successStatusCodes,omitempty" json:"successStatusCodes,omitempty"`
+}
+
+// RemotePlugin declares an out-of-process monitor plugin that connects to the
+// agent over the plugin gRPC socket.
+type RemotePlugin struct {
+	// Name identifies the plugin in API calls and in the plugin registry.
+	Name string `yaml:"name" json:"name"`
+	// ConditionType is the node condition the plugin's conditions are reported
+	// under. Defaults to KernelReady.
+	ConditionType string `yaml:"conditionType,omitempty" json:"conditionType,omitempty"`
+	// Resources are the observer streams delivered to the plugin.
+	Resources []RemotePluginResource `yaml:"resources,omitempty" json:"resources,omitempty"`
+}
+
+// RemotePluginResource is an observed resource, such as a systemd unit's
+// journal, whose events are streamed to a remote plugin.
+type RemotePluginResource struct {
+	// Type is one of dmesg, file, journal or netlink.
+	Type  string   `yaml:"type" json:"type"`
+	Parts []string `yaml:"parts,omitempty" json:"parts,omitempty"`
+}
+
+// GetConditionType returns the configured condition type or the default.
+func (p RemotePlugin) GetConditionType() string {
+	if p.ConditionType == "" {
+		return string(conditions.KernelReady)
+	}
+	return p.ConditionType
+}
+
+var remotePluginNameRegexp = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+
+// remotePluginResourceParts is the number of parts each observable resource
+// type takes.
+var remotePluginResourceParts = map[string]int{
+	"dmesg":   0,
+	"file":    1,
+	"journal": 1,
+	"netlink": 0,
+}
+
+func (p RemotePlugin) validate() error {
+	if !remotePluginNameRegexp.MatchString(p.Name) {
+		return fmt.Errorf("remotePlugins entry name %q must consist of lower case alphanumeric characters or '-'", p.Name)
+	}
+	if !slices.Contains(managedConditionTypes, p.GetConditionType()) {
+		return fmt.Errorf("remotePlugins entry %q has invalid conditionType %q, must be one of %s", p.Name, p.ConditionType, strings.Join(managedConditionTypes, ", "))
+	}
+	for _, res := range p.Resources {
+		parts, ok := remotePluginResourceParts[res.Type]
+		if !ok {
+			return fmt.Errorf("remotePlugins entry %q has invalid resource type %q, must be one of dmesg, file, journal or netlink", p.Name, res.Type)
+		}
+		if len(res.Parts) != parts {
+			return fmt.Errorf("remotePlugins entry %q resource %q must have %d parts, got %d", p.Name, res.Type, parts, len(res.Parts))
+		}
+		if res.Type == "file" && !filepath.IsAbs(res.Parts[0]) {
+			return fmt.Errorf("remotePlugins entry %q file resource must have an absolute path, got %q", p.Name, res.Parts[0])
+		}
+	}
+	return nil
+}
+
+// GetInterval returns the configured interval or the default.
+func (c CustomCheck) GetInterval() time.Duration {
+	if c.Interval == nil {
+		return DefaultCustomCheckInterval
+	}
+	return c.Interval.Duration
+}
+
+// GetTimeout returns the configured timeout or the default.
+func (c CustomCheck) GetTimeout() time.Duration {
+	if c.Timeout == nil {
+		return DefaultCustomCheckTimeout
+	}
+	return c.Timeout.Duration
+}
+
+// GetSeverity returns the configured severity or the default.
+func (c CustomCheck) GetSeverity() string {
+	if c.Severity == "" {
+		return "Warning"
+	}
+	return c.Severity
+}
+
+// GetConditionType returns the configured condition type or the default.
+func (c CustomCheck) GetConditionType() string {
+	if c.ConditionType == "" {
+		return string(conditions.KernelReady)
+	}
+	return c.ConditionType
+}
+
+// GetInterval returns the configured interval or the default.
+func (p ConnectivityProbe) GetInterval() time.Duration {
+	if p.Interval == nil {
+		return DefaultConnectivityProbeInterval
+	}
+	return p.Interval.Duration
+}
+
+// GetTimeout returns the configured timeout or the default.
+func (p ConnectivityProbe) GetTimeout() time.Duration {
+	if p.Timeout == nil {
+		return DefaultConnectivityProbeTimeout
+	}
+	return p.Timeout.Duration
+}
+
+// GetFailureThreshold returns the configured failure threshold or the default.
+func (p ConnectivityProbe) GetFailureThreshold() int {
+	if p.FailureThreshold == nil {
+		return DefaultConnectivityProbeFailureThreshold
+	}
+	return *p.FailureThreshold
+}
+
+func (p ConnectivityProbe) validate() error {
+	if strings.TrimSpace(p.Name) == "" {
+		return fmt.Errorf("connectivityProbes entry must have a name")
+	}
+	if !slices.Contains(probeTypes, p.Type) {
+		return fmt.Errorf("connectivityProbes entry %q has invalid type %q, must be one of %s", p.Name, p.Type, strings.Join(probeTypes, ", "))
+	}
+	switch p.Type {
+	case ProbeTypeHTTP:
+		if u, err := url.Parse(p.Target); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("connectivityProbes entry %q must have an http or https URL target, got %q", p.Name, p.Target)
+		}
+	case ProbeTypeTCP:
+		if _, port, err := net.SplitHostPort(p.Target); err != nil || port == "" {
+			return fmt.Errorf("connectivityProbes entry %q must have a host:port target, got %q", p.Name, p.Target)
+		}
+	default:
+		if p.Target != "" {
+			return fmt.Errorf("connectivityProbes entry %q of type %q must not have a target", p.Name, p.Type)
+		}
+	}
+	if len(p.SuccessStatusCodes) > 0 && p.Type != ProbeTypeHTTP {
+		return fmt.Errorf("connectivityProbes entry %q successStatusCodes are only supported by http probes", p.Name)
+	}
+	for _, code := range p.SuccessStatusCodes {
+		if code < 100 || code > 599 {
+			return fmt.Errorf("connectivityProbes entry %q has invalid status code %d", p.Name, code)
+		}
+	}
+	if p.GetInterval() <= 0 || p.GetTimeout() <= 0 {
+		return fmt.Errorf("connectivityProbes entry %q must have a positive interval and timeout", p.Name)
+	}
+	if p.GetTimeout() > p.GetInterval() {
+		return fmt.Errorf("connectivityProbes entry %q timeout %s must not exceed its interval %s", p.Name, p.GetTimeout(), p.GetInterval())
+	}
+	if p.GetFailureThreshold() < 1 {
+		return fmt.Errorf("connectivityProbes entry %q failureThreshold must be at least 1", p.Name)
+	}
+	return nil
+}
+
+// managedConditionTypes are the node conditions that custom checks and remote
+// plugins may report under. These are the conditions managed by the agent.
+var managedConditionTypes = []string{
+	string(conditions.KernelReady),
+	string(conditions.NetworkingReady),
+	string(conditions.StorageReady),
+	string(conditions.ContainerRuntimeReady),
+	string(conditions.AcceleratedHardwareReady),
+}
+
+var reasonRegexp = regexp.MustCompile(`^[A-Z][A-Za-z0-9]*$`)
+
+func (c CustomCheck) validate() error {
+	if strings.TrimSpace(c.Name) == "" {
+		return fmt.Errorf("customChecks entry must have a name")
+	}
+	if !filepath.IsAbs(c.Path) {
+		return fmt.Errorf("customChecks entry %q must have an absolute path, got %q", c.Name, c.Path)
+	}
+	if !reasonRegexp.MatchString(c.Reason) {
+		return fmt.Errorf("customChecks entry %q must have a PascalCase reason, got %q", c.Name, c.Reason)
+	}
+	switch c.GetSeverity() {
+	case "Info", "Warning", "Fatal":
+	default:
+		return fmt.Errorf("customChecks entry %q has invalid severity %q, must be one of Info, Warning or Fatal", c.Name, c.Severity)
+	}
+	if !slices.Contains(managedConditionTypes, c.GetConditionType()) {
+		return fmt.Errorf("customChecks entry %q has invalid conditionType %q, must be one of %s", c.Name, c.ConditionType, strings.Join(managedConditionTypes, ", "))
+	}
+	if c.GetInterval() <= 0 || c.GetTimeout() <= 0 {
+		return fmt.Errorf("customChecks entry %q must have a positive interval and timeout", c.Name)
+	}
+	if c.GetTimeout() > c.GetInterval() {
+		return fmt.Errorf("customChecks entry %q timeout %s must not exceed its interval %s", c.Name, c.GetTimeout(), c.GetInterval())
+	}
+	return nil
+}
+
+func validateMode(mode string) error {
+	switch mode {
+	case "", ModeEnforce, ModeShadow:
+		return nil
+	default:
+		return fmt.Errorf("invalid mode %q, must be %s or %s", mode, ModeEnforce, ModeShadow)
+	}
 }
 
 // IsEnabled returns true if the monitor is enabled.
@@ -49,6 +402,41 @@ func (mc *MonitorConfig) IsMonitorEnabled(pluginName string) bool {
 	return settings.IsEnabled()
 }
 
+// IsShadowed reports whether conditions with the given reason from a plugin
+// are in shadow mode. A mode set for the reason takes precedence over the mode
+// set for the plugin.
+func (mc *MonitorConfig) IsShadowed(pluginName string, reason string) bool {
+	if mc == nil || mc.Monitors == nil {
+		return false
+	}
+	settings := mc.Monitors[pluginName]
+	if reasonSettings, ok := settings.Reasons[reason]; ok {
+		return reasonSettings.Mode == ModeShadow
+	}
+	return settings.Mode == ModeShadow
+}
+
+// HasShadowMode reports whether any conditions from a plugin may be in shadow
+// mode.
+func (mc *MonitorConfig) HasShadowMode(pluginName string) bool {
+	if mc == nil || mc.Monitors == nil {
+		return false
+	}
+	settings := mc.Monitors[pluginName]
+	if settings.Mode == ModeShadow {
+		return true
+	}
+	for _, reasonSettings := range settings.Reasons {
+		if reasonSettings.Mode == ModeShadow {
+			return true
+		}
+	}
+	return false
+}
+
+// nftablesFamilies are the address families of nftables tables.
+var nftablesFamilies = []string{"ip", "ip6", "inet", "arp", "bridge", "netdev"}
+
 // GetAllowedIPTablesChains returns the allowed iptables chains
 // configured for the networking monitor.
 func (mc *MonitorConfig) GetAllowedIPTablesChains() []string {
@@ -100,6 +488,82 @@ func (mc *MonitorConfig) GetExcludedInterfaceNameRegexps() []string {
 	return settings.ExcludedInterfaceNameRegexps
 }
 
+// GetCustomChecks returns the exec-based checks configured for the
+// custom-plugin-monitor.
+func (mc *MonitorConfig) GetCustomChecks() []CustomCheck {
+	if mc == nil || mc.Monitors == nil {
+		return nil
+	}
+	return mc.Monitors[CustomPluginMonitorName].CustomChecks
+}
+
+// GetCustomCheckLimits returns the maximum number of custom checks allowed to
+// run at once and the maximum number of output bytes kept from each run,
+// falling back to the defaults when they are not configured.
+func (mc *MonitorConfig) GetCustomCheckLimits() (concurrency int, maxOutputBytes int) {
+	concurrency, maxOutputBytes = DefaultCustomCheckConcurrency, DefaultCustomCheckMaxOutputBytes
+	if mc == nil || mc.Monitors == nil {
+		return concurrency, maxOutputBytes
+	}
+	settings := mc.Monitors[CustomPluginMonitorName]
+	if settings.CustomCheckConcurrency != nil {
+		concurrency = *settings.CustomCheckConcurrency
+	}
+	if settings.CustomCheckMaxOutputBytes != nil {
+		maxOutputBytes = *settings.CustomCheckMaxOutputBytes
+	}
+	return concurrency, maxOutputBytes
+}
+
+// GetRemotePlugins returns the out-of-process plugins configured for the
+// remote-plugin-monitor.
+func (mc *MonitorConfig) GetRemotePlugins() []RemotePlugin {
+	if mc == nil || mc.Monitors == nil {
+		return nil
+	}
+	return mc.Monitors[RemotePluginMonitorName].RemotePlugins
+}
+
+// GetRemotePluginSocketPath returns the host path of the remote plugin socket,
+// falling back to the default when it is not configured.
+func (mc *MonitorConfig) GetRemotePluginSocketPath() string {
+	if mc == nil || mc.Monitors == nil || mc.Monitors[RemotePluginMonitorName].RemotePluginSocketPath == "" {
+		return DefaultRemotePluginSocketPath
+	}
+	return mc.Monitors[RemotePluginMonitorName].RemotePluginSocketPath
+}
+
+// GetEBSLatencyThreshold returns the p99 latency of an EBS volume above which
+// the storage-monitor reports degraded latency, falling back to the default
+// when it is not configured.
+func (mc *MonitorConfig) GetEBSLatencyThreshold() time.Duration {
+	if mc == nil || mc.Monitors == nil || mc.Monitors["storage-monitor"].EBSLatencyThreshold == nil {
+		return DefaultEBSLatencyThreshold
+	}
+	return mc.Monitors["storage-monitor"].EBSLatencyThreshold.Duration
+}
+
+// GetDNSProbeNames returns the names the networking monitor resolves against
+// each nameserver, falling back to DefaultDNSProbeNames when it is not
+// configured.
+func (mc *MonitorConfig) GetDNSProbeNames() []string {
+	if mc == nil || mc.Monitors == nil || len(mc.Monitors["networking"].DNSProbeNames) == 0 {
+		return DefaultDNSProbeNames
+	}
+	return mc.Monitors["networking"].DNSProbeNames
+}
+
+// GetConnectivityProbes returns the connectivity probes configured for the
+// networking monitor. When the networking monitor has no connectivityProbes
+// explicitly set, DefaultConnectivityProbes is returned. An explicitly
+// configured empty list disables the default.
+func (mc *MonitorConfig) GetConnectivityProbes() []ConnectivityProbe {
+	if mc == nil || mc.Monitors == nil || mc.Monitors["networking"].ConnectivityProbes == nil {
+		return DefaultConnectivityProbes
+	}
+	return mc.Monitors["networking"].ConnectivityProbes
+}
+
 // KnownPluginNames is the set of valid plugin names for validation.
 var KnownPluginNames = []string{
 	"kernel-monitor",
@@ -108,6 +572,8 @@ var KnownPluginNames = []string{
 	"nvidia",
 	"neuron",
 	"runtime",
+	CustomPluginMonitorName,
+	RemotePluginMonitorName,
 }
 
 // Validate checks that all keys in Monitors are known plugin names.
@@ -126,6 +592,20 @@ func (mc *MonitorConfig) Validate() error {
 		return fmt.Errorf("unknown monitor plugin name(s): %s", strings.Join(unknown, ", "))
 	}
 	for name, settings := range mc.Monitors {
+		if err := validateMode(settings.Mode); err != nil {
+			return fmt.Errorf("monitor %q: %w", name, err)
+		}
+		for reason, reasonSettings := range settings.Reasons {
+			if !reasonRegexp.MatchString(reason) {
+				return fmt.Errorf("monitor %q reasons entry %q must be a PascalCase reason", name, reason)
+			}
+			if reasonSettings.Mode == "" {
+				return fmt.Errorf("monitor %q reasons entry %q must set a mode", name, reason)
+			}
+			if err := validateMode(reasonSettings.Mode); err != nil {
+				return fmt.Errorf("monitor %q reasons entry %q: %w", name, reason, err)
+			}
+		}
 		if len(settings.AllowedIPTablesChains) > 0 {
 			if name != "networking" {
 				return fmt.Errorf("allowedIPTablesChains is only supported by the networking monitor, not %q", name)
@@ -134,9 +614,11 @@ func (mc *MonitorConfig) Validate() error {
 				if strings.TrimSpace(chain) != chain {
 					return fmt.Errorf("allowedIPTablesChains entry %q must not have leading or trailing whitespace", chain)
 				}
-				table, chainName, ok := strings.Cut(chain, "/")
-				if !ok || strings.Count(chain, "/") != 1 || strings.TrimSpace(table) == "" || strings.TrimSpace(chainName) == "" {
-					return fmt.Errorf("allowedIPTablesChains entry %q must use \"table/chain\" format with non-empty table and chain (e.g. \"filter/MY-CUSTOM-CHAIN\")", chain)
+				// iptables chains are "table/chain", nftables chains are
+				// "family/table/chain".
+				parts := strings.Split(chain, "/")
+				if (len(parts) != 2 && (len(parts) != 3 || !slices.Contains(nftablesFamilies, parts[0]))) || slices.ContainsFunc(parts, func(part string) bool { return strings.TrimSpace(part) == "" }) {
+					return fmt.Errorf("allowedIPTablesChains entry %q must use \"table/chain\" format with non-empty table and chain (e.g. \"filter/MY-CUSTOM-CHAIN\"), or \"family/table/chain\" format for nftables (e.g. \"inet/my-table/my-chain\")", chain)
 				}
 			}
 		}
@@ -153,10 +635,97 @@ func (mc *MonitorConfig) Validate() error {
 				}
 			}
 		}
+		if len(settings.CustomChecks) > 0 || settings.CustomCheckConcurrency != nil || settings.CustomCheckMaxOutputBytes != nil {
+			if name != CustomPluginMonitorName {
+				return fmt.Errorf("customChecks settings are only supported by the %s, not %q", CustomPluginMonitorName, name)
+			}
+			if settings.CustomCheckConcurrency != nil && *settings.CustomCheckConcurrency < 1 {
+				return fmt.Errorf("customCheckConcurrency must be at least 1")
+			}
+			if settings.CustomCheckMaxOutputBytes != nil && *settings.CustomCheckMaxOutputBytes < 1 {
+				return fmt.Errorf("customCheckMaxOutputBytes must be at least 1")
+			}
+			checkNames := map[string]bool{}
+			for _, check := range settings.CustomChecks {
+				if err := check.validate(); err != nil {
+					return err
+				}
+				if checkNames[check.Name] {
+					return fmt.Errorf("customChecks entry name %q is not unique", check.Name)
+				}
+				checkNames[check.Name] = true
+			}
+		}
+		if len(settings.RemotePlugins) > 0 || settings.RemotePluginSocketPath != "" {
+			if name != RemotePluginMonitorName {
+				return fmt.Errorf("remotePlugins settings are only supported by the %s, not %q", RemotePluginMonitorName, name)
+			}
+			if settings.RemotePluginSocketPath != "" && !filepath.IsAbs(settings.RemotePluginSocketPath) {
+				return fmt.Errorf("remotePluginSocketPath must be an absolute path, got %q", settings.RemotePluginSocketPath)
+			}
+			pluginNames := map[string]bool{}
+			for _, plugin := range settings.RemotePlugins {
+				if err := plugin.validate(); err != nil {
+					return err
+				}
+				if pluginNames[plugin.Name] {
+					return fmt.Errorf("remotePlugins entry name %q is not unique", plugin.Name)
+				}
+				pluginNames[plugin.Name] = true
+			}
+		}
+		if settings.EBSLatencyThreshold != nil {
+			if name != "storage-monitor" {
+				return fmt.Errorf("ebsLatencyThreshold is only supported by the storage-monitor, not %q", name)
+			}
+			if settings.EBSLatencyThreshold.Duration <= 0 {
+				return fmt.Errorf("ebsLatencyThreshold must be positive, got %s", settings.EBSLatencyThreshold.Duration)
+			}
+		}
+		if len(settings.DNSProbeNames) > 0 {
+			if name != "networking" {
+				return fmt.Errorf("dnsProbeNames is only supported by the networking monitor, not %q", name)
+			}
+			for _, probeName := range settings.DNSProbeNames {
+				if !validDNSName(probeName) {
+					return fmt.Errorf("dnsProbeNames entry %q is not a valid DNS name", probeName)
+				}
+			}
+		}
+		if len(settings.ConnectivityProbes) > 0 {
+			if name != "networking" {
+				return fmt.Errorf("connectivityProbes is only supported by the networking monitor, not %q", name)
+			}
+			probeNames := map[string]bool{}
+			for _, probe := range settings.ConnectivityProbes {
+				if err := probe.validate(); err != nil {
+					return err
+				}
+				if probeNames[probe.Name] {
+					return fmt.Errorf("connectivityProbes entry name %q is not unique", probe.Name)
+				}
+				probeNames[probe.Name] = true
+			}
+		}
 	}
 	return nil
 }
 
+// validDNSName reports whether the name is a DNS name of non-empty labels of
+// at most 63 characters without whitespace, optionally fully qualified.
+func validDNSName(name string) bool {
+	name = strings.TrimSuffix(name, ".")
+	if name == "" || len(name) > 253 || strings.ContainsFunc(name, unicode.IsSpace) {
+		return false
+	}
+	for _, label := range strings.Split(name, ".") {
+		if label == "" || len(label) > 63 {
+			return false
+		}
+	}
+	return true
+}
+
 // LoadMonitorConfig reads the config file at the given path.
 // Returns a default (all-enabled) config if the file does not exist.
 // Returns an error if the file exists but contains invalid YAML or unknown plugin names.
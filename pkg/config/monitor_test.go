@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -199,6 +200,32 @@ func TestLoadMonitorConfig_AllowedIPTablesChains(t *testing.T) {
 	assert.Equal(t, []string{"filter/MY-CUSTOM-CHAIN"}, cfg.GetAllowedIPTablesChains())
 }
 
+func TestLoadMonitorConfig_AllowedNFTablesChains(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "config.yaml")
+
+	content := []byte(`monitors:
+  networking:
+    allowedIPTablesChains:
+      - "inet/my-firewall/input"
+`)
+	require.NoError(t, os.WriteFile(cfgPath, content, 0644))
+
+	cfg, _, err := config.LoadMonitorConfig(cfgPath)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"inet/my-firewall/input"}, cfg.GetAllowedIPTablesChains())
+
+	content = []byte(`monitors:
+  networking:
+    allowedIPTablesChains:
+      - "inet6/my-firewall/input"
+`)
+	require.NoError(t, os.WriteFile(cfgPath, content, 0644))
+
+	_, _, err = config.LoadMonitorConfig(cfgPath)
+	assert.ErrorContains(t, err, "\"family/table/chain\" format")
+}
+
 func TestLoadMonitorConfig_EmptyChainRejected(t *testing.T) {
 	dir := t.TempDir()
 	cfgPath := filepath.Join(dir, "config.yaml")
@@ -435,3 +462,496 @@ func TestLoadMonitorConfig_StrictUnmarshalRejectsUnknownFields(t *testing.T) {
 	assert.Nil(t, cfg)
 	assert.Contains(t, err.Error(), "parsing monitor config")
 }
+
+func TestLoadMonitorConfig_CustomChecks(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "config.yaml")
+
+	content := []byte(`monitors:
+  custom-plugin-monitor:
+    customCheckConcurrency: 5
+    customChecks:
+      - name: ntp
+        path: /usr/local/bin/check-ntp
+        args: ["--strict"]
+        interval: 1m
+        timeout: 10s
+        reason: ClockSkewDetected
+        severity: Fatal
+        conditionType: NetworkingReady
+      - name: lvm
+        path: /usr/local/bin/check-lvm
+        reason: LVMDegraded
+`)
+	require.NoError(t, os.WriteFile(cfgPath, content, 0644))
+
+	cfg, found, err := config.LoadMonitorConfig(cfgPath)
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+	assert.True(t, found)
+
+	checks := cfg.GetCustomChecks()
+	require.Len(t, checks, 2)
+
+	assert.Equal(t, "ntp", checks[0].Name)
+	assert.Equal(t, []string{"--strict"}, checks[0].Args)
+	assert.Equal(t, time.Minute, checks[0].GetInterval())
+	assert.Equal(t, 10*time.Second, checks[0].GetTimeout())
+	assert.Equal(t, "Fatal", checks[0].GetSeverity())
+	assert.Equal(t, "NetworkingReady", checks[0].GetConditionType())
+
+	assert.Equal(t, config.DefaultCustomCheckInterval, checks[1].GetInterval())
+	assert.Equal(t, config.DefaultCustomCheckTimeout, checks[1].GetTimeout())
+	assert.Equal(t, "Warning", checks[1].GetSeverity())
+	assert.Equal(t, "KernelReady", checks[1].GetConditionType())
+
+	concurrency, maxOutputBytes := cfg.GetCustomCheckLimits()
+	assert.Equal(t, 5, concurrency)
+	assert.Equal(t, config.DefaultCustomCheckMaxOutputBytes, maxOutputBytes)
+}
+
+func TestGetCustomChecks_NilConfig(t *testing.T) {
+	var cfg *config.MonitorConfig
+	assert.Nil(t, cfg.GetCustomChecks())
+	concurrency, maxOutputBytes := cfg.GetCustomCheckLimits()
+	assert.Equal(t, config.DefaultCustomCheckConcurrency, concurrency)
+	assert.Equal(t, config.DefaultCustomCheckMaxOutputBytes, maxOutputBytes)
+}
+
+func TestLoadMonitorConfig_InvalidCustomChecksRejected(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		content string
+		errMsg  string
+	}{
+		{
+			name: "RelativePath",
+			content: `monitors:
+  custom-plugin-monitor:
+    customChecks:
+      - name: ntp
+        path: check-ntp
+        reason: ClockSkewDetected
+`,
+			errMsg: "must have an absolute path",
+		},
+		{
+			name: "MissingReason",
+			content: `monitors:
+  custom-plugin-monitor:
+    customChecks:
+      - name: ntp
+        path: /usr/local/bin/check-ntp
+`,
+			errMsg: "must have a PascalCase reason",
+		},
+		{
+			name: "InvalidSeverity",
+			content: `monitors:
+  custom-plugin-monitor:
+    customChecks:
+      - name: ntp
+        path: /usr/local/bin/check-ntp
+        reason: ClockSkewDetected
+        severity: Critical
+`,
+			errMsg: "invalid severity",
+		},
+		{
+			name: "InvalidConditionType",
+			content: `monitors:
+  custom-plugin-monitor:
+    customChecks:
+      - name: ntp
+        path: /usr/local/bin/check-ntp
+        reason: ClockSkewDetected
+        conditionType: Ready
+`,
+			errMsg: "invalid conditionType",
+		},
+		{
+			name: "TimeoutExceedsInterval",
+			content: `monitors:
+  custom-plugin-monitor:
+    customChecks:
+      - name: ntp
+        path: /usr/local/bin/check-ntp
+        reason: ClockSkewDetected
+        interval: 10s
+        timeout: 1m
+`,
+			errMsg: "must not exceed its interval",
+		},
+		{
+			name: "DuplicateName",
+			content: `monitors:
+  custom-plugin-monitor:
+    customChecks:
+      - name: ntp
+        path: /usr/local/bin/check-ntp
+        reason: ClockSkewDetected
+      - name: ntp
+        path: /usr/local/bin/check-ntp
+        reason: ClockSkewDetected
+`,
+			errMsg: "is not unique",
+		},
+		{
+			name: "ZeroConcurrency",
+			content: `monitors:
+  custom-plugin-monitor:
+    customCheckConcurrency: 0
+`,
+			errMsg: "customCheckConcurrency must be at least 1",
+		},
+		{
+			name: "WrongMonitor",
+			content: `monitors:
+  kernel-monitor:
+    customChecks:
+      - name: ntp
+        path: /usr/local/bin/check-ntp
+        reason: ClockSkewDetected
+`,
+			errMsg: "only supported by the custom-plugin-monitor",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cfgPath := filepath.Join(t.TempDir(), "config.yaml")
+			require.NoError(t, os.WriteFile(cfgPath, []byte(tc.content), 0644))
+
+			cfg, _, err := config.LoadMonitorConfig(cfgPath)
+			require.Error(t, err)
+			assert.Nil(t, cfg)
+			assert.Contains(t, err.Error(), tc.errMsg)
+		})
+	}
+}
+
+func TestLoadMonitorConfig_RemotePlugins(t *testing.T) {
+	cfgPath := filepath.Join(t.TempDir(), "config.yaml")
+
+	content := []byte(`monitors:
+  remote-plugin-monitor:
+    remotePlugins:
+      - name: vendor-csi
+        conditionType: StorageReady
+        resources:
+          - type: journal
+            parts: ["vendor-csi-node"]
+          - type: dmesg
+      - name: vendor-nic
+        resources:
+          - type: netlink
+`)
+	require.NoError(t, os.WriteFile(cfgPath, content, 0644))
+
+	cfg, found, err := config.LoadMonitorConfig(cfgPath)
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+	assert.True(t, found)
+
+	plugins := cfg.GetRemotePlugins()
+	require.Len(t, plugins, 2)
+	assert.Equal(t, "vendor-csi", plugins[0].Name)
+	assert.Equal(t, "StorageReady", plugins[0].GetConditionType())
+	assert.Equal(t, []config.RemotePluginResource{
+		{Type: "journal", Parts: []string{"vendor-csi-node"}},
+		{Type: "dmesg"},
+	}, plugins[0].Resources)
+	assert.Equal(t, "KernelReady", plugins[1].GetConditionType())
+	assert.Equal(t, []config.RemotePluginResource{{Type: "netlink"}}, plugins[1].Resources)
+	assert.Equal(t, config.DefaultRemotePluginSocketPath, cfg.GetRemotePluginSocketPath())
+}
+
+func TestLoadMonitorConfig_InvalidRemotePluginsRejected(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		content string
+		errMsg  string
+	}{
+		{
+			name: "InvalidName",
+			content: `monitors:
+  remote-plugin-monitor:
+    remotePlugins:
+      - name: Vendor_CSI
+`,
+			errMsg: "must consist of lower case alphanumeric characters",
+		},
+		{
+			name: "DuplicateName",
+			content: `monitors:
+  remote-plugin-monitor:
+    remotePlugins:
+      - name: vendor-csi
+      - name: vendor-csi
+`,
+			errMsg: "is not unique",
+		},
+		{
+			name: "InvalidResourceType",
+			content: `monitors:
+  remote-plugin-monitor:
+    remotePlugins:
+      - name: vendor-csi
+        resources:
+          - type: syslog
+`,
+			errMsg: "invalid resource type",
+		},
+		{
+			name: "MissingResourcePart",
+			content: `monitors:
+  remote-plugin-monitor:
+    remotePlugins:
+      - name: vendor-csi
+        resources:
+          - type: journal
+`,
+			errMsg: "must have 1 parts, got 0",
+		},
+		{
+			name: "RelativeSocketPath",
+			content: `monitors:
+  remote-plugin-monitor:
+    remotePluginSocketPath: plugins.sock
+`,
+			errMsg: "remotePluginSocketPath must be an absolute path",
+		},
+		{
+			name: "WrongMonitor",
+			content: `monitors:
+  networking:
+    remotePlugins:
+      - name: vendor-csi
+`,
+			errMsg: "only supported by the remote-plugin-monitor",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cfgPath := filepath.Join(t.TempDir(), "config.yaml")
+			require.NoError(t, os.WriteFile(cfgPath, []byte(tc.content), 0644))
+
+			cfg, _, err := config.LoadMonitorConfig(cfgPath)
+			require.Error(t, err)
+			assert.Nil(t, cfg)
+			assert.Contains(t, err.Error(), tc.errMsg)
+		})
+	}
+}
+
+func TestLoadMonitorConfig_EBSLatencyThreshold(t *testing.T) {
+	assert.Equal(t, config.DefaultEBSLatencyThreshold, (*config.MonitorConfig)(nil).GetEBSLatencyThreshold())
+
+	cfgPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(cfgPath, []byte(`monitors:
+  storage-monitor:
+    ebsLatencyThreshold: 250ms
+`), 0644))
+	cfg, _, err := config.LoadMonitorConfig(cfgPath)
+	require.NoError(t, err)
+	assert.Equal(t, 250*time.Millisecond, cfg.GetEBSLatencyThreshold())
+
+	for content, errMsg := range map[string]string{
+		"monitors:\n  storage-monitor:\n    ebsLatencyThreshold: 0s\n":   "ebsLatencyThreshold must be positive",
+		"monitors:\n  kernel-monitor:\n    ebsLatencyThreshold: 100ms\n": "only supported by the storage-monitor",
+	} {
+		require.NoError(t, os.WriteFile(cfgPath, []byte(content), 0644))
+		_, _, err := config.LoadMonitorConfig(cfgPath)
+		assert.ErrorContains(t, err, errMsg)
+	}
+}
+
+func TestLoadMonitorConfig_DNSProbeNames(t *testing.T) {
+	assert.Equal(t, config.DefaultDNSProbeNames, (*config.MonitorConfig)(nil).GetDNSProbeNames())
+
+	cfgPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(cfgPath, []byte(`monitors:
+  networking:
+    dnsProbeNames:
+      - example.com
+      - sts.us-west-2.amazonaws.com.
+`), 0644))
+	cfg, _, err := config.LoadMonitorConfig(cfgPath)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"example.com", "sts.us-west-2.amazonaws.com."}, cfg.GetDNSProbeNames())
+
+	for content, errMsg := range map[string]string{
+		"monitors:\n  networking:\n    dnsProbeNames: [\"\"]\n":                 "is not a valid DNS name",
+		"monitors:\n  networking:\n    dnsProbeNames: [\"example..com\"]\n":     "is not a valid DNS name",
+		"monitors:\n  networking:\n    dnsProbeNames: [\" example.com\"]\n":     "is not a valid DNS name",
+		"monitors:\n  storage-monitor:\n    dnsProbeNames: [\"example.com\"]\n": "only supported by the networking monitor",
+	} {
+		require.NoError(t, os.WriteFile(cfgPath, []byte(content), 0644))
+		_, _, err := config.LoadMonitorConfig(cfgPath)
+		assert.ErrorContains(t, err, errMsg)
+	}
+}
+
+func TestLoadMonitorConfig_ConnectivityProbes(t *testing.T) {
+	assert.Equal(t, config.DefaultConnectivityProbes, (*config.MonitorConfig)(nil).GetConnectivityProbes())
+
+	cfgPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(cfgPath, []byte(`monitors:
+  networking:
+    connectivityProbes:
+      - name: apiserver
+        type: apiserver
+        interval: 30s
+        timeout: 2s
+        failureThreshold: 5
+      - name: registry
+        type: http
+        target: https://registry.example.com/v2/
+        successStatusCodes: [200, 401]
+      - name: database
+        type: tcp
+        target: db.example.com:5432
+`), 0644))
+	cfg, _, err := config.LoadMonitorConfig(cfgPath)
+	require.NoError(t, err)
+	probes := cfg.GetConnectivityProbes()
+	require.Len(t, probes, 3)
+
+	assert.Equal(t, 30*time.Second, probes[0].GetInterval())
+	assert.Equal(t, 2*time.Second, probes[0].GetTimeout())
+	assert.Equal(t, 5, probes[0].GetFailureThreshold())
+	assert.Equal(t, []int{200, 401}, probes[1].SuccessStatusCodes)
+	assert.Equal(t, config.DefaultConnectivityProbeInterval, probes[2].GetInterval())
+	assert.Equal(t, config.DefaultConnectivityProbeTimeout, probes[2].GetTimeout())
+	assert.Equal(t, config.DefaultConnectivityProbeFailureThreshold, probes[2].GetFailureThreshold())
+
+	// an empty list disables the default probes.
+	require.NoError(t, os.WriteFile(cfgPath, []byte("monitors:\n  networking:\n    connectivityProbes: []\n"), 0644))
+	cfg, _, err = config.LoadMonitorConfig(cfgPath)
+	require.NoError(t, err)
+	assert.Empty(t, cfg.GetConnectivityProbes())
+}
+
+func TestLoadMonitorConfig_InvalidConnectivityProbesRejected(t *testing.T) {
+	cfgPath := filepath.Join(t.TempDir(), "config.yaml")
+	for content, errMsg := range map[string]string{
+		"monitors:\n  networking:\n    connectivityProbes: [{type: imds}]\n":                                                      "must have a name",
+		"monitors:\n  networking:\n    connectivityProbes: [{name: a, type: icmp}]\n":                                             "has invalid type",
+		"monitors:\n  networking:\n    connectivityProbes: [{name: a, type: http, target: example.com}]\n":                        "must have an http or https URL target",
+		"monitors:\n  networking:\n    connectivityProbes: [{name: a, type: tcp, target: example.com}]\n":                         "must have a host:port target",
+		"monitors:\n  networking:\n    connectivityProbes: [{name: a, type: imds, target: example.com}]\n":                        "must not have a target",
+		"monitors:\n  networking:\n    connectivityProbes: [{name: a, type: imds, successStatusCodes: [200]}]\n":                  "only supported by http probes",
+		"monitors:\n  networking:\n    connectivityProbes: [{name: a, type: http, target: http://a, successStatusCodes: [99]}]\n": "invalid status code",
+		"monitors:\n  networking:\n    connectivityProbes: [{name: a, type: imds, interval: 1s, timeout: 2s}]\n":                  "must not exceed its interval",
+		"monitors:\n  networking:\n    connectivityProbes: [{name: a, type: imds, failureThreshold: 0}]\n":                        "failureThreshold must be at least 1",
+		"monitors:\n  networking:\n    connectivityProbes: [{name: a, type: imds}, {name: a, type: sts}]\n":                       "is not unique",
+		"monitors:\n  storage-monitor:\n    connectivityProbes: [{name: a, type: imds}]\n":                                        "only supported by the networking monitor",
+	} {
+		require.NoError(t, os.WriteFile(cfgPath, []byte(content), 0644))
+		_, _, err := config.LoadMonitorConfig(cfgPath)
+		assert.ErrorContains(t, err, errMsg, content)
+	}
+}
+
+func TestIsShadowed(t *testing.T) {
+	cfg := &config.MonitorConfig{
+		Monitors: map[string]config.MonitorSettings{
+			"kernel-monitor": {Mode: config.ModeShadow, Reasons: map[string]config.ReasonSettings{
+				"ForkFailedOutOfPIDs": {Mode: config.ModeEnforce},
+			}},
+			"networking": {Reasons: map[string]config.ReasonSettings{
+				"UnexpectedRejectRule": {Mode: config.ModeShadow},
+			}},
+			"storage-monitor": {Mode: config.ModeEnforce},
+		},
+	}
+
+	assert.True(t, cfg.IsShadowed("kernel-monitor", "KernelBug"))
+	assert.False(t, cfg.IsShadowed("kernel-monitor", "ForkFailedOutOfPIDs"), "reason mode takes precedence")
+	assert.True(t, cfg.IsShadowed("networking", "UnexpectedRejectRule"))
+	assert.False(t, cfg.IsShadowed("networking", "InterfaceNotUp"))
+	assert.False(t, cfg.IsShadowed("storage-monitor", "IODelays"))
+	assert.False(t, cfg.IsShadowed("nvidia", "NvidiaXID13Warning"))
+
+	assert.True(t, cfg.HasShadowMode("kernel-monitor"))
+	assert.True(t, cfg.HasShadowMode("networking"))
+	assert.False(t, cfg.HasShadowMode("storage-monitor"))
+	assert.False(t, cfg.HasShadowMode("nvidia"))
+
+	var nilCfg *config.MonitorConfig
+	assert.False(t, nilCfg.IsShadowed("kernel-monitor", "KernelBug"))
+	assert.False(t, nilCfg.HasShadowMode("kernel-monitor"))
+}
+
+func TestLoadMonitorConfig_ShadowMode(t *testing.T) {
+	cfgPath := filepath.Join(t.TempDir(), "config.yaml")
+
+	content := []byte(`monitors:
+  kernel-monitor:
+    mode: shadow
+  networking:
+    reasons:
+      UnexpectedRejectRule:
+        mode: shadow
+`)
+	require.NoError(t, os.WriteFile(cfgPath, content, 0644))
+
+	cfg, _, err := config.LoadMonitorConfig(cfgPath)
+	require.NoError(t, err)
+	assert.True(t, cfg.IsMonitorEnabled("kernel-monitor"))
+	assert.True(t, cfg.IsShadowed("kernel-monitor", "KernelBug"))
+	assert.True(t, cfg.IsShadowed("networking", "UnexpectedRejectRule"))
+}
+
+func TestLoadMonitorConfig_InvalidModeRejected(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		content string
+		errMsg  string
+	}{
+		{
+			name: "InvalidMonitorMode",
+			content: `monitors:
+  kernel-monitor:
+    mode: dryrun
+`,
+			errMsg: `invalid mode "dryrun"`,
+		},
+		{
+			name: "InvalidReasonMode",
+			content: `monitors:
+  networking:
+    reasons:
+      UnexpectedRejectRule:
+        mode: dryrun
+`,
+			errMsg: `invalid mode "dryrun"`,
+		},
+		{
+			name: "MissingReasonMode",
+			content: `monitors:
+  networking:
+    reasons:
+      UnexpectedRejectRule: {}
+`,
+			errMsg: "must set a mode",
+		},
+		{
+			name: "InvalidReason",
+			content: `monitors:
+  networking:
+    reasons:
+      unexpected-reject-rule:
+        mode: shadow
+`,
+			errMsg: "must be a PascalCase reason",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cfgPath := filepath.Join(t.TempDir(), "config.yaml")
+			require.NoError(t, os.WriteFile(cfgPath, []byte(tc.content), 0644))
+
+			cfg, _, err := config.LoadMonitorConfig(cfgPath)
+			require.Error(t, err)
+			assert.Nil(t, cfg)
+			assert.Contains(t, err.Error(), tc.errMsg)
+		})
+	}
+}
@@ -25,12 +25,17 @@ var (
 		prometheus.GaugeOpts{Name: "fatal_condition_gauge"},
 		[]string{"type"},
 	)
+	shadowConditionCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{Name: "shadow_condition_count"},
+		[]string{"severity", "reason", "monitor"},
+	)
 )
 
 func init() {
 	metrics.Registry.MustRegister(
 		conditionCount,
 		conditionTypeGauge,
+		shadowConditionCount,
 	)
 }
 
@@ -40,11 +45,16 @@ type MonitorManager struct {
 	monitors          map[string]monitor.Monitor
 	conditionTypeMap  map[string]corev1.NodeConditionType
 	conditionCountMap map[string]int64
+	shadowFuncs       map[string]ShadowFunc
 	observers         map[string]observer.Observer
 	notifyChan        chan notification
 	exporter          Exporter
 }
 
+// ShadowFunc reports whether conditions with the given reason are in shadow
+// mode. Shadowed conditions are evaluated and logged but never exported.
+type ShadowFunc func(reason string) bool
+
 type notification struct {
 	monitorName string
 	condition   monitor.Condition
@@ -57,6 +67,7 @@ func NewMonitorManager(nodeName string, exporter Exporter) *MonitorManager {
 		monitors:          make(map[string]monitor.Monitor),
 		conditionTypeMap:  make(map[string]corev1.NodeConditionType),
 		conditionCountMap: make(map[string]int64),
+		shadowFuncs:       make(map[string]ShadowFunc),
 		observers:         make(map[string]observer.Observer),
 		notifyChan:        make(chan notification, 100),
 		exporter:          exporter,
@@ -70,6 +81,12 @@ func (m *MonitorManager) Register(ctx context.Context, mon monitor.Monitor, cond
 	return mon.Register(ctx, makeManagerWrapper(m, mon))
 }
 
+// SetShadowMode puts the conditions from the named monitor for which
+// isShadowed returns true into shadow mode. It must be called before Start.
+func (m *MonitorManager) SetShadowMode(monitorName string, isShadowed ShadowFunc) {
+	m.shadowFuncs[monitorName] = isShadowed
+}
+
 // Start starts all observers and begins processing notifications
 func (m *MonitorManager) Start(ctx context.Context) error {
 	logger := log.FromContext(ctx)
@@ -124,8 +141,11 @@ func (m *MonitorManager) runLoop(ctx context.Context) error {
 func (m *MonitorManager) exportCondition(ctx context.Context, monitorName string, condition monitor.Condition) error {
 	logger := log.FromContext(ctx).WithValues("source", monitorName, "condition", condition)
 
-	// track condition metrics
-	conditionCount.WithLabelValues(string(condition.Severity), condition.Reason).Add(1)
+	shadowed := m.isShadowed(monitorName, condition.Reason)
+	if !shadowed {
+		// track condition metrics
+		conditionCount.WithLabelValues(string(condition.Severity), condition.Reason).Add(1)
+	}
 
 	conditionType, ok := m.conditionTypeMap[monitorName]
 	if !ok {
@@ -141,9 +161,22 @@ func (m *MonitorManager) exportCondition(ctx context.Context, monitorName string
 	}
 	m.conditionCountMap[condition.Reason] = 0
 
+	if shadowed {
+		logger.Info("shadow mode, not sending condition to exporter")
+		shadowConditionCount.WithLabelValues(string(condition.Severity), condition.Reason, monitorName).Add(1)
+		return nil
+	}
+
 	return m.SendCondition(ctx, condition, conditionType)
 }
 
+// isShadowed reports whether a condition from the named monitor is in shadow
+// mode.
+func (m *MonitorManager) isShadowed(monitorName string, reason string) bool {
+	isShadowed, ok := m.shadowFuncs[monitorName]
+	return ok && isShadowed(reason)
+}
+
 // SendCondition sends a condition to the exporter based on severity
 func (m *MonitorManager) SendCondition(ctx context.Context, condition monitor.Condition, conditionType corev1.NodeConditionType) error {
 	log.FromContext(ctx).Info("sending condition to exporter", "condition", condition, "conditionType", conditionType)
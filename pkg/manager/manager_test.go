@@ -7,6 +7,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 
 	"github.com/aws/eks-node-monitoring-agent/api/monitor"
 	"github.com/aws/eks-node-monitoring-agent/api/monitor/resource"
@@ -144,3 +145,70 @@ func TestManager_CreateObserver(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NoError(t, mMgr.Start(ctx))
 }
+
+func TestManager_ShadowMode(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Second)
+	defer cancel()
+
+	mockMon := &mockMonitor{
+		registerFunc: func(ctx context.Context, mgr monitor.Manager) error {
+			go func() {
+				// notifications are handled in order, so the shadowed condition
+				// has been evaluated by the time the enforced one is exported.
+				mgr.Notify(ctx, monitor.Condition{
+					Reason:   "ShadowedReason",
+					Severity: monitor.SeverityFatal,
+				})
+				mgr.Notify(ctx, monitor.Condition{
+					Reason:   "EnforcedReason",
+					Severity: monitor.SeverityFatal,
+				})
+			}()
+			return nil
+		},
+	}
+	mMgr, mockExp := NewManagerWithExporterFuncs()
+	mMgr.SetShadowMode(mockMon.Name(), func(reason string) bool {
+		return reason == "ShadowedReason"
+	})
+	if err := mMgr.Register(ctx, mockMon, "MockPassed"); err != nil {
+		t.Fatal(err)
+	}
+	go mMgr.Start(ctx)
+
+	select {
+	case <-mockExp.notifyChan:
+	case <-ctx.Done():
+		t.Fatal(ctx.Err())
+	}
+	assert.Equal(t, 1.0, shadowConditionCount(t, "ShadowedReason"))
+	assert.Equal(t, 0.0, shadowConditionCount(t, "EnforcedReason"))
+
+	select {
+	case <-mockExp.notifyChan:
+		t.Fatal("expected the shadowed condition not to be exported")
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+// shadowConditionCount returns the value of the shadow_condition_count metric
+// for reason.
+func shadowConditionCount(t *testing.T, reason string) float64 {
+	families, err := metrics.Registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, family := range families {
+		if family.GetName() != "shadow_condition_count" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "reason" && label.GetValue() == reason {
+					return metric.GetCounter().GetValue()
+				}
+			}
+		}
+	}
+	return 0
+}
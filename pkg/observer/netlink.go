@@ -0,0 +1,184 @@
+//go:build linux
+
+package observer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/aws/eks-node-monitoring-agent/api/monitor/resource"
+)
+
+func init() {
+	RegisterObserverConstructor(resource.ResourceTypeNetlink, func(rp []resource.Part) (Observer, error) {
+		if l := len(rp); l != 0 {
+			return nil, fmt.Errorf("part count must be 0, but was %d", l)
+		}
+		return &netlinkObserver{}, nil
+	})
+}
+
+// netlinkResubscribeInterval is the wait before subscribing again after a
+// subscription fails, e.g. when the socket overruns.
+const netlinkResubscribeInterval = time.Second
+
+type netlinkObserver struct {
+	BaseObserver
+}
+
+func (o *netlinkObserver) Identifier() string {
+	return "netlink"
+}
+
+func (o *netlinkObserver) Init(ctx context.Context) error {
+	logger := log.FromContext(ctx)
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := o.subscribe(ctx); err != nil {
+			logger.Error(err, "error in netlink subscription")
+		}
+	}, netlinkResubscribeInterval)
+	return nil
+}
+
+// subscribe broadcasts the link and route events until the context is done or
+// either subscription fails, after which netlink closes its channel.
+func (o *netlinkObserver) subscribe(ctx context.Context) error {
+	done := make(chan struct{})
+	defer close(done)
+	// each subscription reports its errors from its own goroutine, so they are
+	// handed over on a buffered channel per subscription.
+	linkErrs, routeErrs := make(chan error, 1), make(chan error, 1)
+
+	links := make(chan netlink.LinkUpdate, 100)
+	if err := netlink.LinkSubscribeWithOptions(links, done, netlink.LinkSubscribeOptions{ErrorCallback: errorCallback(linkErrs)}); err != nil {
+		return fmt.Errorf("subscribing to links: %w", err)
+	}
+	routes := make(chan netlink.RouteUpdate, 100)
+	if err := netlink.RouteSubscribeWithOptions(routes, done, netlink.RouteSubscribeOptions{ErrorCallback: errorCallback(routeErrs)}); err != nil {
+		return fmt.Errorf("subscribing to routes: %w", err)
+	}
+	for {
+		var event *resource.NetlinkEvent
+		select {
+		case <-ctx.Done():
+			return nil
+		case update, ok := <-links:
+			if !ok {
+				return subscriptionClosed("link", linkErrs)
+			}
+			event = linkEvent(update)
+		case update, ok := <-routes:
+			if !ok {
+				return subscriptionClosed("route", routeErrs)
+			}
+			event = routeEvent(update, linkName)
+		}
+		if event == nil {
+			continue
+		}
+		data, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		o.Broadcast(o.Identifier(), string(data))
+	}
+}
+
+// errorCallback keeps the first error of a subscription in errs.
+func errorCallback(errs chan<- error) func(error) {
+	return func(err error) {
+		select {
+		case errs <- err:
+		default:
+		}
+	}
+}
+
+// subscriptionClosed returns the error of a closed subscription, wrapping the
+// error netlink reported before closing its channel, if any.
+func subscriptionClosed(name string, errs <-chan error) error {
+	select {
+	case err := <-errs:
+		return fmt.Errorf("%s subscription closed: %w", name, err)
+	default:
+		return fmt.Errorf("%s subscription closed", name)
+	}
+}
+
+func linkEvent(update netlink.LinkUpdate) *resource.NetlinkEvent {
+	var eventType string
+	switch update.Header.Type {
+	case unix.RTM_NEWLINK:
+		eventType = resource.NetlinkNewLink
+	case unix.RTM_DELLINK:
+		eventType = resource.NetlinkDelLink
+	default:
+		return nil
+	}
+	attrs := update.Link.Attrs()
+	link := &resource.NetlinkLink{
+		Index:     attrs.Index,
+		Name:      attrs.Name,
+		Kind:      update.Link.Type(),
+		OperState: attrs.OperState.String(),
+		Loopback:  attrs.Flags&net.FlagLoopback != 0,
+		// the kernel announces a registered link with every attribute
+		// changed.
+		Registered: eventType == resource.NetlinkNewLink && update.Change == ^uint32(0),
+	}
+	if len(attrs.HardwareAddr) > 0 {
+		link.HardwareAddr = attrs.HardwareAddr.String()
+	}
+	return &resource.NetlinkEvent{Type: eventType, Link: link}
+}
+
+func routeEvent(update netlink.RouteUpdate, linkName func(int) string) *resource.NetlinkEvent {
+	var eventType string
+	switch update.Type {
+	case unix.RTM_NEWROUTE:
+		eventType = resource.NetlinkNewRoute
+	case unix.RTM_DELROUTE:
+		eventType = resource.NetlinkDelRoute
+	default:
+		return nil
+	}
+	route := &resource.NetlinkRoute{
+		Family:    "inet",
+		Table:     update.Table,
+		Dst:       "default",
+		LinkIndex: update.LinkIndex,
+	}
+	if update.Family == unix.AF_INET6 {
+		route.Family = "inet6"
+	}
+	if update.Dst != nil {
+		if ones, _ := update.Dst.Mask.Size(); ones > 0 {
+			route.Dst = update.Dst.String()
+		}
+	}
+	if update.Gw != nil {
+		route.Gateway = update.Gw.String()
+	}
+	if update.LinkIndex > 0 {
+		route.Dev = linkName(update.LinkIndex)
+	}
+	return &resource.NetlinkEvent{Type: eventType, Route: route}
+}
+
+// linkName returns the name of a link, or an empty name when it no longer
+// exists.
+func linkName(index int) string {
+	link, err := netlink.LinkByIndex(index)
+	if err != nil {
+		return ""
+	}
+	return link.Attrs().Name
+}
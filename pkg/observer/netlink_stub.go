@@ -0,0 +1,15 @@
+//go:build !linux
+
+package observer
+
+import (
+	"fmt"
+
+	"github.com/aws/eks-node-monitoring-agent/api/monitor/resource"
+)
+
+func init() {
+	RegisterObserverConstructor(resource.ResourceTypeNetlink, func(rp []resource.Part) (Observer, error) {
+		return nil, fmt.Errorf("netlink observer is only supported on Linux")
+	})
+}
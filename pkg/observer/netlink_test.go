@@ -0,0 +1,103 @@
+//go:build linux
+
+package observer
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netlink/nl"
+	"golang.org/x/sys/unix"
+
+	"github.com/aws/eks-node-monitoring-agent/api/monitor/resource"
+)
+
+func TestNetlinkObserver_Constructor(t *testing.T) {
+	obs, err := ObserverConstructorMap[resource.ResourceTypeNetlink](nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "netlink", obs.Identifier())
+
+	_, err = ObserverConstructorMap[resource.ResourceTypeNetlink]([]resource.Part{"eth0"})
+	assert.Error(t, err)
+}
+
+func TestNetlinkObserver_LinkEvent(t *testing.T) {
+	hardwareAddr, _ := net.ParseMAC("0a:1b:2c:3d:4e:5f")
+	link := &netlink.Device{LinkAttrs: netlink.LinkAttrs{Index: 3, Name: "ens6", HardwareAddr: hardwareAddr, OperState: netlink.OperUp}}
+
+	event := linkEvent(netlink.LinkUpdate{
+		IfInfomsg: nl.IfInfomsg{IfInfomsg: unix.IfInfomsg{Index: 3, Change: ^uint32(0)}},
+		Header:    unix.NlMsghdr{Type: unix.RTM_NEWLINK},
+		Link:      link,
+	})
+	assert.Equal(t, &resource.NetlinkEvent{
+		Type: resource.NetlinkNewLink,
+		Link: &resource.NetlinkLink{Index: 3, Name: "ens6", Kind: "device", HardwareAddr: "0a:1b:2c:3d:4e:5f", OperState: "up", Registered: true},
+	}, event)
+
+	// the event survives its encoding.
+	data, err := json.Marshal(event)
+	assert.NoError(t, err)
+	parsed, err := resource.ParseNetlinkEvent(string(data))
+	assert.NoError(t, err)
+	assert.Equal(t, *event, parsed)
+
+	link.OperState = netlink.OperDown
+	event = linkEvent(netlink.LinkUpdate{
+		IfInfomsg: nl.IfInfomsg{IfInfomsg: unix.IfInfomsg{Index: 3, Change: unix.IFF_UP}},
+		Header:    unix.NlMsghdr{Type: unix.RTM_NEWLINK},
+		Link:      link,
+	})
+	assert.Equal(t, "down", event.Link.OperState)
+	assert.False(t, event.Link.Registered)
+
+	event = linkEvent(netlink.LinkUpdate{Header: unix.NlMsghdr{Type: unix.RTM_DELLINK}, Link: &netlink.Veth{LinkAttrs: netlink.LinkAttrs{Index: 9, Name: "eni1a2b3c"}}})
+	assert.Equal(t, resource.NetlinkDelLink, event.Type)
+	assert.Equal(t, "veth", event.Link.Kind)
+}
+
+func TestNetlinkObserver_RouteEvent(t *testing.T) {
+	linkName := func(index int) string { return map[int]string{2: "ens5"}[index] }
+
+	_, defaultDst, _ := net.ParseCIDR("0.0.0.0/0")
+	for _, dst := range []*net.IPNet{nil, defaultDst} {
+		event := routeEvent(netlink.RouteUpdate{Type: unix.RTM_DELROUTE, Route: netlink.Route{
+			Family:    unix.AF_INET,
+			Table:     unix.RT_TABLE_MAIN,
+			Dst:       dst,
+			Gw:        net.ParseIP("10.0.0.1"),
+			LinkIndex: 2,
+		}}, linkName)
+		assert.Equal(t, &resource.NetlinkEvent{
+			Type:  resource.NetlinkDelRoute,
+			Route: &resource.NetlinkRoute{Family: "inet", Table: unix.RT_TABLE_MAIN, Dst: "default", Gateway: "10.0.0.1", Dev: "ens5", LinkIndex: 2},
+		}, event)
+	}
+
+	_, dst, _ := net.ParseCIDR("2001:db8::/64")
+	event := routeEvent(netlink.RouteUpdate{Type: unix.RTM_NEWROUTE, Route: netlink.Route{Family: unix.AF_INET6, Table: 2, Dst: dst, LinkIndex: 7}}, linkName)
+	assert.Equal(t, &resource.NetlinkEvent{
+		Type:  resource.NetlinkNewRoute,
+		Route: &resource.NetlinkRoute{Family: "inet6", Table: 2, Dst: "2001:db8::/64", LinkIndex: 7},
+	}, event)
+
+	assert.Nil(t, routeEvent(netlink.RouteUpdate{Type: unix.RTM_GETROUTE}, linkName))
+}
+
+func TestNetlinkObserver_SubscriptionClosed(t *testing.T) {
+	errs := make(chan error, 1)
+	assert.EqualError(t, subscriptionClosed("link", errs), "link subscription closed")
+
+	callback := errorCallback(errs)
+	overrun := errors.New("no buffer space available")
+	callback(overrun)
+	// later errors do not block the subscription.
+	callback(errors.New("socket closed"))
+	err := subscriptionClosed("route", errs)
+	assert.ErrorIs(t, err, overrun)
+	assert.EqualError(t, err, "route subscription closed: no buffer space available")
+}
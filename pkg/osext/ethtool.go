@@ -0,0 +1,115 @@
+package osext
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"runtime"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// ethssStats is the ETH_SS_STATS string set, which names the NIC
+	// statistics.
+	ethssStats = 1
+	// ethGStringLen is ETH_GSTRING_LEN, the length of each name in a string
+	// set.
+	ethGStringLen = 32
+)
+
+// ethtoolIfreq is a struct ifreq with the ifr_data member of the union set,
+// which points to the ethtool command.
+type ethtoolIfreq struct {
+	name [unix.IFNAMSIZ]byte
+	data unsafe.Pointer
+	_    [24 - unsafe.Sizeof(uintptr(0))]byte
+}
+
+// EthtoolStats returns the NIC statistics of the interface, as listed by
+// `ethtool -S`, using the ETHTOOL_GSTRINGS and ETHTOOL_GSTATS ioctls rather
+// than running ethtool. Interfaces without statistics, like the loopback
+// interface, have none.
+func EthtoolStats(interfaceName string) (map[string]uint64, error) {
+	fd, err := unix.Socket(unix.AF_INET, unix.SOCK_DGRAM|unix.SOCK_CLOEXEC, 0)
+	if err != nil {
+		return nil, fmt.Errorf("opening socket for ethtool: %w", err)
+	}
+	defer unix.Close(fd)
+
+	drvinfo, err := unix.IoctlGetEthtoolDrvinfo(fd, interfaceName)
+	if err != nil {
+		if errors.Is(err, unix.EOPNOTSUPP) {
+			return map[string]uint64{}, nil
+		}
+		return nil, fmt.Errorf("getting driver info of %s: %w", interfaceName, err)
+	}
+	count := int(drvinfo.N_stats)
+	if count == 0 {
+		return map[string]uint64{}, nil
+	}
+
+	// struct ethtool_gstrings is the command, string set and length, followed
+	// by the names.
+	gstrings := make([]byte, 12+count*ethGStringLen)
+	binary.NativeEndian.PutUint32(gstrings[0:], unix.ETHTOOL_GSTRINGS)
+	binary.NativeEndian.PutUint32(gstrings[4:], ethssStats)
+	binary.NativeEndian.PutUint32(gstrings[8:], uint32(count))
+	if err := ethtoolIoctl(fd, interfaceName, gstrings); err != nil {
+		return nil, fmt.Errorf("getting statistic names of %s: %w", interfaceName, err)
+	}
+	// struct ethtool_stats is the command and count, followed by the values.
+	gstats := make([]byte, 8+count*8)
+	binary.NativeEndian.PutUint32(gstats[0:], unix.ETHTOOL_GSTATS)
+	binary.NativeEndian.PutUint32(gstats[4:], uint32(count))
+	if err := ethtoolIoctl(fd, interfaceName, gstats); err != nil {
+		return nil, fmt.Errorf("getting statistics of %s: %w", interfaceName, err)
+	}
+	return ParseEthtoolStats(gstrings, gstats, count)
+}
+
+// ParseEthtoolStats pairs the names of the ETHTOOL_GSTRINGS response with the
+// values of the ETHTOOL_GSTATS response, both of which were requested for
+// count statistics. Names fill the ETH_GSTRING_LEN bytes of their slot and are
+// only NUL terminated when they are shorter.
+func ParseEthtoolStats(gstrings, gstats []byte, count int) (map[string]uint64, error) {
+	if len(gstrings) < 12+count*ethGStringLen {
+		return nil, fmt.Errorf("statistic names of %d bytes are too short for %d statistics", len(gstrings), count)
+	}
+	if len(gstats) < 8+count*8 {
+		return nil, fmt.Errorf("statistics of %d bytes are too short for %d statistics", len(gstats), count)
+	}
+	// the kernel reports how many statistics it returned, which only differ
+	// from the count if the driver changed them in between.
+	if n := int(binary.NativeEndian.Uint32(gstrings[8:])); n != count {
+		return nil, fmt.Errorf("expected %d statistic names, got %d", count, n)
+	}
+	if n := int(binary.NativeEndian.Uint32(gstats[4:])); n != count {
+		return nil, fmt.Errorf("expected %d statistics, got %d", count, n)
+	}
+	stats := make(map[string]uint64, count)
+	for i := range count {
+		name := gstrings[12+i*ethGStringLen : 12+(i+1)*ethGStringLen]
+		if end := bytes.IndexByte(name, 0); end >= 0 {
+			name = name[:end]
+		}
+		stats[string(name)] = binary.NativeEndian.Uint64(gstats[8+i*8:])
+	}
+	return stats, nil
+}
+
+func ethtoolIoctl(fd int, interfaceName string, data []byte) error {
+	if len(interfaceName) >= unix.IFNAMSIZ {
+		return unix.EINVAL
+	}
+	ifr := ethtoolIfreq{data: unsafe.Pointer(&data[0])}
+	copy(ifr.name[:], interfaceName)
+	_, _, errno := unix.Syscall(unix.SYS_IOCTL, uintptr(fd), unix.SIOCETHTOOL, uintptr(unsafe.Pointer(&ifr)))
+	runtime.KeepAlive(data)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package osext_test
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/sys/unix"
+
+	"github.com/aws/eks-node-monitoring-agent/pkg/osext"
+)
+
+func TestEthtoolStats(t *testing.T) {
+	// the loopback interface has no NIC statistics.
+	stats, err := osext.EthtoolStats("lo")
+	assert.NoError(t, err)
+	assert.Empty(t, stats)
+
+	_, err = osext.EthtoolStats("does-not-exist")
+	assert.Error(t, err)
+}
+
+// ethtoolBuffers builds the ETHTOOL_GSTRINGS and ETHTOOL_GSTATS responses for
+// the names and values, reporting the given counts.
+func ethtoolBuffers(names []string, values []uint64, namesCount, valuesCount int) ([]byte, []byte) {
+	gstrings := make([]byte, 12+len(names)*32)
+	binary.NativeEndian.PutUint32(gstrings[0:], unix.ETHTOOL_GSTRINGS)
+	binary.NativeEndian.PutUint32(gstrings[4:], 1)
+	binary.NativeEndian.PutUint32(gstrings[8:], uint32(namesCount))
+	for i, name := range names {
+		copy(gstrings[12+i*32:12+(i+1)*32], name)
+	}
+	gstats := make([]byte, 8+len(values)*8)
+	binary.NativeEndian.PutUint32(gstats[0:], unix.ETHTOOL_GSTATS)
+	binary.NativeEndian.PutUint32(gstats[4:], uint32(valuesCount))
+	for i, value := range values {
+		binary.NativeEndian.PutUint64(gstats[8+i*8:], value)
+	}
+	return gstrings, gstats
+}
+
+func TestParseEthtoolStats(t *testing.T) {
+	// a name that fills its 32 bytes has no NUL terminator.
+	const longName = "rx_queue_0_xdp_redirect_failures"
+	assert.Len(t, longName, 32)
+
+	for _, testCase := range []struct {
+		name        string
+		names       []string
+		values      []uint64
+		namesCount  int
+		valuesCount int
+		count       int
+		stats       map[string]uint64
+		err         string
+	}{
+		{
+			name:        "ENA",
+			names:       []string{"bw_in_allowance_exceeded", "pps_allowance_exceeded", "conntrack_allowance_available"},
+			values:      []uint64{12, 0, 1 << 40},
+			namesCount:  3,
+			valuesCount: 3,
+			count:       3,
+			stats:       map[string]uint64{"bw_in_allowance_exceeded": 12, "pps_allowance_exceeded": 0, "conntrack_allowance_available": 1 << 40},
+		},
+		{
+			name:        "NameWithoutNUL",
+			names:       []string{longName, "tx_timeout"},
+			values:      []uint64{7, 3},
+			namesCount:  2,
+			valuesCount: 2,
+			count:       2,
+			stats:       map[string]uint64{longName: 7, "tx_timeout": 3},
+		},
+		{
+			name:        "NamesCountMismatch",
+			names:       []string{"rx_packets", "tx_packets"},
+			values:      []uint64{1, 2},
+			namesCount:  1,
+			valuesCount: 2,
+			count:       2,
+			err:         "expected 2 statistic names, got 1",
+		},
+		{
+			name:        "StatsCountMismatch",
+			names:       []string{"rx_packets", "tx_packets"},
+			values:      []uint64{1, 2},
+			namesCount:  2,
+			valuesCount: 3,
+			count:       2,
+			err:         "expected 2 statistics, got 3",
+		},
+		{
+			name:        "ShortBuffer",
+			names:       []string{"rx_packets"},
+			values:      []uint64{1},
+			namesCount:  2,
+			valuesCount: 2,
+			count:       2,
+			err:         "too short for 2 statistics",
+		},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			gstrings, gstats := ethtoolBuffers(testCase.names, testCase.values, testCase.namesCount, testCase.valuesCount)
+			stats, err := osext.ParseEthtoolStats(gstrings, gstats, testCase.count)
+			if testCase.err != "" {
+				assert.ErrorContains(t, err, testCase.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.stats, stats)
+		})
+	}
+}
@@ -2,9 +2,17 @@ package osext
 
 import (
 	"bytes"
+	"cmp"
+	"errors"
+	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"syscall"
 
 	"github.com/aws/eks-node-monitoring-agent/pkg/config"
 )
@@ -26,3 +34,145 @@ func ParseSysctl[T any](sysctlName string, parser RawSysctlParser[T]) (*T, error
 	}
 	return &sysctlValue, nil
 }
+
+// ProcStat holds the fields of /proc/<pid>/stat used by the agent.
+// see: https://man7.org/linux/man-pages/man5/proc_pid_stat.5.html
+type ProcStat struct {
+	PID   int
+	Comm  string
+	State byte
+	PPID  int
+}
+
+// ReadProcStats reads the stat of every process on the host. Processes that
+// exit while they are being read are skipped.
+func ReadProcStats() ([]ProcStat, error) {
+	paths, err := filepath.Glob(config.ToHostPath("/proc/[0-9]*/stat"))
+	if err != nil {
+		return nil, err
+	}
+	stats := make([]ProcStat, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) || errors.Is(err, syscall.ESRCH) {
+				continue
+			}
+			return nil, err
+		}
+		stat, err := ParseProcStat(data)
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", path, err)
+		}
+		stats = append(stats, stat)
+	}
+	return stats, nil
+}
+
+// ParseProcStat parses the contents of /proc/<pid>/stat, formatted like:
+//
+//	4321 (java) Z 1234 4321 1234 0 -1 4227084 ...
+//
+// The command is enclosed in parentheses and may itself contain spaces and
+// parentheses, so the fields are split after its last closing parenthesis.
+func ParseProcStat(data []byte) (ProcStat, error) {
+	var stat ProcStat
+	start := bytes.IndexByte(data, '(')
+	end := bytes.LastIndexByte(data, ')')
+	if start < 0 || end < start {
+		return stat, fmt.Errorf("invalid stat %q", data)
+	}
+	pid, err := strconv.Atoi(string(bytes.TrimSpace(data[:start])))
+	if err != nil {
+		return stat, err
+	}
+	fields := strings.Fields(string(data[end+1:]))
+	if len(fields) < 2 || len(fields[0]) != 1 {
+		return stat, fmt.Errorf("invalid stat %q", data)
+	}
+	ppid, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return stat, err
+	}
+	return ProcStat{
+		PID:   pid,
+		Comm:  string(data[start+1 : end]),
+		State: fields[0][0],
+		PPID:  ppid,
+	}, nil
+}
+
+// ZombieParent is a process with exited children that it has not reaped.
+type ZombieParent struct {
+	ProcStat
+	Zombies int
+}
+
+// ZombieParents groups the zombie processes in stats by their parent, sorted by
+// descending number of zombies. A parent that is missing from stats is only
+// identified by its PID.
+func ZombieParents(stats []ProcStat) []ZombieParent {
+	byPID := make(map[int]ProcStat, len(stats))
+	zombies := make(map[int]int)
+	for _, stat := range stats {
+		byPID[stat.PID] = stat
+		if stat.State == 'Z' {
+			zombies[stat.PPID]++
+		}
+	}
+	parents := make([]ZombieParent, 0, len(zombies))
+	for ppid, count := range zombies {
+		parent, ok := byPID[ppid]
+		if !ok {
+			parent = ProcStat{PID: ppid}
+		}
+		parents = append(parents, ZombieParent{ProcStat: parent, Zombies: count})
+	}
+	slices.SortFunc(parents, func(a, b ZombieParent) int {
+		return cmp.Or(cmp.Compare(b.Zombies, a.Zombies), cmp.Compare(a.PID, b.PID))
+	})
+	return parents
+}
+
+// ReadProcCgroup returns the cgroup of a process on the host. The unified
+// hierarchy is preferred, and on a cgroup v1 host the path of the first
+// hierarchy is returned.
+func ReadProcCgroup(pid int) (string, error) {
+	data, err := os.ReadFile(config.ToHostPath(filepath.Join("/proc", strconv.Itoa(pid), "cgroup")))
+	if err != nil {
+		return "", err
+	}
+	// each line is formatted like "hierarchy-ID:controller-list:cgroup-path",
+	// with "0::" for the unified hierarchy.
+	var cgroup string
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		parts := strings.SplitN(line, ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		if parts[0] == "0" && parts[1] == "" {
+			return parts[2], nil
+		}
+		if cgroup == "" {
+			cgroup = parts[2]
+		}
+	}
+	return cgroup, nil
+}
+
+// kubepodsCgroupRegexp matches the cgroup of a pod, or of a container in a
+// pod. The systemd cgroup driver names them like
+// "kubepods-burstable-pod<uid>.slice/cri-containerd-<id>.scope", with the
+// dashes of the UID replaced by underscores, and the cgroupfs driver like
+// "pod<uid>/<id>".
+var kubepodsCgroupRegexp = regexp.MustCompile(`pod([0-9a-fA-F]{8}[-_][0-9a-fA-F]{4}[-_][0-9a-fA-F]{4}[-_][0-9a-fA-F]{4}[-_][0-9a-fA-F]{12})(?:\.slice)?(?:/(?:[a-z]+-)*([0-9a-f]{64})(?:\.scope)?)?$`)
+
+// PodFromCgroup returns the UID of the pod that a cgroup belongs to, and the
+// ID of the container when the cgroup is one of the pod's containers.
+func PodFromCgroup(cgroup string) (podUID string, containerID string, ok bool) {
+	matches := kubepodsCgroupRegexp.FindStringSubmatch(cgroup)
+	if matches == nil {
+		return "", "", false
+	}
+	return strings.ReplaceAll(matches[1], "_", "-"), matches[2], true
+}
@@ -0,0 +1,111 @@
+package osext_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aws/eks-node-monitoring-agent/pkg/config"
+	"github.com/aws/eks-node-monitoring-agent/pkg/osext"
+)
+
+func TestParseProcStat(t *testing.T) {
+	stat, err := osext.ParseProcStat([]byte("4321 (my (app) x) Z 1234 4321 1234 0 -1 4227084 0 0 0 0\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, osext.ProcStat{PID: 4321, Comm: "my (app) x", State: 'Z', PPID: 1234}, stat)
+
+	for _, data := range []string{"", "4321 java Z 1", "4321 (java)", "x (java) Z 1", "4321 (java) Z x"} {
+		_, err := osext.ParseProcStat([]byte(data))
+		assert.Error(t, err, data)
+	}
+}
+
+func TestReadProcStats(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv(config.HOST_ROOT_ENV, root)
+	for pid, stat := range map[string]string{
+		"1":   "1 (systemd) S 0 1 1 0 -1 4194560\n",
+		"100": "100 (bash) Z 1 100 100 0 -1 4227084\n",
+	} {
+		assert.NoError(t, os.MkdirAll(filepath.Join(root, "proc", pid), 0755))
+		assert.NoError(t, os.WriteFile(filepath.Join(root, "proc", pid, "stat"), []byte(stat), 0644))
+	}
+	// a process that exited after the directory was listed.
+	assert.NoError(t, os.MkdirAll(filepath.Join(root, "proc", "200"), 0755))
+
+	stats, err := osext.ReadProcStats()
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []osext.ProcStat{
+		{PID: 1, Comm: "systemd", State: 'S', PPID: 0},
+		{PID: 100, Comm: "bash", State: 'Z', PPID: 1},
+	}, stats)
+}
+
+func TestZombieParents(t *testing.T) {
+	parents := osext.ZombieParents([]osext.ProcStat{
+		{PID: 1, Comm: "systemd", State: 'S'},
+		{PID: 10, Comm: "app", State: 'S', PPID: 1},
+		{PID: 11, Comm: "worker", State: 'Z', PPID: 10},
+		{PID: 12, Comm: "worker", State: 'Z', PPID: 10},
+		{PID: 13, Comm: "sh", State: 'Z', PPID: 1},
+		{PID: 14, Comm: "orphan", State: 'Z', PPID: 99},
+	})
+	assert.Equal(t, []osext.ZombieParent{
+		{ProcStat: osext.ProcStat{PID: 10, Comm: "app", State: 'S', PPID: 1}, Zombies: 2},
+		{ProcStat: osext.ProcStat{PID: 1, Comm: "systemd", State: 'S'}, Zombies: 1},
+		{ProcStat: osext.ProcStat{PID: 99}, Zombies: 1},
+	}, parents)
+	assert.Empty(t, osext.ZombieParents([]osext.ProcStat{{PID: 1, State: 'S'}}))
+}
+
+func TestReadProcCgroup(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv(config.HOST_ROOT_ENV, root)
+	write := func(pid, content string) {
+		assert.NoError(t, os.MkdirAll(filepath.Join(root, "proc", pid), 0755))
+		assert.NoError(t, os.WriteFile(filepath.Join(root, "proc", pid, "cgroup"), []byte(content), 0644))
+	}
+	write("1", "0::/kubepods.slice/kubepods-podabc.slice\n")
+	write("2", "12:pids:/kubepods/burstable/podabc/def\n11:memory:/kubepods/burstable/podabc/def\n0::/\n")
+	write("3", "12:pids:/kubepods/burstable/podabc/def\n11:memory:/other\n")
+
+	for pid, expected := range map[int]string{
+		1: "/kubepods.slice/kubepods-podabc.slice",
+		2: "/",
+		3: "/kubepods/burstable/podabc/def",
+	} {
+		cgroup, err := osext.ReadProcCgroup(pid)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, cgroup)
+	}
+	_, err := osext.ReadProcCgroup(4)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestPodFromCgroup(t *testing.T) {
+	const podUID = "8d2b6f4e-3c1a-4e5f-9a7b-1c2d3e4f5a6b"
+	containerID := strings.Repeat("0123456789abcdef", 4)
+	for _, testCase := range []struct {
+		cgroup      string
+		containerID string
+	}{
+		{"/kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod8d2b6f4e_3c1a_4e5f_9a7b_1c2d3e4f5a6b.slice/cri-containerd-" + containerID + ".scope", containerID},
+		{"/kubepods.slice/kubepods-pod8d2b6f4e_3c1a_4e5f_9a7b_1c2d3e4f5a6b.slice", ""},
+		{"kubepods-besteffort-pod8d2b6f4e_3c1a_4e5f_9a7b_1c2d3e4f5a6b.slice", ""},
+		{"/kubepods/besteffort/pod" + podUID + "/" + containerID, containerID},
+		{"/kubepods/pod" + podUID, ""},
+	} {
+		uid, id, ok := osext.PodFromCgroup(testCase.cgroup)
+		assert.True(t, ok, testCase.cgroup)
+		assert.Equal(t, podUID, uid, testCase.cgroup)
+		assert.Equal(t, testCase.containerID, id, testCase.cgroup)
+	}
+
+	for _, cgroup := range []string{"/init.scope", "/system.slice/containerd.service", "/kubepods.slice/kubepods-burstable.slice"} {
+		_, _, ok := osext.PodFromCgroup(cgroup)
+		assert.False(t, ok, cgroup)
+	}
+}
@@ -0,0 +1,226 @@
+package osext
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/netip"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/aws/eks-node-monitoring-agent/pkg/config"
+)
+
+// Sockstat holds the counters of /proc/net/sockstat and /proc/net/sockstat6,
+// keyed by protocol and then by counter, e.g. Sockstat["TCP"]["tw"]. The TCP
+// orphan, tw, alloc and mem counters cover both IPv4 and IPv6 sockets.
+type Sockstat map[string]map[string]int
+
+// ReadSockstat reads the socket counters of the host. The IPv6 counters are
+// skipped when IPv6 is disabled.
+func ReadSockstat() (Sockstat, error) {
+	sockstat := Sockstat{}
+	for _, path := range []string{"/proc/net/sockstat", "/proc/net/sockstat6"} {
+		data, err := os.ReadFile(config.ToHostPath(path))
+		if err != nil {
+			if path == "/proc/net/sockstat6" && errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+		if err := ParseSockstat(data, sockstat); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", path, err)
+		}
+	}
+	return sockstat, nil
+}
+
+// ParseSockstat adds the counters of a sockstat file to sockstat. Each line
+// holds the counters of a protocol, formatted like:
+//
+//	TCP: inuse 17 orphan 0 tw 2 alloc 21 mem 2
+func ParseSockstat(data []byte, sockstat Sockstat) error {
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		protocol, counters, ok := strings.Cut(line, ":")
+		if !ok {
+			return fmt.Errorf("invalid line %q", line)
+		}
+		fields := strings.Fields(counters)
+		if len(fields)%2 != 0 {
+			return fmt.Errorf("invalid line %q", line)
+		}
+		if sockstat[protocol] == nil {
+			sockstat[protocol] = map[string]int{}
+		}
+		for i := 0; i < len(fields); i += 2 {
+			value, err := strconv.Atoi(fields[i+1])
+			if err != nil {
+				return fmt.Errorf("invalid line %q: %w", line, err)
+			}
+			sockstat[protocol][fields[i]] = value
+		}
+	}
+	return nil
+}
+
+// TCPState is the state of a TCP socket, as listed in /proc/net/tcp.
+// see: https://github.com/torvalds/linux/blob/master/include/net/tcp_states.h
+type TCPState uint8
+
+const (
+	TCPEstablished TCPState = 0x01
+	TCPTimeWait    TCPState = 0x06
+	TCPListen      TCPState = 0x0A
+)
+
+// TCPSocket is a TCP socket of the host, as listed in /proc/net/tcp and
+// /proc/net/tcp6.
+type TCPSocket struct {
+	Local  netip.AddrPort
+	Remote netip.AddrPort
+	State  TCPState
+}
+
+// ReadTCPSockets reads the TCP sockets of the host. The IPv6 sockets are
+// skipped when IPv6 is disabled.
+func ReadTCPSockets() ([]TCPSocket, error) {
+	var sockets []TCPSocket
+	for _, path := range []string{"/proc/net/tcp", "/proc/net/tcp6"} {
+		f, err := os.Open(config.ToHostPath(path))
+		if err != nil {
+			if path == "/proc/net/tcp6" && errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+		parsed, err := ParseProcNetTCP(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", path, err)
+		}
+		sockets = append(sockets, parsed...)
+	}
+	return sockets, nil
+}
+
+// ParseProcNetTCP parses the sockets of /proc/net/tcp or /proc/net/tcp6, which
+// follow a header line and are formatted like:
+//
+//	0: 0100007F:0050 0100007F:C350 01 00000000:00000000 00:00000000 ...
+//
+// Addresses are the hexadecimal 32-bit words of the address in host byte
+// order, followed by the port.
+func ParseProcNetTCP(r io.Reader) ([]TCPSocket, error) {
+	var sockets []TCPSocket
+	scanner := bufio.NewScanner(r)
+	// skip the header.
+	scanner.Scan()
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 {
+			continue
+		}
+		local, err := parseProcNetAddr(fields[1])
+		if err != nil {
+			return nil, err
+		}
+		remote, err := parseProcNetAddr(fields[2])
+		if err != nil {
+			return nil, err
+		}
+		state, err := strconv.ParseUint(fields[3], 16, 8)
+		if err != nil {
+			return nil, fmt.Errorf("invalid state %q: %w", fields[3], err)
+		}
+		sockets = append(sockets, TCPSocket{Local: local, Remote: remote, State: TCPState(state)})
+	}
+	return sockets, scanner.Err()
+}
+
+// SoftnetStat is the counters of a CPU in /proc/net/softnet_stat.
+type SoftnetStat struct {
+	CPU int
+	// Processed is the number of packets processed by the CPU.
+	Processed uint64
+	// Dropped is the number of packets dropped because the backlog of the
+	// CPU, bounded by net.core.netdev_max_backlog, was full.
+	Dropped uint64
+	// TimeSqueeze is the number of times the CPU ran out of
+	// net.core.netdev_budget or net.core.netdev_budget_usecs with packets
+	// left to process.
+	TimeSqueeze uint64
+}
+
+// ReadSoftnetStat reads the softnet counters of each CPU of the host.
+func ReadSoftnetStat() ([]SoftnetStat, error) {
+	f, err := os.Open(config.ToHostPath("/proc/net/softnet_stat"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	stats, err := ParseSoftnetStat(f)
+	if err != nil {
+		return nil, fmt.Errorf("parsing /proc/net/softnet_stat: %w", err)
+	}
+	return stats, nil
+}
+
+// ParseSoftnetStat parses /proc/net/softnet_stat, which has a line of
+// hexadecimal counters for each online CPU, formatted like:
+//
+//	000a3b7c 00000000 00000012 00000000 00000000 ... 00000000 00000003
+//
+// Since Linux 5.10 the 13th counter is the index of the CPU, which otherwise
+// is the line number, as long as no CPU is offline.
+func ParseSoftnetStat(r io.Reader) ([]SoftnetStat, error) {
+	var stats []SoftnetStat
+	scanner := bufio.NewScanner(r)
+	for line := 0; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 {
+			return nil, fmt.Errorf("invalid line %q", scanner.Text())
+		}
+		var counters [13]uint64
+		for i := 0; i < len(fields) && i < len(counters); i++ {
+			value, err := strconv.ParseUint(fields[i], 16, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid line %q: %w", scanner.Text(), err)
+			}
+			counters[i] = value
+		}
+		cpu := line
+		if len(fields) >= 13 {
+			cpu = int(counters[12])
+		}
+		stats = append(stats, SoftnetStat{CPU: cpu, Processed: counters[0], Dropped: counters[1], TimeSqueeze: counters[2]})
+	}
+	return stats, scanner.Err()
+}
+
+func parseProcNetAddr(s string) (netip.AddrPort, error) {
+	addrHex, portHex, ok := strings.Cut(s, ":")
+	if !ok {
+		return netip.AddrPort{}, fmt.Errorf("invalid address %q", s)
+	}
+	words, err := hex.DecodeString(addrHex)
+	if err != nil || (len(words) != 4 && len(words) != 16) {
+		return netip.AddrPort{}, fmt.Errorf("invalid address %q", s)
+	}
+	port, err := strconv.ParseUint(portHex, 16, 16)
+	if err != nil {
+		return netip.AddrPort{}, fmt.Errorf("invalid address %q: %w", s, err)
+	}
+	// each word is printed from its value in host byte order, so it is written
+	// back in host byte order to recover the address in network byte order.
+	addr := make([]byte, len(words))
+	for i := 0; i < len(words); i += 4 {
+		binary.NativeEndian.PutUint32(addr[i:], binary.BigEndian.Uint32(words[i:]))
+	}
+	ip, _ := netip.AddrFromSlice(addr)
+	return netip.AddrPortFrom(ip.Unmap(), uint16(port)), nil
+}
@@ -0,0 +1,99 @@
+package osext_test
+
+import (
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aws/eks-node-monitoring-agent/pkg/config"
+	"github.com/aws/eks-node-monitoring-agent/pkg/osext"
+)
+
+func TestReadSockstat(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv(config.HOST_ROOT_ENV, root)
+	assert.NoError(t, os.MkdirAll(filepath.Join(root, "proc", "net"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "proc", "net", "sockstat"), []byte(`sockets: used 290
+TCP: inuse 17 orphan 1 tw 2 alloc 21 mem 3
+UDP: inuse 7 mem 3
+FRAG: inuse 0 memory 0
+`), 0644))
+
+	// IPv6 is disabled.
+	sockstat, err := osext.ReadSockstat()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"inuse": 17, "orphan": 1, "tw": 2, "alloc": 21, "mem": 3}, sockstat["TCP"])
+	assert.Equal(t, 290, sockstat["sockets"]["used"])
+
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "proc", "net", "sockstat6"), []byte("TCP6: inuse 5\nUDP6: inuse 3\n"), 0644))
+	sockstat, err = osext.ReadSockstat()
+	assert.NoError(t, err)
+	assert.Equal(t, 5, sockstat["TCP6"]["inuse"])
+	assert.Equal(t, 17, sockstat["TCP"]["inuse"])
+
+	for _, data := range []string{"TCP inuse 17", "TCP: inuse", "TCP: inuse x"} {
+		assert.Error(t, osext.ParseSockstat([]byte(data), osext.Sockstat{}), data)
+	}
+}
+
+func TestParseProcNetTCP(t *testing.T) {
+	sockets, err := osext.ParseProcNetTCP(strings.NewReader(`  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000:0050 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 662 1 000000007e1bd57e 100 0 0 10 0
+   1: 0B00000A:C350 0100000A:01BB 01 00000000:00000000 00:00000000 00000000     0        0 912 1 0000000093624abc 100 0 0 10 0
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, []osext.TCPSocket{
+		{Local: netip.MustParseAddrPort("0.0.0.0:80"), Remote: netip.MustParseAddrPort("0.0.0.0:0"), State: osext.TCPListen},
+		{Local: netip.MustParseAddrPort("10.0.0.11:50000"), Remote: netip.MustParseAddrPort("10.0.0.1:443"), State: osext.TCPEstablished},
+	}, sockets)
+
+	sockets, err = osext.ParseProcNetTCP(strings.NewReader(`  sl  local_address                         remote_address                        st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 0000000000000000FFFF00000B00000A:C350 0000000000000000FFFF00000100000A:01BB 06 00000000:00000000 03:00000F2A 00000000     0        0 0 3 0000000000000000
+   1: B80D0120000000000000000001000000:0050 00000000000000000000000000000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 1 1 0000000000000000
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, []osext.TCPSocket{
+		// IPv4-mapped addresses are unmapped.
+		{Local: netip.MustParseAddrPort("10.0.0.11:50000"), Remote: netip.MustParseAddrPort("10.0.0.1:443"), State: osext.TCPTimeWait},
+		{Local: netip.MustParseAddrPort("[2001:db8::1]:80"), Remote: netip.MustParseAddrPort("[::]:0"), State: osext.TCPListen},
+	}, sockets)
+
+	for _, line := range []string{
+		"0: 0100007F 00000000:0000 0A",
+		"0: 0100007:0050 00000000:0000 0A",
+		"0: 0100007F:0050 00000000:0000 XX",
+		"0: 0100007F:10000 00000000:0000 0A",
+	} {
+		_, err := osext.ParseProcNetTCP(strings.NewReader("header\n" + line))
+		assert.Error(t, err, line)
+	}
+}
+
+func TestParseSoftnetStat(t *testing.T) {
+	// Linux 5.10 and later print the index of the CPU last, so that offline
+	// CPUs are skipped without shifting the others.
+	stats, err := osext.ParseSoftnetStat(strings.NewReader(`0003b4a1 00000000 00000002 00000000 00000000 00000000 00000000 00000000 00000000 00000000 00000000 00000000 00000000
+000a3b7c 0000001f 00000012 00000000 00000000 00000000 00000000 00000000 00000000 00000000 00000000 00000000 00000003
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, []osext.SoftnetStat{
+		{CPU: 0, Processed: 0x3b4a1, Dropped: 0, TimeSqueeze: 2},
+		{CPU: 3, Processed: 0xa3b7c, Dropped: 0x1f, TimeSqueeze: 0x12},
+	}, stats)
+
+	stats, err = osext.ParseSoftnetStat(strings.NewReader("00000010 00000001 00000002 00000000 00000000 00000000 00000000 00000000 00000000 00000000 00000000\n00000020 00000003 00000004 00000000 00000000 00000000 00000000 00000000 00000000 00000000 00000000\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, []osext.SoftnetStat{
+		{CPU: 0, Processed: 0x10, Dropped: 1, TimeSqueeze: 2},
+		{CPU: 1, Processed: 0x20, Dropped: 3, TimeSqueeze: 4},
+	}, stats)
+
+	for _, data := range []string{"00000010 00000001", "00000010 0000000x 00000002"} {
+		_, err := osext.ParseSoftnetStat(strings.NewReader(data))
+		assert.Error(t, err, data)
+	}
+}
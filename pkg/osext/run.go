@@ -1,6 +1,7 @@
 package osext
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -26,13 +27,42 @@ type CommandFunc func(context.Context) *exec.Cmd
 
 // Output runs a command bounded by timeout and returns its standard output.
 func Output(ctx context.Context, timeout time.Duration, newCmd CommandFunc) ([]byte, error) {
-	return run(ctx, timeout, newCmd, false)
+	return run(ctx, timeout, newCmd, (*exec.Cmd).Output)
 }
 
 // CombinedOutput runs a command bounded by timeout and returns its standard
 // output and standard error interleaved.
 func CombinedOutput(ctx context.Context, timeout time.Duration, newCmd CommandFunc) ([]byte, error) {
-	return run(ctx, timeout, newCmd, true)
+	return run(ctx, timeout, newCmd, (*exec.Cmd).CombinedOutput)
+}
+
+// CombinedOutputLimit is CombinedOutput with the collected output capped at
+// limit bytes. Anything the command writes past the cap is discarded as it is
+// produced rather than buffered, so a command that writes without bound cannot
+// grow the agent's memory.
+func CombinedOutputLimit(ctx context.Context, timeout time.Duration, limit int, newCmd CommandFunc) ([]byte, error) {
+	return run(ctx, timeout, newCmd, func(cmd *exec.Cmd) ([]byte, error) {
+		w := &limitedWriter{limit: limit}
+		cmd.Stdout = w
+		cmd.Stderr = w
+		err := cmd.Run()
+		return w.buf.Bytes(), err
+	})
+}
+
+// limitedWriter keeps the first limit bytes written to it and silently drops
+// the rest. It always reports a full write so that the command is never
+// failed with a short write.
+type limitedWriter struct {
+	buf   bytes.Buffer
+	limit int
+}
+
+func (w *limitedWriter) Write(p []byte) (int, error) {
+	if remaining := w.limit - w.buf.Len(); remaining > 0 {
+		w.buf.Write(p[:min(len(p), remaining)])
+	}
+	return len(p), nil
 }
 
 // run executes the command and returns once it completes, the timeout elapses,
@@ -48,7 +78,7 @@ func CombinedOutput(ctx context.Context, timeout time.Duration, newCmd CommandFu
 // finally exits, if it ever does. Callers that run the same command repeatedly
 // should therefore avoid launching a fresh copy while a previous one is still
 // outstanding.
-func run(ctx context.Context, timeout time.Duration, newCmd CommandFunc, combined bool) ([]byte, error) {
+func run(ctx context.Context, timeout time.Duration, newCmd CommandFunc, collect func(*exec.Cmd) ([]byte, error)) ([]byte, error) {
 	runCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
@@ -64,11 +94,7 @@ func run(ctx context.Context, timeout time.Duration, newCmd CommandFunc, combine
 	done := make(chan result, 1)
 	go func() {
 		var r result
-		if combined {
-			r.output, r.err = cmd.CombinedOutput()
-		} else {
-			r.output, r.err = cmd.Output()
-		}
+		r.output, r.err = collect(cmd)
 		done <- r
 	}()
 
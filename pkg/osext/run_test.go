@@ -87,3 +87,33 @@ func TestCommandFuncHelpers(t *testing.T) {
 		require.ErrorIs(t, err, osext.ErrTimeout)
 	})
 }
+
+// output past the cap must be dropped while the command still runs to
+// completion, so a chatty command neither grows the buffer nor fails on a short
+// write.
+func TestCombinedOutputLimit(t *testing.T) {
+	t.Run("TruncatesOutput", func(t *testing.T) {
+		out, err := osext.CombinedOutputLimit(context.Background(), time.Minute, 4, func(ctx context.Context) *exec.Cmd {
+			return exec.CommandContext(ctx, "sh", "-c", "echo hello; echo world >&2")
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "hell", string(out))
+	})
+
+	t.Run("ReturnsExitError", func(t *testing.T) {
+		out, err := osext.CombinedOutputLimit(context.Background(), time.Minute, 1024, func(ctx context.Context) *exec.Cmd {
+			return exec.CommandContext(ctx, "sh", "-c", "echo failed; exit 3")
+		})
+		var exitErr *exec.ExitError
+		require.ErrorAs(t, err, &exitErr)
+		assert.Equal(t, 3, exitErr.ExitCode())
+		assert.Equal(t, "failed\n", string(out))
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		_, err := osext.CombinedOutputLimit(context.Background(), 100*time.Millisecond, 1024, func(ctx context.Context) *exec.Cmd {
+			return exec.CommandContext(ctx, "sleep", "30")
+		})
+		require.ErrorIs(t, err, osext.ErrTimeout)
+	})
+}
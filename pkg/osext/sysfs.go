@@ -2,8 +2,11 @@ package osext
 
 import (
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
+
+	"github.com/aws/eks-node-monitoring-agent/pkg/config"
 )
 
 func ReadInt(path string) (int, error) {
@@ -17,3 +20,21 @@ func ReadInt(path string) (int, error) {
 	}
 	return counterValue, nil
 }
+
+// ReadInterfaceStatistics reads the counters of a network interface from
+// /sys/class/net/<name>/statistics, e.g. rx_dropped.
+func ReadInterfaceStatistics(name string, counters ...string) (map[string]uint64, error) {
+	stats := make(map[string]uint64, len(counters))
+	for _, counter := range counters {
+		data, err := os.ReadFile(config.ToHostPath(filepath.Join("/sys/class/net", name, "statistics", counter)))
+		if err != nil {
+			return nil, err
+		}
+		value, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		stats[counter] = value
+	}
+	return stats, nil
+}
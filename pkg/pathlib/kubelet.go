@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
@@ -95,6 +96,24 @@ func ResolveKubeletConfig(hostRoot string) string {
 	)
 }
 
+// ResolveKubeletConfigs returns the kubelet config followed by the drop-in
+// configs that override it, in the lexical order the kubelet merges them in.
+func ResolveKubeletConfigs(hostRoot string) ([]string, error) {
+	var paths []string
+	if path := ResolveKubeletConfig(hostRoot); path != "" {
+		paths = append(paths, path)
+	}
+	if dir := ResolveKubeletConfigDropIn(hostRoot); dir != "" {
+		dropIns, err := filepath.Glob(filepath.Join(dir, "*.conf"))
+		if err != nil {
+			return nil, err
+		}
+		slices.Sort(dropIns)
+		paths = append(paths, dropIns...)
+	}
+	return paths, nil
+}
+
 func ResolveKubeconfig(hostRoot string) string {
 	return ResolvePathOption(hostRoot,
 		filepath.Join(hostRoot, os.ExpandEnv("${KUBECONFIG}")),
@@ -69,6 +69,25 @@ func TestResolvePath(t *testing.T) {
 	}
 }
 
+func TestResolveKubeletConfigs(t *testing.T) {
+	root := t.TempDir()
+	paths, err := ResolveKubeletConfigs(root)
+	assert.NoError(t, err)
+	assert.Empty(t, paths)
+
+	SetupFile(t, root, "/etc/kubernetes/kubelet/config.json")
+	SetupFile(t, root, "/etc/kubernetes/kubelet/config.json.d/20-b.conf")
+	SetupFile(t, root, "/etc/kubernetes/kubelet/config.json.d/10-a.conf")
+	SetupFile(t, root, "/etc/kubernetes/kubelet/config.json.d/README")
+	paths, err = ResolveKubeletConfigs(root)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(root, "/etc/kubernetes/kubelet/config.json"),
+		filepath.Join(root, "/etc/kubernetes/kubelet/config.json.d/10-a.conf"),
+		filepath.Join(root, "/etc/kubernetes/kubelet/config.json.d/20-b.conf"),
+	}, paths)
+}
+
 func TestResolveClusterCACert(t *testing.T) {
 	t.Run("embedded CA data needs no path", func(t *testing.T) {
 		caCertPath, err := ResolveClusterCACert(t.TempDir(), &clientcmdapi.Cluster{
@@ -91,6 +91,10 @@ var (
 
     // reasons for the ContainerRuntimeReady condition.
 
+    ApproachingPodPidMax = ReasonMeta{
+        template:        "ApproachingPodPidMax",
+        defaultSeverity: "Warning",
+    }
     ContainerRuntimeFailed = ReasonMeta{
         template:        "ContainerRuntimeFailed",
         defaultSeverity: "Warning",
@@ -107,6 +111,10 @@ var (
         template:        "LivenessProbeFailures",
         defaultSeverity: "Warning",
     }
+    PodCPUThrottled = ReasonMeta{
+        template:        "PodCPUThrottled",
+        defaultSeverity: "Warning",
+    }
     PodStuckTerminating = ReasonMeta{
         template:        "PodStuckTerminating",
         defaultSeverity: "Fatal",
@@ -134,6 +142,10 @@ var (
         template:        "AppCrash",
         defaultSeverity: "Warning",
     }
+    ApproachingConntrackMax = ReasonMeta{
+        template:        "ApproachingConntrackMax",
+        defaultSeverity: "Warning",
+    }
     ApproachingKernelPidMax = ReasonMeta{
         template:        "ApproachingKernelPidMax",
         defaultSeverity: "Warning",
@@ -142,6 +154,10 @@ var (
         template:        "ApproachingMaxOpenFiles",
         defaultSeverity: "Warning",
     }
+    CgroupOOMKill = ReasonMeta{
+        template:        "CgroupOOMKill",
+        defaultSeverity: "Info",
+    }
     ClockUnsynchronized = ReasonMeta{
         template:        "ClockUnsynchronized",
         defaultSeverity: "Warning",
@@ -150,6 +166,10 @@ var (
         template:        "ConntrackExceededKernel",
         defaultSeverity: "Warning",
     }
+    ContainerOOMKillStorm = ReasonMeta{
+        template:        "ContainerOOMKillStorm",
+        defaultSeverity: "Warning",
+    }
     ExcessiveZombieProcesses = ReasonMeta{
         template:        "ExcessiveZombieProcesses",
         defaultSeverity: "Warning",
@@ -162,10 +182,34 @@ var (
         template:        "KernelBug",
         defaultSeverity: "Warning",
     }
+    KernelTainted = ReasonMeta{
+        template:        "KernelTainted",
+        defaultSeverity: "Warning",
+    }
     LargeEnvironment = ReasonMeta{
         template:        "LargeEnvironment",
         defaultSeverity: "Warning",
     }
+    MachineCheckEvents = ReasonMeta{
+        template:        "MachineCheckEvents",
+        defaultSeverity: "Warning",
+    }
+    MachineCheckException = ReasonMeta{
+        template:        "MachineCheckException",
+        defaultSeverity: "Fatal",
+    }
+    MemoryCorrectableErrorRate = ReasonMeta{
+        template:        "MemoryCorrectableErrorRate",
+        defaultSeverity: "Warning",
+    }
+    MemoryPressureSustained = ReasonMeta{
+        template:        "MemoryPressureSustained",
+        defaultSeverity: "Warning",
+    }
+    MemoryUncorrectableError = ReasonMeta{
+        template:        "MemoryUncorrectableError",
+        defaultSeverity: "Fatal",
+    }
     RapidCron = ReasonMeta{
         template:        "RapidCron",
         defaultSeverity: "Warning",
@@ -174,6 +218,14 @@ var (
         template:        "SoftLockup",
         defaultSeverity: "Warning",
     }
+    SystemOOMKill = ReasonMeta{
+        template:        "SystemOOMKill",
+        defaultSeverity: "Warning",
+    }
+    UnexpectedReboot = ReasonMeta{
+        template:        "UnexpectedReboot",
+        defaultSeverity: "Warning",
+    }
 
     // reasons for the NetworkingReady condition.
 
@@ -185,14 +237,46 @@ var (
         template:        "BandwidthOutExceeded",
         defaultSeverity: "Warning",
     }
+    ConnectivityProbeFailing = ReasonMeta{
+        template:        "ConnectivityProbeFailing",
+        defaultSeverity: "Warning",
+    }
     ConntrackExceeded = ReasonMeta{
         template:        "ConntrackExceeded",
         defaultSeverity: "Warning",
     }
+    DNSLatencyHigh = ReasonMeta{
+        template:        "DNSLatencyHigh",
+        defaultSeverity: "Warning",
+    }
+    DNSResolutionFailing = ReasonMeta{
+        template:        "DNSResolutionFailing",
+        defaultSeverity: "Warning",
+    }
+    DefaultRouteDeleted = ReasonMeta{
+        template:        "DefaultRouteDeleted",
+        defaultSeverity: "Warning",
+    }
     EFAErrorMetric = ReasonMeta{
         template:        "EFAErrorMetric",
         defaultSeverity: "Warning",
     }
+    ENIAttached = ReasonMeta{
+        template:        "ENIAttached",
+        defaultSeverity: "Info",
+    }
+    ENIDetached = ReasonMeta{
+        template:        "ENIDetached",
+        defaultSeverity: "Info",
+    }
+    EphemeralPortsExhausted = ReasonMeta{
+        template:        "EphemeralPortsExhausted",
+        defaultSeverity: "Warning",
+    }
+    IPAMDCapacityLow = ReasonMeta{
+        template:        "IPAMDCapacityLow",
+        defaultSeverity: "Warning",
+    }
     IPAMDInconsistentState = ReasonMeta{
         template:        "IPAMDInconsistentState",
         defaultSeverity: "Warning",
@@ -213,6 +297,10 @@ var (
         template:        "IPAMDRepeatedlyRestart",
         defaultSeverity: "Warning",
     }
+    InterfaceFlapping = ReasonMeta{
+        template:        "InterfaceFlapping",
+        defaultSeverity: "Warning",
+    }
     InterfaceNotRunning = ReasonMeta{
         template:        "InterfaceNotRunning",
         defaultSeverity: "Fatal",
@@ -221,6 +309,10 @@ var (
         template:        "InterfaceNotUp",
         defaultSeverity: "Fatal",
     }
+    InterfacePacketDrops = ReasonMeta{
+        template:        "InterfacePacketDrops",
+        defaultSeverity: "Warning",
+    }
     KubeProxyNotReady = ReasonMeta{
         template:        "KubeProxyNotReady",
         defaultSeverity: "Warning",
@@ -261,6 +353,14 @@ var (
         template:        "NPARepeatedlyRestart",
         defaultSeverity: "Warning",
     }
+    NeighborTableNearlyFull = ReasonMeta{
+        template:        "NeighborTableNearlyFull",
+        defaultSeverity: "Warning",
+    }
+    NeighborTableOverflow = ReasonMeta{
+        template:        "NeighborTableOverflow",
+        defaultSeverity: "Warning",
+    }
     NetworkSysctl = ReasonMeta{
         template:        "NetworkSysctl",
         defaultSeverity: "Warning",
@@ -273,6 +373,22 @@ var (
         template:        "PortConflict",
         defaultSeverity: "Warning",
     }
+    SoftnetBacklogDrops = ReasonMeta{
+        template:        "SoftnetBacklogDrops",
+        defaultSeverity: "Warning",
+    }
+    TCPMemoryPressure = ReasonMeta{
+        template:        "TCPMemoryPressure",
+        defaultSeverity: "Warning",
+    }
+    TCPOrphanedSocketsHigh = ReasonMeta{
+        template:        "TCPOrphanedSocketsHigh",
+        defaultSeverity: "Warning",
+    }
+    TCPTimeWaitSocketsHigh = ReasonMeta{
+        template:        "TCPTimeWaitSocketsHigh",
+        defaultSeverity: "Warning",
+    }
     UnexpectedRejectRule = ReasonMeta{
         template:        "UnexpectedRejectRule",
         defaultSeverity: "Warning",
@@ -280,6 +396,14 @@ var (
 
     // reasons for the StorageReady condition.
 
+    ApproachingImageFSEviction = ReasonMeta{
+        template:        "ApproachingImageFSEviction",
+        defaultSeverity: "Warning",
+    }
+    ApproachingNodeFSEviction = ReasonMeta{
+        template:        "ApproachingNodeFSEviction",
+        defaultSeverity: "Warning",
+    }
     BlockDeviceIOError = ReasonMeta{
         template:        "BlockDeviceIOError",
         defaultSeverity: "Warning",
@@ -296,6 +420,10 @@ var (
         template:        "EBSVolumeIOPSExceeded",
         defaultSeverity: "Warning",
     }
+    EBSVolumeLatencyDegraded = ReasonMeta{
+        template:        "EBSVolumeLatencyDegraded",
+        defaultSeverity: "Warning",
+    }
     EBSVolumeThroughputExceeded = ReasonMeta{
         template:        "EBSVolumeThroughputExceeded",
         defaultSeverity: "Warning",
@@ -304,14 +432,50 @@ var (
         template:        "EtcHostsMountFailed",
         defaultSeverity: "Warning",
     }
+    FilesystemCorruption = ReasonMeta{
+        template:        "FilesystemCorruption",
+        defaultSeverity: "Fatal",
+    }
+    FilesystemReadOnly = ReasonMeta{
+        template:        "FilesystemReadOnly",
+        defaultSeverity: "Fatal",
+    }
+    FilesystemShutdown = ReasonMeta{
+        template:        "FilesystemShutdown",
+        defaultSeverity: "Fatal",
+    }
     IODelays = ReasonMeta{
         template:        "IODelays",
         defaultSeverity: "Warning",
     }
+    InodesNearlyExhausted = ReasonMeta{
+        template:        "InodesNearlyExhausted",
+        defaultSeverity: "Warning",
+    }
+    InstanceStoreCriticalWarning = ReasonMeta{
+        template:        "InstanceStoreCriticalWarning",
+        defaultSeverity: "Fatal",
+    }
+    InstanceStoreMediaErrors = ReasonMeta{
+        template:        "InstanceStoreMediaErrors",
+        defaultSeverity: "Warning",
+    }
+    InstanceStoreSpareLow = ReasonMeta{
+        template:        "InstanceStoreSpareLow",
+        defaultSeverity: "Warning",
+    }
+    InstanceStoreWearOut = ReasonMeta{
+        template:        "InstanceStoreWearOut",
+        defaultSeverity: "Warning",
+    }
     KubeletDiskUsageSlow = ReasonMeta{
         template:        "KubeletDiskUsageSlow",
         defaultSeverity: "Warning",
     }
+    NetworkMountUnresponsive = ReasonMeta{
+        template:        "NetworkMountUnresponsive",
+        defaultSeverity: "Warning",
+    }
     XFSSmallAverageClusterSize = ReasonMeta{
         template:        "XFSSmallAverageClusterSize",
         defaultSeverity: "Warning",
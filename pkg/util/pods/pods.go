@@ -0,0 +1,54 @@
+// Package pods looks up the pods running on the node through the CRI, so that
+// monitors can name the pods that cgroups and processes belong to.
+package pods
+
+import (
+	"context"
+	"time"
+
+	cri "k8s.io/cri-api/pkg/apis"
+	criclient "k8s.io/cri-client/pkg"
+
+	"github.com/aws/eks-node-monitoring-agent/pkg/config"
+)
+
+// Metadata identifies a pod in condition messages.
+type Metadata struct {
+	Namespace string
+	Name      string
+}
+
+// String returns the namespaced name of the pod.
+func (m Metadata) String() string {
+	return m.Namespace + "/" + m.Name
+}
+
+// Lister lists the pods known to the CRI.
+type Lister struct {
+	// RuntimeService is the CRI client. It is connected on first use when
+	// nil.
+	RuntimeService cri.RuntimeService
+}
+
+// ByUID returns the metadata of the pods known to the CRI, keyed by pod UID.
+func (l *Lister) ByUID(ctx context.Context) (map[string]Metadata, error) {
+	if l.RuntimeService == nil {
+		runtimeService, err := criclient.NewRemoteRuntimeService(ctx, config.CRIEndpoint, 5*time.Second, nil, false)
+		if err != nil {
+			return nil, err
+		}
+		l.RuntimeService = runtimeService
+	}
+	sandboxes, err := l.RuntimeService.ListPodSandbox(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	pods := make(map[string]Metadata, len(sandboxes))
+	for _, sandbox := range sandboxes {
+		if sandbox.Metadata == nil {
+			continue
+		}
+		pods[sandbox.Metadata.Uid] = Metadata{Namespace: sandbox.Metadata.Namespace, Name: sandbox.Metadata.Name}
+	}
+	return pods, nil
+}
@@ -0,0 +1,50 @@
+package pods
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	cri "k8s.io/cri-api/pkg/apis"
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+// fakeRuntimeService is a stub cri.RuntimeService that returns canned pod
+// sandboxes. Other interface methods inherit the embedded nil and panic if
+// invoked, surfacing unexpected dependencies.
+type fakeRuntimeService struct {
+	cri.RuntimeService
+
+	sandboxes []*runtimeapi.PodSandbox
+	err       error
+	calls     int
+}
+
+func (f *fakeRuntimeService) ListPodSandbox(context.Context, *runtimeapi.PodSandboxFilter) ([]*runtimeapi.PodSandbox, error) {
+	f.calls++
+	return f.sandboxes, f.err
+}
+
+func TestByUID(t *testing.T) {
+	runtimeService := &fakeRuntimeService{sandboxes: []*runtimeapi.PodSandbox{
+		{Metadata: &runtimeapi.PodSandboxMetadata{Uid: "8d2b6f4e-3c1a-4e5f-9a7b-1c2d3e4f5a6b", Namespace: "default", Name: "web-0"}},
+		{Metadata: &runtimeapi.PodSandboxMetadata{Uid: "0f1e2d3c-4b5a-6978-8796-a5b4c3d2e1f0", Namespace: "kube-system", Name: "aws-node-abcde"}},
+		// sandboxes without metadata are skipped.
+		{},
+	}}
+	lister := Lister{RuntimeService: runtimeService}
+
+	pods, err := lister.ByUID(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]Metadata{
+		"8d2b6f4e-3c1a-4e5f-9a7b-1c2d3e4f5a6b": {Namespace: "default", Name: "web-0"},
+		"0f1e2d3c-4b5a-6978-8796-a5b4c3d2e1f0": {Namespace: "kube-system", Name: "aws-node-abcde"},
+	}, pods)
+	assert.Equal(t, "default/web-0", pods["8d2b6f4e-3c1a-4e5f-9a7b-1c2d3e4f5a6b"].String())
+	assert.Equal(t, 1, runtimeService.calls)
+
+	runtimeService.err = errors.New("connection refused")
+	_, err = lister.ByUID(context.Background())
+	assert.ErrorContains(t, err, "connection refused")
+}